@@ -1,36 +1,177 @@
 package config
 
 import (
-	"io/ioutil"
-	"os"
+	"time"
 
+	"github.com/luulethe/quiz/go_common/kafka"
 	"github.com/luulethe/quiz/quiz_lib/db"
-	"gopkg.in/yaml.v2"
 )
 
 // Configuration defines the config
 type Configuration struct {
-	Debug           bool            `yaml:"debug"` // false: info level, true: debug level
-	ProfileAddr     string          `yaml:"pprof"`
-	Listen          string          `yaml:"listen"`
-	MySQL           []db.Config     `yaml:"mysql"`
-	SentryDNS       string          `yaml:"sentry_dns"`
-	GeoIPServerAddr string          `yaml:"geoip_server_addr"`
-	QuizKafka       *ConsumerConfig `yaml:"quiz_kafka"`
+	Debug           bool              `yaml:"debug"` // false: info level, true: debug level
+	ProfileAddr     string            `yaml:"pprof"`
+	Listen          string            `yaml:"listen"`
+	HTTPListen      string            `yaml:"http_listen"` // REST/JSON gateway of the quiz api, disabled if empty
+	MySQL           []db.Config       `yaml:"mysql"`       // the first database is the main one
+	Sharding        ShardingConfig    `yaml:"sharding"`    // places the participants and answers of a quiz
+	SentryDNS       string            `yaml:"sentry_dns" secret:"true"`
+	GeoIPServerAddr string            `yaml:"geoip_server_addr"`
+	QuizKafka       *KafkaConfig      `yaml:"quiz_kafka"`
+	Redis           *RedisConfig      `yaml:"redis"`
+	Outbox          OutboxConfig      `yaml:"outbox"`
+	Scheduler       SchedulerConfig   `yaml:"scheduler"`
+	Auth            AuthConfig        `yaml:"auth"`
+	Admin           AdminConfig       `yaml:"admin"`
+	WSGateway       *WSGatewayConfig  `yaml:"ws_gateway"`
+	Account         *AccountConfig    `yaml:"account"` // every user exists without the account service
+	RateLimit       RateLimitConfig   `yaml:"rate_limit"`
+	Idempotency     IdempotencyConfig `yaml:"idempotency"`
+	Migration       MigrationConfig   `yaml:"migration"`
+	Reload          ReloadConfig      `yaml:"reload"`
 }
 
-type ConsumerConfig struct {
-	Brokers       string `yaml:"brokers"`
-	Version       string `yaml:"version"`
-	ConsumerGroup string `yaml:"consumer_group"`
+type AuthConfig struct {
+	Keys []AuthKey `yaml:"keys"` // HMAC keys of the user token, keep the old key listed until its tokens expired
 }
 
-func (c *Configuration) LoadFromFile(confPath string) error {
-	configContent, err := ioutil.ReadFile(confPath)
-	configContent = []byte(os.ExpandEnv(string(configContent)))
+type AuthKey struct {
+	ID     string `yaml:"id"` // matched against the "kid" header of the token
+	Secret string `yaml:"secret" secret:"true"`
+}
+
+type AdminConfig struct {
+	Keys        []string `yaml:"keys" secret:"true"`         // keys accepted from the x-admin-key metadata of admin commands
+	ServiceKeys []string `yaml:"service_keys" secret:"true"` // keys accepted from the x-service-key metadata of the read-only service commands
+}
+
+type AccountConfig struct {
+	Protocol    string               `yaml:"protocol"` // grpc or http
+	Address     string               `yaml:"address"`  // host:port for grpc, base url for http
+	Timeout     time.Duration        `yaml:"timeout"`
+	PositiveTTL time.Duration        `yaml:"positive_ttl"` // cache ttl of an existing user
+	NegativeTTL time.Duration        `yaml:"negative_ttl"` // cache ttl of a missing user
+	Breaker     CircuitBreakerConfig `yaml:"breaker"`
+}
+
+type CircuitBreakerConfig struct {
+	FailureThreshold int           `yaml:"failure_threshold"` // consecutive failures opening the breaker
+	OpenDuration     time.Duration `yaml:"open_duration"`     // time before a trial call is let through
+	FailOpen         bool          `yaml:"fail_open"`         // true: users exist while the service fails
+}
+
+type RateLimitConfig struct {
+	Backend  string                      `yaml:"backend"`  // memory (per server) or redis (shared), disabled if empty
+	Commands map[string]CommandRateLimit `yaml:"commands"` // keyed by command name, e.g. CMD_JOIN_QUIZ
+}
+
+type CommandRateLimit struct {
+	PerUser RateLimit `yaml:"per_user"`
+	PerQuiz RateLimit `yaml:"per_quiz"`
+}
+
+// RateLimit is a token bucket refilled by rate tokens per second up to burst, a zero rate is unlimited
+type RateLimit struct {
+	Rate  float64 `yaml:"rate"`
+	Burst int     `yaml:"burst"`
+}
+
+type IdempotencyConfig struct {
+	TTL         time.Duration `yaml:"ttl"`           // how long the reply of a request id is replayed
+	InFlightTTL time.Duration `yaml:"in_flight_ttl"` // how long a request id is locked by a request which never completes
+}
+
+// ShardingConfig is the shard map of the participants and answers, a quiz is in the shard quiz_id modulo
+// the number of shards. A shard is an index in the mysql list, several shards may be in the same database
+// so that they can be moved later. Without shards everything is in the main database.
+type ShardingConfig struct {
+	Shards []int `yaml:"shards"`
+}
+
+type MigrationConfig struct {
+	RequireLatest bool `yaml:"require_latest"` // refuse to start when the database schema is behind the migrations
+}
+
+// ReloadConfig controls the hot reload of the config, disabled if the interval is zero. Only debug,
+// rate_limit.commands and the account cache ttls are applied at runtime, other keys need a restart.
+type ReloadConfig struct {
+	Interval time.Duration `yaml:"interval"`  // how often the config files and the redis key are checked
+	RedisKey string        `yaml:"redis_key"` // optional yaml in redis applied over the files
+}
+
+type WSGatewayConfig struct {
+	Listen         string        `yaml:"listen"`
+	QuizServerAddr string        `yaml:"quiz_server_addr"`
+	NodeTTL        time.Duration `yaml:"node_ttl"`      // a node missing its heartbeat for node_ttl is considered dead, see NodeTTL
+	PushInterval   time.Duration `yaml:"push_interval"` // min interval between two leader board pushes of a quiz
+	PushTopN       int32         `yaml:"push_top_n"`
+	ServiceKey     string        `yaml:"service_key" secret:"true"` // one of admin.service_keys, sent with the commands of the gateway itself
+}
+
+type RedisConfig struct {
+	Address  string        `yaml:"address"`
+	Password string        `yaml:"password" secret:"true"`
+	DB       int           `yaml:"db"`
+	PoolSize int           `yaml:"pool_size"`
+	Timeout  time.Duration `yaml:"timeout"`
+}
+
+type KafkaConfig struct {
+	Brokers       string            `yaml:"brokers"`
+	Version       string            `yaml:"version"`
+	ConsumerGroup string            `yaml:"consumer_group"`
+	Topic         string            `yaml:"topic"`
+	Retry         kafka.RetryConfig `yaml:"retry"`
+	Batch         kafka.BatchConfig `yaml:"batch"`
+	Workers       int               `yaml:"workers"` // max quizzes handled in parallel per partition
+}
+
+// OutboxConfig controls the relay of quiz_outbox_tab to kafka, zero values fall back to defaults
+type OutboxConfig struct {
+	PollInterval    time.Duration `yaml:"poll_interval"`
+	BatchSize       int           `yaml:"batch_size"`
+	Retention       time.Duration `yaml:"retention"`
+	CleanupInterval time.Duration `yaml:"cleanup_interval"`
+}
+
+// SchedulerConfig controls the quiz lifecycle scheduler, zero values fall back to defaults
+type SchedulerConfig struct {
+	PollInterval  time.Duration `yaml:"poll_interval"`
+	BatchSize     int           `yaml:"batch_size"`
+	LobbyDuration time.Duration `yaml:"lobby_duration"` // a scheduled quiz opens its lobby this long before start_time
+	ArchiveAfter  time.Duration `yaml:"archive_after"`  // a finished quiz is archived this long after end_time
+}
+
+// DefaultNodeTTL is the ws_gateway.node_ttl when it is not set
+const DefaultNodeTTL = 30 * time.Second
+
+// NodeTTL is the ttl of the gateway nodes in the connection registry, the same for the gateways which heartbeat
+// and the services which read the registry
+func (c *Configuration) NodeTTL() time.Duration {
+	if c.WSGateway == nil || c.WSGateway.NodeTTL <= 0 {
+		return DefaultNodeTTL
+	}
+	return c.WSGateway.NodeTTL
+}
 
-	if err != nil {
-		return err
+// Default returns the typed defaults, the first layer of the Loader
+func Default() *Configuration {
+	return &Configuration{
+		Outbox: OutboxConfig{
+			PollInterval:    200 * time.Millisecond,
+			BatchSize:       500,
+			Retention:       24 * time.Hour,
+			CleanupInterval: 10 * time.Minute,
+		},
+		Scheduler: SchedulerConfig{
+			PollInterval:  time.Second,
+			BatchSize:     100,
+			LobbyDuration: 5 * time.Minute,
+			ArchiveAfter:  7 * 24 * time.Hour,
+		},
+		Idempotency: IdempotencyConfig{
+			TTL:         24 * time.Hour,
+			InFlightTTL: 30 * time.Second,
+		},
 	}
-	return yaml.Unmarshal(configContent, c)
 }
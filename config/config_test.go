@@ -0,0 +1,120 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeTTL(t *testing.T) {
+	assert.Equal(t, DefaultNodeTTL, (&Configuration{}).NodeTTL())
+	assert.Equal(t, DefaultNodeTTL, (&Configuration{WSGateway: &WSGatewayConfig{}}).NodeTTL())
+	assert.Equal(t, time.Minute, (&Configuration{WSGateway: &WSGatewayConfig{NodeTTL: time.Minute}}).NodeTTL())
+}
+
+func TestLoader_Env(t *testing.T) {
+	loader := &Loader{
+		BaseFile: "base.yml",
+		Env: []string{
+			// set by kubernetes for a service named quiz
+			"QUIZ_SERVICE_HOST=10.0.0.1",
+			"QUIZ_PORT=tcp://10.0.0.1:9000",
+			"QUIZCFG_REDIS__PASSWORD=secret",
+			"QUIZCFG_MYSQL__0__ADDRESS=localhost:3306",
+		},
+	}
+	conf, err := loader.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "secret", conf.Redis.Password)
+	require.Len(t, conf.MySQL, 1)
+	assert.Equal(t, "localhost:3306", conf.MySQL[0].DBAddress)
+
+	loader.Env = []string{"QUIZCFG_UNKNOWN=1"}
+	_, err = loader.Load()
+	assert.Error(t, err)
+	loader.Env = []string{}
+	loader.Overrides = []string{"unknown=1"}
+	_, err = loader.Load()
+	assert.Error(t, err)
+}
+
+func writeOverlay(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "overlay.yml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestLoader_MergesMapEntries(t *testing.T) {
+	loader := &Loader{
+		BaseFile: "base.yml",
+		OverlayFile: writeOverlay(t, `
+rate_limit:
+  commands:
+    CMD_JOIN_QUIZ:
+      per_quiz: { rate: 10 }
+    CMD_GET_LEADERBOARD_RANKS:
+      per_user: { rate: 3, burst: 4 }
+`),
+		Remote: "rate_limit: { commands: { CMD_SUBMIT_ANSWER: { per_user: { burst: 7 } } } }",
+		Env:    []string{},
+	}
+	conf, err := loader.Load()
+	require.NoError(t, err)
+
+	// the entries set by base.yml keep the keys the overlay and the remote config don't set
+	commands := conf.RateLimit.Commands
+	assert.Equal(t, CommandRateLimit{
+		PerUser: RateLimit{Rate: 1, Burst: 5},
+		PerQuiz: RateLimit{Rate: 10, Burst: 5000},
+	}, commands["CMD_JOIN_QUIZ"])
+	assert.Equal(t, CommandRateLimit{
+		PerUser: RateLimit{Rate: 2, Burst: 7},
+		PerQuiz: RateLimit{Rate: 5000, Burst: 10000},
+	}, commands["CMD_SUBMIT_ANSWER"])
+	assert.Equal(t, CommandRateLimit{PerUser: RateLimit{Rate: 5, Burst: 10}}, commands["CMD_GET_LEADERBOARD"])
+	assert.Equal(t, CommandRateLimit{PerUser: RateLimit{Rate: 3, Burst: 4}}, commands["CMD_GET_LEADERBOARD_RANKS"])
+	assert.Equal(t, "redis", conf.RateLimit.Backend)
+
+	// lists are replaced, an unknown key of any layer is an error
+	loader.OverlayFile = writeOverlay(t, "sharding: { shards: [0] }")
+	conf, err = loader.Load()
+	require.NoError(t, err)
+	assert.Equal(t, []int{0}, conf.Sharding.Shards)
+	loader.OverlayFile = writeOverlay(t, "rate_limit: { commands: { CMD_JOIN_QUIZ: { per_day: { rate: 1 } } } }")
+	_, err = loader.Load()
+	assert.Error(t, err)
+}
+
+func TestLoader_WithoutMySQL(t *testing.T) {
+	// e.g. the ws_gateway
+	conf, err := (&Loader{BaseFile: "base.yml", Env: []string{}}).Load()
+	require.NoError(t, err)
+	assert.Empty(t, conf.MySQL)
+
+	loader := &Loader{BaseFile: "base.yml", Env: []string{}, Overrides: []string{
+		"mysql.0.address=localhost:3306", "sharding.shards=[0,1]",
+	}}
+	_, err = loader.Load()
+	assert.Error(t, err)
+}
+
+func TestDefaultOverlayFile(t *testing.T) {
+	deploy, ok := os.LookupEnv("DEPLOY")
+	defer func() {
+		if ok {
+			os.Setenv("DEPLOY", deploy)
+		} else {
+			os.Unsetenv("DEPLOY")
+		}
+	}()
+
+	os.Unsetenv("DEPLOY")
+	assert.Equal(t, "", defaultOverlayFile())
+	os.Setenv("DEPLOY", "dev")
+	assert.Equal(t, "config/dev.yml", defaultOverlayFile())
+}
@@ -0,0 +1,368 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/luulethe/quiz/quiz_lib/pb/gen"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// EnvPrefix selects the environment variables overriding the config, the levels of the key are
+	// separated by "__" and a number indexes a list, e.g. QUIZCFG_REDIS__PASSWORD or QUIZCFG_MYSQL__0__PASSWORD.
+	// It isn't QUIZ_, kubernetes sets QUIZ_SERVICE_HOST, QUIZ_PORT... for a service named quiz.
+	EnvPrefix        = "QUIZCFG_"
+	envPathSeparator = "__"
+
+	maskedValue = "******"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Loader builds the Configuration from layered sources, each one overriding the previous ones:
+// the typed defaults, the base file, the overlay file of the environment, the remote yaml, the QUIZCFG_
+// environment variables then the overrides of the command line. Every source is strict, an unknown key
+// is an error, and the result is validated.
+type Loader struct {
+	BaseFile    string   // shared by every environment, skipped if empty
+	OverlayFile string   // the environment, e.g. config/dev.yml, skipped if empty
+	Remote      string   // yaml of the reload.redis_key, skipped if empty
+	Env         []string // KEY=value pairs, os.Environ() when nil
+	Overrides   []string // key.path=value, e.g. redis.pool_size=100
+}
+
+func (l *Loader) Load() (*Configuration, error) {
+	conf := Default()
+	tree := map[interface{}]interface{}{}
+	for _, file := range []string{l.BaseFile, l.OverlayFile} {
+		if file == "" {
+			continue
+		}
+		if err := mergeFile(tree, file); err != nil {
+			return nil, err
+		}
+	}
+	if l.Remote != "" {
+		if err := mergeYAML(tree, []byte(l.Remote)); err != nil {
+			return nil, fmt.Errorf("remote config: %w", err)
+		}
+	}
+	if err := decodeTree(conf, tree); err != nil {
+		return nil, err
+	}
+
+	env := l.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	for _, pair := range env {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || !strings.HasPrefix(parts[0], EnvPrefix) {
+			continue
+		}
+		path := strings.Split(strings.TrimPrefix(parts[0], EnvPrefix), envPathSeparator)
+		if err := conf.Set(path, parts[1]); err != nil {
+			return nil, fmt.Errorf("environment %s: %w", parts[0], err)
+		}
+	}
+
+	for _, override := range l.Overrides {
+		parts := strings.SplitN(override, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("override %q must be of form key.path=value", override)
+		}
+		if err := conf.Set(strings.Split(parts[0], "."), parts[1]); err != nil {
+			return nil, fmt.Errorf("override %s: %w", parts[0], err)
+		}
+	}
+	if err := conf.Validate(); err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
+
+// Validate checks the values which can't be decoded wrong but are still invalid. The sources a command
+// needs, e.g. mysql which the ws_gateway doesn't, are checked when it connects to them.
+func (c *Configuration) Validate() error {
+	for shard, database := range c.Sharding.Shards {
+		if len(c.MySQL) > 0 && (database < 0 || database >= len(c.MySQL)) {
+			return fmt.Errorf("sharding.shards: shard %d is on database %d, only %d mysql databases are configured",
+				shard, database, len(c.MySQL))
+		}
+	}
+
+	switch c.RateLimit.Backend {
+	case "", "memory", "redis":
+	default:
+		return fmt.Errorf("rate_limit.backend: unknown backend %q", c.RateLimit.Backend)
+	}
+	for command, limits := range c.RateLimit.Commands {
+		if _, ok := pb.Command_value[command]; !ok {
+			return fmt.Errorf("rate_limit.commands: unknown command %q", command)
+		}
+		for _, limit := range []RateLimit{limits.PerUser, limits.PerQuiz} {
+			if limit.Rate < 0 || limit.Burst < 0 {
+				return fmt.Errorf("rate_limit.commands.%s: negative rate or burst", command)
+			}
+		}
+	}
+
+	if c.Account != nil && (c.Account.PositiveTTL < 0 || c.Account.NegativeTTL < 0) {
+		return errors.New("account: negative ttl")
+	}
+	if c.WSGateway != nil && c.WSGateway.NodeTTL < 0 {
+		return errors.New("ws_gateway.node_ttl: negative ttl")
+	}
+	if c.Reload.Interval < 0 {
+		return errors.New("reload.interval: negative interval")
+	}
+	return nil
+}
+
+// mergeFile merges the yaml of the file into tree, see mergeTree
+func mergeFile(tree map[interface{}]interface{}, path string) error {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := mergeYAML(tree, []byte(os.ExpandEnv(string(content)))); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+func mergeYAML(tree map[interface{}]interface{}, content []byte) error {
+	layer := map[interface{}]interface{}{}
+	if err := yaml.Unmarshal(content, &layer); err != nil {
+		return err
+	}
+	mergeTree(tree, layer)
+	return nil
+}
+
+// mergeTree merges layer into tree: maps, i.e. structs and maps of the config, are merged key by key
+// at every level, any other value replaces the previous one, lists included
+func mergeTree(tree map[interface{}]interface{}, layer map[interface{}]interface{}) {
+	for key, value := range layer {
+		previous, ok := tree[key].(map[interface{}]interface{})
+		next, isMap := value.(map[interface{}]interface{})
+		if ok && isMap {
+			mergeTree(previous, next)
+			continue
+		}
+		tree[key] = value
+	}
+}
+
+// decodeTree decodes the merged layers over conf once, an unknown key of any layer is an error
+func decodeTree(conf *Configuration, tree map[interface{}]interface{}) error {
+	content, err := yaml.Marshal(tree)
+	if err != nil {
+		return err
+	}
+	if err := yaml.UnmarshalStrict(content, conf); err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	return nil
+}
+
+// Flags are the config flags of a command
+type Flags struct {
+	Loader
+	PrintConfig bool
+}
+
+// RegisterFlags adds -base-config, -config, -set and -print-config to the flag set
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	flags := &Flags{}
+	fs.StringVar(&flags.BaseFile, "base-config", "config/base.yml", "set base config file, shared by every environment")
+	fs.StringVar(&flags.OverlayFile, "config", defaultOverlayFile(), "set config file, config/$DEPLOY.yml by default")
+	fs.Var((*overrideFlag)(&flags.Overrides), "set", "override a config key, e.g. -set redis.pool_size=100, repeatable")
+	fs.BoolVar(&flags.PrintConfig, "print-config", false, "print the config with masked secrets then exit")
+	return flags
+}
+
+// defaultOverlayFile is the file of the DEPLOY environment, none without DEPLOY so that base.yml is used alone
+func defaultOverlayFile() string {
+	deploy := os.Getenv("DEPLOY")
+	if deploy == "" {
+		return ""
+	}
+	return fmt.Sprintf("config/%s.yml", deploy)
+}
+
+type overrideFlag []string
+
+func (f *overrideFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *overrideFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// Set sets the key of the path to the value. Struct fields are matched by their yaml name ignoring case,
+// a number indexes a list, the index past the end appends. Lists, maps and structs are given in yaml, e.g. [0, 1].
+func (c *Configuration) Set(path []string, value string) error {
+	return setValue(reflect.ValueOf(c).Elem(), path, value)
+}
+
+func setValue(v reflect.Value, path []string, value string) error {
+	if len(path) == 0 {
+		return decodeValue(v, value)
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return setValue(v.Elem(), path, value)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if name := yamlName(v.Type().Field(i)); name != "" && strings.EqualFold(name, path[0]) {
+				return setValue(v.Field(i), path[1:], value)
+			}
+		}
+	case reflect.Slice:
+		index, err := strconv.Atoi(path[0])
+		if err != nil || index < 0 || index > v.Len() {
+			return fmt.Errorf("invalid index %q of a list of %d", path[0], v.Len())
+		}
+		if index == v.Len() {
+			v.Set(reflect.Append(v, reflect.Zero(v.Type().Elem())))
+		}
+		return setValue(v.Index(index), path[1:], value)
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			break
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		key := reflect.ValueOf(path[0]).Convert(v.Type().Key())
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if existing := v.MapIndex(key); existing.IsValid() {
+			elem.Set(existing)
+		}
+		if err := setValue(elem, path[1:], value); err != nil {
+			return err
+		}
+		v.SetMapIndex(key, elem)
+		return nil
+	}
+	return fmt.Errorf("unknown key %q", path[0])
+}
+
+func decodeValue(v reflect.Value, value string) (err error) {
+	if v.Type() == durationType {
+		duration, err := time.ParseDuration(value)
+		if err == nil {
+			v.SetInt(int64(duration))
+		}
+		return err
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		var b bool
+		if b, err = strconv.ParseBool(value); err == nil {
+			v.SetBool(b)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		if i, err = strconv.ParseInt(value, 10, v.Type().Bits()); err == nil {
+			v.SetInt(i)
+		}
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		if f, err = strconv.ParseFloat(value, v.Type().Bits()); err == nil {
+			v.SetFloat(f)
+		}
+	default:
+		decoded := reflect.New(v.Type())
+		if err = yaml.UnmarshalStrict([]byte(value), decoded.Interface()); err == nil {
+			v.Set(decoded.Elem())
+		}
+	}
+	return err
+}
+
+// yamlName is the key of the field, empty when the field isn't decoded
+func yamlName(field reflect.StructField) string {
+	if field.PkgPath != "" {
+		return ""
+	}
+	name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return strings.ToLower(field.Name)
+	}
+	return name
+}
+
+// WriteMasked writes the config as yaml, the values of the fields tagged secret:"true" are masked
+func (c *Configuration) WriteMasked(w io.Writer) error {
+	data, err := yaml.Marshal(maskedTree(reflect.ValueOf(c).Elem(), false))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func maskedTree(v reflect.Value, secret bool) interface{} {
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String()
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return maskedTree(v.Elem(), secret)
+	case reflect.Struct:
+		tree := yaml.MapSlice{}
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if name := yamlName(field); name != "" {
+				tree = append(tree, yaml.MapItem{Key: name, Value: maskedTree(v.Field(i), secret || field.Tag.Get("secret") == "true")})
+			}
+		}
+		return tree
+	case reflect.Slice, reflect.Array:
+		items := make([]interface{}, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			items = append(items, maskedTree(v.Index(i), secret))
+		}
+		return items
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		tree := yaml.MapSlice{}
+		for _, key := range keys {
+			tree = append(tree, yaml.MapItem{Key: key.Interface(), Value: maskedTree(v.MapIndex(key), secret)})
+		}
+		return tree
+	case reflect.String:
+		if secret && v.Len() > 0 {
+			return maskedValue
+		}
+	}
+	return v.Interface()
+}
@@ -4,12 +4,15 @@ go 1.16
 
 require (
 	github.com/Shopify/sarama v1.28.0
+	github.com/alicebob/miniredis/v2 v2.30.0
 	github.com/gavv/httpexpect/v2 v2.16.0
 	github.com/getsentry/sentry-go v0.10.0
 	github.com/gin-gonic/gin v1.4.0
 	github.com/go-redis/redis v6.15.9+incompatible
 	github.com/go-sql-driver/mysql v1.5.0
+	github.com/golang-jwt/jwt/v4 v4.5.0
 	github.com/google/uuid v1.2.0
+	github.com/gorilla/websocket v1.5.0
 	github.com/grpc-ecosystem/go-grpc-middleware v1.0.1-0.20190118093823-f849b5445de4
 	github.com/patrickmn/go-cache v2.1.0+incompatible
 	github.com/pkg/errors v0.9.1
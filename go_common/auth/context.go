@@ -0,0 +1,16 @@
+package auth
+
+import "context"
+
+type userIDKey struct{}
+
+// WithUserID returns a context carrying the authenticated user id
+func WithUserID(ctx context.Context, userID int64) context.Context {
+	return context.WithValue(ctx, userIDKey{}, userID)
+}
+
+// UserIDFromContext returns the authenticated user id, false when the caller has not been authenticated
+func UserIDFromContext(ctx context.Context) (int64, bool) {
+	userID, ok := ctx.Value(userIDKey{}).(int64)
+	return userID, ok && userID > 0
+}
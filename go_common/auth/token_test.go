@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sign(t *testing.T, claims jwt.RegisteredClaims, kid string, secret string) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString([]byte(secret))
+	require.NoError(t, err)
+	return signed
+}
+
+func TestTokenVerifier(t *testing.T) {
+	verifier := NewTokenVerifier(Key{ID: "old", Secret: "old-secret"}, Key{ID: "new", Secret: "new-secret"})
+	valid := jwt.RegisteredClaims{Subject: "42", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))}
+
+	userID, err := verifier.Verify(sign(t, valid, "new", "new-secret"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), userID)
+	userID, err = verifier.Verify(sign(t, valid, "", "old-secret"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), userID)
+
+	expired := jwt.RegisteredClaims{Subject: "42", ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute))}
+	_, err = verifier.Verify(sign(t, expired, "new", "new-secret"))
+	assert.Equal(t, ErrInvalidToken, err)
+
+	_, err = verifier.Verify(sign(t, jwt.RegisteredClaims{Subject: "42"}, "new", "new-secret"))
+	assert.Equal(t, ErrInvalidToken, err)
+	_, err = verifier.Verify(sign(t, jwt.RegisteredClaims{Subject: "42"}, "", "new-secret"))
+	assert.Equal(t, ErrInvalidToken, err)
+
+	// signed with another key than its kid, and with an unknown kid
+	_, err = verifier.Verify(sign(t, valid, "old", "new-secret"))
+	assert.Equal(t, ErrInvalidToken, err)
+	_, err = verifier.Verify(sign(t, valid, "unknown", "new-secret"))
+	assert.Equal(t, ErrInvalidToken, err)
+
+	invalidSubject := jwt.RegisteredClaims{Subject: "0", ExpiresAt: valid.ExpiresAt}
+	_, err = verifier.Verify(sign(t, invalidSubject, "new", "new-secret"))
+	assert.Equal(t, ErrInvalidToken, err)
+}
@@ -30,13 +30,19 @@ type Reader interface {
 	LLen(key string) (int64, error)
 	LIndex(key string, index int64) (string, error)
 	LRange(key string, from int64, to int64) ([]string, error)
+
+	// Sorted Set
+	ZCard(key string) (int64, error)
+	ZScore(key, member string) (float64, error)
+	ZRevRank(key, member string) (int64, error)
+	ZRevRangeWithScores(key string, start, stop int64) ([]redis.Z, error)
 }
 
 type Writer interface {
 	// Simple K/Vs
 	// Set will set key to value, zero expiration means the key has no expiration time.
-	Set(key, value string, expire time.Duration) (err error)
-	MSet(pairs map[string]string, expiration time.Duration) (err error)
+	Set(key string, value interface{}, expire time.Duration) (err error)
+	MSet(pairs map[string]interface{}, expiration time.Duration) (err error)
 	Incr(key string) (value int64, err error)
 	SetNX(key string, value interface{}, expiration time.Duration) (bool, error)
 	GetSet(key string, value interface{}) (string, error)
@@ -65,6 +71,10 @@ type Writer interface {
 	BRPopLPush(src, dest string, timeout time.Duration) (string, error)
 	LRem(key string, count int64, val string) (int64, error)
 
+	// Sorted Set
+	ZAdd(key string, members ...redis.Z) (int64, error)
+	ZRem(key string, members ...interface{}) (int64, error)
+
 	// Eval
 	Eval(script string, key, args []string) (string, error)
 }
@@ -26,6 +26,15 @@ type WrapperConfig struct {
 	Encoder    Encoder
 	SliceInput bool
 	Compress   bool
+	// ResultExpire overrides Expire by the result of a single key, e.g. to cache misses for a shorter time
+	ResultExpire func(result interface{}) time.Duration
+}
+
+func (c *WrapperConfig) getExpire(result interface{}) time.Duration {
+	if c.ResultExpire != nil {
+		return c.ResultExpire(result)
+	}
+	return c.Expire
 }
 
 func (c *WrapperConfig) GetEncoder() Encoder {
@@ -136,7 +145,7 @@ func setToCache(ctx context.Context, config *WrapperConfig, key interface{}, res
 			return err
 		}
 	}
-	return cacheInstance.Set(cacheKey, resultString, config.Expire)
+	return cacheInstance.Set(cacheKey, resultString, config.getExpire(result))
 }
 
 func mSetToCache(ctx context.Context, config *WrapperConfig, pairs map[interface{}]interface{}) (err error) {
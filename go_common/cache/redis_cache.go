@@ -279,6 +279,30 @@ func (c RedisCache) LRange(key string, start int64, stop int64) ([]string, error
 	return c.client.LRange(key, start, stop).Result()
 }
 
+func (c RedisCache) ZAdd(key string, members ...redis.Z) (int64, error) {
+	return c.client.ZAdd(key, members...).Result()
+}
+
+func (c RedisCache) ZRem(key string, members ...interface{}) (int64, error) {
+	return c.client.ZRem(key, members...).Result()
+}
+
+func (c RedisCache) ZCard(key string) (int64, error) {
+	return c.client.ZCard(key).Result()
+}
+
+func (c RedisCache) ZScore(key, member string) (float64, error) {
+	return c.client.ZScore(key, member).Result()
+}
+
+func (c RedisCache) ZRevRank(key, member string) (int64, error) {
+	return c.client.ZRevRank(key, member).Result()
+}
+
+func (c RedisCache) ZRevRangeWithScores(key string, start, stop int64) ([]redis.Z, error) {
+	return c.client.ZRevRangeWithScores(key, start, stop).Result()
+}
+
 func (c RedisCache) Eval(script string, keys, args []string) (string, error) {
 	val, err := c.client.Eval(script, keys, args).Result()
 	if err != nil {
@@ -0,0 +1,75 @@
+package kafka
+
+import (
+	"context"
+	"time"
+
+	"github.com/Shopify/sarama"
+)
+
+const (
+	defaultBatchWindow  = 100 * time.Millisecond
+	defaultBatchMaxSize = 1000
+)
+
+// BatchConfig controls how long and how many messages of a claim are collected into one batch
+type BatchConfig struct {
+	Window  time.Duration `yaml:"window"`
+	MaxSize int           `yaml:"max_size"`
+}
+
+type BatchHandler func(ctx context.Context, messages []*sarama.ConsumerMessage) error
+
+// ConsumeBatches collects messages of the claim until the window since the first message elapses
+// or MaxSize is reached, then calls handler with the batch.
+// The batch is only marked after handler succeeds, the claim stops on error and its messages are consumed again.
+func ConsumeBatches(
+	ctx context.Context, session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim,
+	config BatchConfig, handler BatchHandler,
+) error {
+	window := config.Window
+	if window <= 0 {
+		window = defaultBatchWindow
+	}
+	maxSize := config.MaxSize
+	if maxSize <= 0 {
+		maxSize = defaultBatchMaxSize
+	}
+
+	batch := make([]*sarama.ConsumerMessage, 0, maxSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := handler(ctx, batch)
+		if err != nil {
+			return err
+		}
+		session.MarkMessage(batch[len(batch)-1], "")
+		batch = batch[:0]
+		return nil
+	}
+
+	var timeout <-chan time.Time
+	for {
+		select {
+		case message, ok := <-claim.Messages():
+			if !ok {
+				return flush()
+			}
+			if len(batch) == 0 {
+				timeout = time.After(window)
+			}
+			batch = append(batch, message)
+			if len(batch) < maxSize {
+				continue
+			}
+		case <-timeout:
+		}
+
+		timeout = nil
+		if err := flush(); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMessages(count int) []*sarama.ConsumerMessage {
+	messages := make([]*sarama.ConsumerMessage, 0, count)
+	for i := 0; i < count; i++ {
+		messages = append(messages, &sarama.ConsumerMessage{Topic: "event", Offset: int64(i)})
+	}
+	return messages
+}
+
+func offsets(messages []*sarama.ConsumerMessage) []int64 {
+	result := make([]int64, 0, len(messages))
+	for _, message := range messages {
+		result = append(result, message.Offset)
+	}
+	return result
+}
+
+func TestConsumeBatches_MaxSize(t *testing.T) {
+	ctx := context.Background()
+	session := &fakeSession{}
+	var batches [][]int64
+	err := ConsumeBatches(ctx, session, newFakeClaim(newMessages(5)...), BatchConfig{Window: time.Hour, MaxSize: 2},
+		func(ctx context.Context, messages []*sarama.ConsumerMessage) error {
+			batches = append(batches, offsets(messages))
+			return nil
+		})
+	require.NoError(t, err)
+
+	// the last batch is flushed when the claim ends, each batch is marked at its last message
+	assert.Equal(t, [][]int64{{0, 1}, {2, 3}, {4}}, batches)
+	assert.Equal(t, []int64{1, 3, 4}, session.offsets())
+}
+
+func TestConsumeBatches_HandlerFailure(t *testing.T) {
+	ctx := context.Background()
+	session := &fakeSession{}
+	calls := 0
+	err := ConsumeBatches(ctx, session, newFakeClaim(newMessages(5)...), BatchConfig{Window: time.Hour, MaxSize: 2},
+		func(ctx context.Context, messages []*sarama.ConsumerMessage) error {
+			calls++
+			if messages[0].Offset == 2 {
+				return errors.New("handle failed")
+			}
+			return nil
+		})
+
+	// the claim stops at the failed batch, which is not marked
+	assert.Error(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, []int64{1}, session.offsets())
+}
+
+func TestConsumeBatches_Window(t *testing.T) {
+	ctx := context.Background()
+	session := &fakeSession{}
+	claim := &fakeClaim{messages: make(chan *sarama.ConsumerMessage)}
+	batches := make(chan []int64, 2)
+	done := make(chan error, 1)
+	go func() {
+		done <- ConsumeBatches(ctx, session, claim, BatchConfig{Window: 20 * time.Millisecond, MaxSize: 100},
+			func(ctx context.Context, messages []*sarama.ConsumerMessage) error {
+				batches <- offsets(messages)
+				return nil
+			})
+	}()
+
+	// the batch is handled once the window since its first message elapses
+	messages := newMessages(3)
+	claim.messages <- messages[0]
+	claim.messages <- messages[1]
+	select {
+	case batch := <-batches:
+		assert.Equal(t, []int64{0, 1}, batch)
+	case <-time.After(time.Second):
+		require.Fail(t, "the batch is not flushed after the window")
+	}
+	assert.Eventually(t, func() bool { return len(session.offsets()) == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, []int64{1}, session.offsets())
+
+	claim.messages <- messages[2]
+	close(claim.messages)
+	require.NoError(t, <-done)
+	assert.Equal(t, []int64{2}, <-batches)
+	assert.Equal(t, []int64{1, 2}, session.offsets())
+}
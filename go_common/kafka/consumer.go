@@ -12,6 +12,15 @@ import (
 )
 
 func NewKafkaConsumerClient(brokers, consumerGroup, ver string) (sarama.ConsumerGroup, error) {
+	return newKafkaConsumerClient(brokers, consumerGroup, ver, sarama.OffsetNewest)
+}
+
+// NewKafkaReplayConsumerClient starts from the oldest offset when the consumer group has no committed offset
+func NewKafkaReplayConsumerClient(brokers, consumerGroup, ver string) (sarama.ConsumerGroup, error) {
+	return newKafkaConsumerClient(brokers, consumerGroup, ver, sarama.OffsetOldest)
+}
+
+func newKafkaConsumerClient(brokers, consumerGroup, ver string, initialOffset int64) (sarama.ConsumerGroup, error) {
 	if len(brokers) == 0 {
 		return nil, errors.New("no Kafka bootstrap brokers defined")
 	}
@@ -26,7 +35,7 @@ func NewKafkaConsumerClient(brokers, consumerGroup, ver string) (sarama.Consumer
 	conf := sarama.NewConfig()
 	conf.Version = version
 	conf.Consumer.Group.Rebalance.Strategy = sarama.BalanceStrategyRange
-	conf.Consumer.Offsets.Initial = sarama.OffsetNewest
+	conf.Consumer.Offsets.Initial = initialOffset
 	return sarama.NewConsumerGroup(strings.Split(brokers, ","), consumerGroup, conf)
 }
 
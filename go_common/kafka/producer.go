@@ -16,12 +16,17 @@ func NewSyncKafkaProducer(ctx context.Context, addr string) (sarama.SyncProducer
 	return sarama.NewSyncProducer(brokers, config)
 }
 
+// NewAsyncKafkaProducer keeps the order of the messages of a partition: the producer is idempotent
+// with a single request in flight per broker, so that a retried message is never written after the next ones
 func NewAsyncKafkaProducer(
 	ctx context.Context, addr string, onSuccess func(*sarama.ProducerMessage), onError func(error),
 ) (sarama.AsyncProducer, error) {
 	config := sarama.NewConfig()
 	config.Producer.Return.Successes = true
 	config.Producer.Return.Errors = true
+	config.Producer.Idempotent = true
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	config.Net.MaxOpenRequests = 1
 	config.Version = sarama.V1_1_0_0
 	brokers := strings.Split(addr, ",")
 	producer, err := sarama.NewAsyncProducer(brokers, config)
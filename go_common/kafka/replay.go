@@ -0,0 +1,97 @@
+package kafka
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/luulethe/quiz/go_common/log"
+)
+
+// ReplayDeadLetters publishes the messages of the dead letter topic back to their original topic,
+// with the retry headers removed so that they get all retries again.
+// It returns the number of replayed messages once no message arrives for idleTimeout.
+func ReplayDeadLetters(
+	ctx context.Context, group sarama.ConsumerGroup, deadLetterTopic string, producer sarama.SyncProducer,
+	idleTimeout time.Duration,
+) (int64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	handler := &replayHandler{ctx: ctx, producer: producer, activity: make(chan struct{}, 1)}
+	consumeErr := make(chan error, 1)
+	go func() {
+		for ctx.Err() == nil {
+			if err := group.Consume(ctx, []string{deadLetterTopic}, handler); err != nil {
+				consumeErr <- err
+				return
+			}
+		}
+	}()
+
+	idleTimer := time.NewTimer(idleTimeout)
+	defer idleTimer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return atomic.LoadInt64(&handler.count), ctx.Err()
+		case err := <-consumeErr:
+			return atomic.LoadInt64(&handler.count), err
+		case <-handler.activity:
+			if !idleTimer.Stop() {
+				<-idleTimer.C
+			}
+			idleTimer.Reset(idleTimeout)
+		case <-idleTimer.C:
+			return atomic.LoadInt64(&handler.count), nil
+		}
+	}
+}
+
+type replayHandler struct {
+	ctx      context.Context
+	producer sarama.SyncProducer
+	activity chan struct{}
+	count    int64
+}
+
+func (h *replayHandler) Setup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (h *replayHandler) Cleanup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (h *replayHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for message := range claim.Messages() {
+		select {
+		case h.activity <- struct{}{}:
+		default:
+		}
+
+		topic := headerValue(message.Headers, HeaderOriginalTopic)
+		if topic == "" {
+			log.Errorff(h.ctx, "ReplayDeadLetters|skip|missing original topic|partition:%d|offset:%d",
+				message.Partition, message.Offset)
+			session.MarkMessage(message, "")
+			continue
+		}
+
+		_, _, err := h.producer.SendMessage(&sarama.ProducerMessage{
+			Topic:   topic,
+			Key:     sarama.ByteEncoder(message.Key),
+			Value:   sarama.ByteEncoder(message.Value),
+			Headers: copyHeaders(message.Headers),
+		})
+		if err != nil {
+			// the claim stops here, the message is replayed again by the next run
+			log.Errorff(h.ctx, "ReplayDeadLetters|topic:%s|err:%v", topic, err)
+			return err
+		}
+		atomic.AddInt64(&h.count, 1)
+		session.MarkMessage(message, "")
+	}
+	return nil
+}
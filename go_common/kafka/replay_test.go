@@ -0,0 +1,121 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSession records the offsets marked
+type fakeSession struct {
+	sarama.ConsumerGroupSession
+	mutex  sync.Mutex
+	marked []int64
+}
+
+func (s *fakeSession) MarkMessage(message *sarama.ConsumerMessage, metadata string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.marked = append(s.marked, message.Offset)
+}
+
+func (s *fakeSession) offsets() []int64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]int64(nil), s.marked...)
+}
+
+type fakeClaim struct {
+	sarama.ConsumerGroupClaim
+	messages chan *sarama.ConsumerMessage
+}
+
+func (c *fakeClaim) Messages() <-chan *sarama.ConsumerMessage {
+	return c.messages
+}
+
+// newFakeClaim returns a claim of messages, which is closed after them
+func newFakeClaim(messages ...*sarama.ConsumerMessage) *fakeClaim {
+	claim := &fakeClaim{messages: make(chan *sarama.ConsumerMessage, len(messages))}
+	for _, message := range messages {
+		claim.messages <- message
+	}
+	close(claim.messages)
+	return claim
+}
+
+// fakeGroup consumes the claim once, the next sessions have no message until ctx is done
+type fakeGroup struct {
+	sarama.ConsumerGroup
+	session *fakeSession
+	claim   *fakeClaim
+}
+
+func (g *fakeGroup) Consume(ctx context.Context, topics []string, handler sarama.ConsumerGroupHandler) error {
+	if g.claim == nil {
+		<-ctx.Done()
+		return nil
+	}
+	claim := g.claim
+	g.claim = nil
+	return handler.ConsumeClaim(g.session, claim)
+}
+
+func TestReplayDeadLetters(t *testing.T) {
+	ctx := context.Background()
+	producer := &fakeSyncProducer{}
+	policy := newTestRetryPolicy(producer, "event_dlq")
+	source := &sarama.ConsumerMessage{
+		Topic: "event", Offset: 42, Key: []byte("7"), Value: []byte("payload"),
+		Headers: []*sarama.RecordHeader{{Key: []byte("trace"), Value: []byte("abc")}},
+	}
+	require.NoError(t, policy.Forward(ctx, source, NonRetryable(errors.New("malformed"))))
+	deadLetter := consumed(producer.messages()[0], 0)
+	unknown := &sarama.ConsumerMessage{Topic: "event_dlq", Offset: 1, Value: []byte("unknown")}
+
+	session := &fakeSession{}
+	group := &fakeGroup{session: session, claim: newFakeClaim(deadLetter, unknown)}
+	count, err := ReplayDeadLetters(ctx, group, "event_dlq", producer, 20*time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// the message gets back to its topic without the retry headers, a message without origin is skipped
+	sent := producer.messages()
+	require.Len(t, sent, 2)
+	replayed := sent[1]
+	assert.Equal(t, "event", replayed.Topic)
+	key, _ := replayed.Key.Encode()
+	value, _ := replayed.Value.Encode()
+	assert.Equal(t, []byte("7"), key)
+	assert.Equal(t, []byte("payload"), value)
+	assert.Equal(t, []sarama.RecordHeader{{Key: []byte("trace"), Value: []byte("abc")}}, replayed.Headers)
+	assert.Equal(t, []int64{0, 1}, session.offsets())
+
+	// the replayed message gets all retries again
+	require.NoError(t, policy.Forward(ctx, consumed(replayed, 43), errors.New("handle failed")))
+	assert.Equal(t, "event_retry_1", producer.messages()[2].Topic)
+}
+
+func TestReplayDeadLetters_SendFailure(t *testing.T) {
+	ctx := context.Background()
+	producer := &fakeSyncProducer{fails: 1}
+	message := &sarama.ConsumerMessage{
+		Topic: "event_dlq", Offset: 5, Value: []byte("payload"),
+		Headers: []*sarama.RecordHeader{{Key: []byte(HeaderOriginalTopic), Value: []byte("event")}},
+	}
+
+	// the message isn't marked, so that the next run replays it
+	session := &fakeSession{}
+	group := &fakeGroup{session: session, claim: newFakeClaim(message)}
+	count, err := ReplayDeadLetters(ctx, group, "event_dlq", producer, time.Second)
+	assert.Error(t, err)
+	assert.Zero(t, count)
+	assert.Empty(t, session.offsets())
+	assert.Empty(t, producer.messages())
+}
@@ -0,0 +1,219 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/luulethe/quiz/go_common/log"
+)
+
+// Headers attached to messages forwarded to a retry topic or the dead letter topic
+const (
+	HeaderOriginalTopic     = "x-original-topic"
+	HeaderOriginalPartition = "x-original-partition"
+	HeaderOriginalOffset    = "x-original-offset"
+	HeaderError             = "x-error"
+	HeaderAttempt           = "x-attempt"
+
+	forwardBackoff    = time.Second
+	maxForwardBackoff = time.Minute
+)
+
+// RetryTopic is consumed again after Delay since the message was forwarded to it
+type RetryTopic struct {
+	Topic string        `yaml:"topic"`
+	Delay time.Duration `yaml:"delay"`
+}
+
+// RetryConfig defines the retry topics in the order they are tried, and the final dead letter topic.
+// Failed messages are dropped if DeadLetterTopic is empty.
+type RetryConfig struct {
+	RetryTopics     []RetryTopic `yaml:"retry_topics"`
+	DeadLetterTopic string       `yaml:"dead_letter_topic"`
+}
+
+type MessageHandler func(ctx context.Context, message *sarama.ConsumerMessage) error
+
+type nonRetryableError struct {
+	error
+}
+
+func (e nonRetryableError) Unwrap() error {
+	return e.error
+}
+
+// NonRetryable marks err so that the message is sent to the dead letter topic directly, e.g. a malformed message
+func NonRetryable(err error) error {
+	return nonRetryableError{err}
+}
+
+// RetryPolicy forwards failed messages to the next retry topic, then to the dead letter topic
+type RetryPolicy struct {
+	config   RetryConfig
+	producer sarama.SyncProducer
+	delays   map[string]time.Duration
+}
+
+func NewRetryPolicy(config RetryConfig, producer sarama.SyncProducer) *RetryPolicy {
+	delays := map[string]time.Duration{}
+	for _, retryTopic := range config.RetryTopics {
+		delays[retryTopic.Topic] = retryTopic.Delay
+	}
+	return &RetryPolicy{config: config, producer: producer, delays: delays}
+}
+
+// Topics returns the retry topics, which must be consumed by the same handler as the source topics
+func (p *RetryPolicy) Topics() []string {
+	topics := make([]string, 0, len(p.config.RetryTopics))
+	for _, retryTopic := range p.config.RetryTopics {
+		topics = append(topics, retryTopic.Topic)
+	}
+	return topics
+}
+
+// Wrap returns a handler which delays messages of retry topics, and forwards messages failed by handler.
+// The returned handler only fails if ctx is done before the failed message is forwarded,
+// the message must not be marked in that case.
+func (p *RetryPolicy) Wrap(handler MessageHandler) MessageHandler {
+	return func(ctx context.Context, message *sarama.ConsumerMessage) error {
+		if delay, ok := p.delays[message.Topic]; ok {
+			err := sleepUntil(ctx, message.Timestamp.Add(delay))
+			if err != nil {
+				return err
+			}
+		}
+
+		handleErr := handler(ctx, message)
+		if handleErr == nil {
+			return nil
+		}
+
+		return p.Forward(ctx, message, handleErr)
+	}
+}
+
+// Forward sends the failed message to the next retry topic, or the dead letter topic after all retries.
+// It only fails if ctx is done before the message is forwarded.
+func (p *RetryPolicy) Forward(ctx context.Context, message *sarama.ConsumerMessage, handleErr error) error {
+	attempt := headerInt(message.Headers, HeaderAttempt) + 1
+	topic := p.config.DeadLetterTopic
+	if attempt <= len(p.config.RetryTopics) && !errors.As(handleErr, &nonRetryableError{}) {
+		topic = p.config.RetryTopics[attempt-1].Topic
+	}
+	if topic == "" {
+		log.Errorff(ctx, "RetryPolicy|drop|topic:%s|partition:%d|offset:%d|err:%v",
+			message.Topic, message.Partition, message.Offset, handleErr)
+		return nil
+	}
+
+	return p.forward(ctx, newForwardMessage(topic, message, handleErr, attempt))
+}
+
+// IsRetryTopic reports whether topic is one of the retry topics
+func (p *RetryPolicy) IsRetryTopic(topic string) bool {
+	_, ok := p.delays[topic]
+	return ok
+}
+
+// forward retries until the message is sent, so that a failed message is never lost
+func (p *RetryPolicy) forward(ctx context.Context, message *sarama.ProducerMessage) error {
+	backoff := forwardBackoff
+	for {
+		_, _, err := p.producer.SendMessage(message)
+		if err == nil {
+			return nil
+		}
+		log.Errorff(ctx, "RetryPolicy.forward|topic:%s|err:%v", message.Topic, err)
+
+		err = sleepUntil(ctx, time.Now().Add(backoff))
+		if err != nil {
+			return err
+		}
+		backoff *= 2
+		if backoff > maxForwardBackoff {
+			backoff = maxForwardBackoff
+		}
+	}
+}
+
+func newForwardMessage(topic string, message *sarama.ConsumerMessage, handleErr error, attempt int) *sarama.ProducerMessage {
+	originalTopic := headerValue(message.Headers, HeaderOriginalTopic)
+	originalPartition := headerValue(message.Headers, HeaderOriginalPartition)
+	originalOffset := headerValue(message.Headers, HeaderOriginalOffset)
+	if originalTopic == "" {
+		originalTopic = message.Topic
+		originalPartition = strconv.FormatInt(int64(message.Partition), 10)
+		originalOffset = strconv.FormatInt(message.Offset, 10)
+	}
+
+	headers := copyHeaders(message.Headers)
+	headers = append(headers,
+		sarama.RecordHeader{Key: []byte(HeaderOriginalTopic), Value: []byte(originalTopic)},
+		sarama.RecordHeader{Key: []byte(HeaderOriginalPartition), Value: []byte(originalPartition)},
+		sarama.RecordHeader{Key: []byte(HeaderOriginalOffset), Value: []byte(originalOffset)},
+		sarama.RecordHeader{Key: []byte(HeaderError), Value: []byte(handleErr.Error())},
+		sarama.RecordHeader{Key: []byte(HeaderAttempt), Value: []byte(strconv.Itoa(attempt))},
+	)
+
+	return &sarama.ProducerMessage{
+		Topic:   topic,
+		Key:     sarama.ByteEncoder(message.Key),
+		Value:   sarama.ByteEncoder(message.Value),
+		Headers: headers,
+	}
+}
+
+var retryHeaders = map[string]bool{
+	HeaderOriginalTopic:     true,
+	HeaderOriginalPartition: true,
+	HeaderOriginalOffset:    true,
+	HeaderError:             true,
+	HeaderAttempt:           true,
+}
+
+// copyHeaders returns the headers of message except the retry headers
+func copyHeaders(headers []*sarama.RecordHeader) []sarama.RecordHeader {
+	result := make([]sarama.RecordHeader, 0, len(headers))
+	for _, header := range headers {
+		if header == nil || retryHeaders[string(header.Key)] {
+			continue
+		}
+		result = append(result, *header)
+	}
+	return result
+}
+
+func headerValue(headers []*sarama.RecordHeader, key string) string {
+	for _, header := range headers {
+		if header != nil && string(header.Key) == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+func headerInt(headers []*sarama.RecordHeader, key string) int {
+	value, err := strconv.Atoi(headerValue(headers, key))
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+func sleepUntil(ctx context.Context, deadline time.Time) error {
+	wait := time.Until(deadline)
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
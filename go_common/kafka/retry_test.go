@@ -0,0 +1,199 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSyncProducer records the messages sent, the first fails sends fail
+type fakeSyncProducer struct {
+	sarama.SyncProducer
+	mutex sync.Mutex
+	fails int
+	sent  []*sarama.ProducerMessage
+}
+
+func (p *fakeSyncProducer) SendMessage(message *sarama.ProducerMessage) (int32, int64, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.fails != 0 {
+		p.fails--
+		return 0, 0, errors.New("kafka unavailable")
+	}
+	p.sent = append(p.sent, message)
+	return 0, int64(len(p.sent) - 1), nil
+}
+
+func (p *fakeSyncProducer) messages() []*sarama.ProducerMessage {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return append([]*sarama.ProducerMessage(nil), p.sent...)
+}
+
+// consumed returns the message as consumed from its topic
+func consumed(message *sarama.ProducerMessage, offset int64) *sarama.ConsumerMessage {
+	key, _ := message.Key.Encode()
+	value, _ := message.Value.Encode()
+	headers := make([]*sarama.RecordHeader, 0, len(message.Headers))
+	for i := range message.Headers {
+		headers = append(headers, &message.Headers[i])
+	}
+	return &sarama.ConsumerMessage{
+		Topic: message.Topic, Offset: offset, Key: key, Value: value, Headers: headers, Timestamp: time.Now(),
+	}
+}
+
+func headerValues(message *sarama.ProducerMessage, key string) []string {
+	var values []string
+	for _, header := range message.Headers {
+		if string(header.Key) == key {
+			values = append(values, string(header.Value))
+		}
+	}
+	return values
+}
+
+func newTestRetryPolicy(producer sarama.SyncProducer, deadLetterTopic string) *RetryPolicy {
+	return NewRetryPolicy(RetryConfig{
+		RetryTopics:     []RetryTopic{{Topic: "event_retry_1"}, {Topic: "event_retry_2"}},
+		DeadLetterTopic: deadLetterTopic,
+	}, producer)
+}
+
+func TestRetryPolicy_Forward(t *testing.T) {
+	ctx := context.Background()
+	producer := &fakeSyncProducer{}
+	policy := newTestRetryPolicy(producer, "event_dlq")
+	message := &sarama.ConsumerMessage{
+		Topic: "event", Partition: 3, Offset: 42, Key: []byte("7"), Value: []byte("payload"),
+		Headers: []*sarama.RecordHeader{{Key: []byte("trace"), Value: []byte("abc")}},
+	}
+
+	// each failure moves the message to the next retry topic, then to the dead letter topic
+	for i, topic := range []string{"event_retry_1", "event_retry_2", "event_dlq"} {
+		require.NoError(t, policy.Forward(ctx, message, errors.New("handle failed")))
+		sent := producer.messages()
+		require.Len(t, sent, i+1)
+		forwarded := sent[i]
+		assert.Equal(t, topic, forwarded.Topic)
+		assert.Equal(t, []string{strconv.Itoa(i + 1)}, headerValues(forwarded, HeaderAttempt))
+		assert.Equal(t, []string{"event"}, headerValues(forwarded, HeaderOriginalTopic))
+		assert.Equal(t, []string{"3"}, headerValues(forwarded, HeaderOriginalPartition))
+		assert.Equal(t, []string{"42"}, headerValues(forwarded, HeaderOriginalOffset))
+		assert.Equal(t, []string{"handle failed"}, headerValues(forwarded, HeaderError))
+		assert.Equal(t, []string{"abc"}, headerValues(forwarded, "trace"))
+		message = consumed(forwarded, int64(i))
+		assert.Equal(t, []byte("7"), message.Key)
+		assert.Equal(t, []byte("payload"), message.Value)
+	}
+
+	// a message of the dead letter topic which fails again stays there
+	require.NoError(t, policy.Forward(ctx, message, errors.New("handle failed")))
+	sent := producer.messages()
+	assert.Equal(t, "event_dlq", sent[3].Topic)
+	assert.Equal(t, []string{"4"}, headerValues(sent[3], HeaderAttempt))
+}
+
+func TestRetryPolicy_NonRetryable(t *testing.T) {
+	ctx := context.Background()
+	producer := &fakeSyncProducer{}
+	policy := newTestRetryPolicy(producer, "event_dlq")
+
+	err := policy.Forward(ctx, &sarama.ConsumerMessage{Topic: "event"}, NonRetryable(errors.New("malformed")))
+	require.NoError(t, err)
+	sent := producer.messages()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "event_dlq", sent[0].Topic)
+	assert.Equal(t, []string{"malformed"}, headerValues(sent[0], HeaderError))
+}
+
+func TestRetryPolicy_NoDeadLetterTopic(t *testing.T) {
+	ctx := context.Background()
+	producer := &fakeSyncProducer{}
+	policy := newTestRetryPolicy(producer, "")
+	message := &sarama.ConsumerMessage{
+		Topic:   "event_retry_2",
+		Headers: []*sarama.RecordHeader{{Key: []byte(HeaderAttempt), Value: []byte("2")}},
+	}
+
+	// the message is dropped after the last retry
+	require.NoError(t, policy.Forward(ctx, message, errors.New("handle failed")))
+	assert.Empty(t, producer.messages())
+}
+
+func TestRetryPolicy_Wrap(t *testing.T) {
+	ctx := context.Background()
+	producer := &fakeSyncProducer{}
+	policy := newTestRetryPolicy(producer, "event_dlq")
+	var handled []int64
+	handler := policy.Wrap(func(ctx context.Context, message *sarama.ConsumerMessage) error {
+		handled = append(handled, message.Offset)
+		if message.Offset == 1 {
+			return errors.New("handle failed")
+		}
+		return nil
+	})
+
+	require.NoError(t, handler(ctx, &sarama.ConsumerMessage{Topic: "event", Offset: 0}))
+	require.NoError(t, handler(ctx, &sarama.ConsumerMessage{Topic: "event", Offset: 1}))
+	assert.Equal(t, []int64{0, 1}, handled)
+	sent := producer.messages()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "event_retry_1", sent[0].Topic)
+}
+
+func TestRetryPolicy_WrapStopping(t *testing.T) {
+	producer := &fakeSyncProducer{fails: -1}
+	policy := NewRetryPolicy(RetryConfig{
+		RetryTopics:     []RetryTopic{{Topic: "event_retry_1", Delay: time.Hour}},
+		DeadLetterTopic: "event_dlq",
+	}, producer)
+	handled := 0
+	handler := policy.Wrap(func(ctx context.Context, message *sarama.ConsumerMessage) error {
+		handled++
+		return errors.New("handle failed")
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// a message of a retry topic isn't handled before its delay
+	err := handler(ctx, &sarama.ConsumerMessage{Topic: "event_retry_1", Timestamp: time.Now()})
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Zero(t, handled)
+
+	// a failed message which can't be forwarded fails the handler, so that it's not marked
+	err = handler(ctx, &sarama.ConsumerMessage{Topic: "event"})
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, 1, handled)
+	assert.Empty(t, producer.messages())
+}
+
+func TestRetryPolicy_WrapDelay(t *testing.T) {
+	ctx := context.Background()
+	policy := NewRetryPolicy(RetryConfig{
+		RetryTopics: []RetryTopic{{Topic: "event_retry_1", Delay: 30 * time.Millisecond}},
+	}, &fakeSyncProducer{})
+	var handledAt time.Time
+	handler := policy.Wrap(func(ctx context.Context, message *sarama.ConsumerMessage) error {
+		handledAt = time.Now()
+		return nil
+	})
+
+	// the delay counts from the time the message was forwarded
+	forwardedAt := time.Now()
+	require.NoError(t, handler(ctx, &sarama.ConsumerMessage{Topic: "event_retry_1", Timestamp: forwardedAt}))
+	assert.GreaterOrEqual(t, int64(handledAt.Sub(forwardedAt)), int64(30*time.Millisecond))
+
+	start := time.Now()
+	late := &sarama.ConsumerMessage{Topic: "event_retry_1", Timestamp: forwardedAt.Add(-time.Minute)}
+	require.NoError(t, handler(ctx, late))
+	assert.Less(t, int64(handledAt.Sub(start)), int64(30*time.Millisecond))
+}
@@ -93,6 +93,16 @@ var logLevel = zap.NewAtomicLevel()
 // to dynamically change the logging level.
 var Default = NewCtxLogger()
 
+// SetLevel changes the level of every logger at runtime
+func SetLevel(level Level) {
+	logLevel.SetLevel(level)
+}
+
+// GetLevel returns the current level of the loggers
+func GetLevel() Level {
+	return logLevel.Level()
+}
+
 // DefaultContext The defaultContext for ctxlogger
 var DefaultContext = WithLogger(context.Background(), Default)
 
@@ -447,10 +457,11 @@ func Configure(ctx context.Context, config Config) (context.Context, error) {
 		writersMap[DebugLevel] = append(writersMap[DebugLevel], os.Stderr)
 	}
 
+	logLevel.SetLevel(config.Level)
 	loggers := map[Level]*zap.Logger{}
 	for l, writers := range writersMap {
 		loggers[l] = newZapLogger(
-			config.Level,
+			&logLevel,
 			config.EncodeLogsAsJSON,
 			config.CallerEnabled,
 			config.CallerSkip,
@@ -487,7 +498,7 @@ func TimeEncoder(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
 	enc.AppendString(t.Format("2006-01-02 15:04:05.000"))
 }
 
-func newZapLogger(lvl Level, encodeAsJSON, enableCaller bool, callerSkip int, output zapcore.WriteSyncer) *zap.Logger {
+func newZapLogger(lvl zapcore.LevelEnabler, encodeAsJSON, enableCaller bool, callerSkip int, output zapcore.WriteSyncer) *zap.Logger {
 	var encoder zapcore.Encoder
 	encCfg := zapcore.EncoderConfig{
 		TimeKey:  defaultTimeKey,
@@ -509,9 +520,9 @@ func newZapLogger(lvl Level, encodeAsJSON, enableCaller bool, callerSkip int, ou
 		encoder = zapcore.NewConsoleEncoder(encCfg)
 	}
 	if enableCaller {
-		return zap.New(zapcore.NewCore(encoder, output, zap.NewAtomicLevelAt(lvl)), zap.AddCaller(), zap.AddCallerSkip(callerSkip))
+		return zap.New(zapcore.NewCore(encoder, output, lvl), zap.AddCaller(), zap.AddCallerSkip(callerSkip))
 	}
-	return zap.New(zapcore.NewCore(encoder, output, zap.NewAtomicLevelAt(lvl)))
+	return zap.New(zapcore.NewCore(encoder, output, lvl))
 }
 
 const (
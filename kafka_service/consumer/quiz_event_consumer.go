@@ -0,0 +1,245 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/Shopify/sarama"
+	"github.com/luulethe/quiz/go_common/kafka"
+	"github.com/luulethe/quiz/go_common/log"
+	"github.com/luulethe/quiz/go_common/metrics"
+	"github.com/luulethe/quiz/quiz_lib/manager"
+	pb "github.com/luulethe/quiz/quiz_lib/pb/gen"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	quizEventAction = "QuizEvent"
+	defaultWorkers  = 16
+)
+
+// quizEventGroup holds the messages of one quiz in a batch, its participant events in order
+// and its latest status event
+type quizEventGroup struct {
+	quizID       int64
+	messages     []*sarama.ConsumerMessage
+	participants []*pb.QuizEvent
+	status       *pb.QuizEvent
+}
+
+func newQuizEventGroup(quizID int64) *quizEventGroup {
+	return &quizEventGroup{quizID: quizID}
+}
+
+func (g *quizEventGroup) add(message *sarama.ConsumerMessage, event *pb.QuizEvent) {
+	g.messages = append(g.messages, message)
+	switch event.Type {
+	case pb.QuizEventType_QUIZ_EVENT_PARTICIPANT_JOINED, pb.QuizEventType_QUIZ_EVENT_SCORE_CHANGED:
+		g.participants = append(g.participants, event)
+	case pb.QuizEventType_QUIZ_EVENT_STATUS_CHANGED:
+		g.status = event
+	}
+}
+
+// QuizEventConsumer represents a Sarama consumer group consumer of pb.QuizEvent.
+// Events of the source topic are grouped per quiz within a batch, the participant events of a quiz are applied
+// to its leader board in one call and the leader board is pushed once.
+type QuizEventConsumer struct {
+	ctx     context.Context
+	dep     *manager.Dependency
+	kqueue  sarama.SyncProducer
+	stats   *metrics.StatsCollector
+	retry   *kafka.RetryPolicy
+	handle  kafka.MessageHandler
+	batch   kafka.BatchConfig
+	workers int
+}
+
+func NewQuizEventConsumer(
+	ctx context.Context, dep *manager.Dependency, kqueue sarama.SyncProducer, stats *metrics.StatsCollector,
+) *QuizEventConsumer {
+	var retryConfig kafka.RetryConfig
+	var batchConfig kafka.BatchConfig
+	workers := defaultWorkers
+	if dep.Conf != nil && dep.Conf.QuizKafka != nil {
+		retryConfig = dep.Conf.QuizKafka.Retry
+		batchConfig = dep.Conf.QuizKafka.Batch
+		if dep.Conf.QuizKafka.Workers > 0 {
+			workers = dep.Conf.QuizKafka.Workers
+		}
+	}
+	c := &QuizEventConsumer{
+		ctx:     ctx,
+		dep:     dep,
+		kqueue:  kqueue,
+		stats:   stats,
+		retry:   kafka.NewRetryPolicy(retryConfig, kqueue),
+		batch:   batchConfig,
+		workers: workers,
+	}
+	c.handle = c.retry.Wrap(c.handleMessage)
+	return c
+}
+
+// Topics returns the source topic and its retry topics
+func (c *QuizEventConsumer) Topics() []string {
+	return append([]string{c.dep.QuizEventTopic()}, c.retry.Topics()...)
+}
+
+func (c *QuizEventConsumer) Setup(sarama.ConsumerGroupSession) error {
+	log.Info(c.ctx, "quiz event consumer is running!...")
+	return nil
+}
+
+func (c *QuizEventConsumer) Cleanup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+// ConsumeClaim must start a consumer loop of ConsumerGroupClaim's Messages().
+// Messages of retry topics are handled one by one after their retry delay, others are handled in batches.
+func (c *QuizEventConsumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	if !c.retry.IsRetryTopic(claim.Topic()) {
+		return kafka.ConsumeBatches(c.ctx, session, claim, c.batch, c.handleBatch)
+	}
+
+	for message := range claim.Messages() {
+		err := c.handle(c.ctx, message)
+		if err != nil {
+			// the consumer is stopping before a failed message is forwarded, keep it unmarked
+			log.Errorff(c.ctx, "event_consumer|stop|topic:%s|partition:%d|offset:%d|err:%v",
+				message.Topic, message.Partition, message.Offset, err)
+			return nil
+		}
+		session.MarkMessage(message, "")
+	}
+	return nil
+}
+
+// handleBatch handles each quiz of the batch on a single worker, and different quizzes in parallel.
+// Messages failed to handle are forwarded to the retry topic, so that the batch can be marked.
+func (c *QuizEventConsumer) handleBatch(ctx context.Context, messages []*sarama.ConsumerMessage) error {
+	var quizIDs []int64
+	groups := map[int64]*quizEventGroup{}
+	for _, message := range messages {
+		event, err := decodeQuizEvent(message)
+		if err != nil {
+			log.Errorff(ctx, "event_consumer|decode|offset:%d|err:%v", message.Offset, err)
+			c.stats.ReportCount(1, quizEventAction, string(metrics.ResultError))
+			if err := c.retry.Forward(ctx, message, err); err != nil {
+				return err
+			}
+			continue
+		}
+
+		group, ok := groups[event.QuizId]
+		if !ok {
+			group = newQuizEventGroup(event.QuizId)
+			groups[event.QuizId] = group
+			quizIDs = append(quizIDs, event.QuizId)
+		}
+		group.add(message, event)
+	}
+
+	workers := make(chan struct{}, c.workers)
+	errs := make(chan error, len(quizIDs))
+	wg := &sync.WaitGroup{}
+	for _, quizID := range quizIDs {
+		group := groups[quizID]
+		workers <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+			errs <- c.handleGroup(ctx, group)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *QuizEventConsumer) handleGroup(ctx context.Context, group *quizEventGroup) error {
+	var handleErr error
+	metrics.CollectStats(func() (result metrics.ResultType) {
+		result, handleErr = c.applyEvents(ctx, group)
+		return result
+	}, c.stats, group.messages[0].Timestamp, quizEventAction)
+	if handleErr == nil {
+		return nil
+	}
+
+	for _, message := range group.messages {
+		if err := c.retry.Forward(ctx, message, handleErr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handleMessage handles a message of a retry topic
+func (c *QuizEventConsumer) handleMessage(ctx context.Context, message *sarama.ConsumerMessage) error {
+	event, err := decodeQuizEvent(message)
+	if err != nil {
+		log.Errorff(ctx, "event_consumer|decode|offset:%d|err:%v", message.Offset, err)
+		c.stats.ReportCount(1, quizEventAction, string(metrics.ResultError))
+		return err
+	}
+
+	group := newQuizEventGroup(event.QuizId)
+	group.add(message, event)
+
+	var handleErr error
+	metrics.CollectStats(func() (result metrics.ResultType) {
+		result, handleErr = c.applyEvents(ctx, group)
+		return result
+	}, c.stats, message.Timestamp, quizEventAction)
+	return handleErr
+}
+
+func (c *QuizEventConsumer) applyEvents(ctx context.Context, group *quizEventGroup) (metrics.ResultType, error) {
+	result := metrics.ResultNotInterested
+	if len(group.participants) > 0 {
+		err := c.dep.QuizManager.HandleParticipantEvents(ctx, group.quizID, group.participants)
+		if err != nil {
+			log.Errorf(ctx, "event_consumer_error|quiz_id:%v|events:%d|err:%v", group.quizID, len(group.participants), err)
+			return metrics.ResultError, err
+		}
+		result = metrics.ResultSuccess
+	}
+	if group.status != nil {
+		err := c.dep.QuizManager.HandleStatusChanged(ctx, group.status)
+		if err != nil {
+			log.Errorf(ctx, "event_consumer_error|event:%v|err:%v", group.status, err)
+			return metrics.ResultError, err
+		}
+		result = metrics.ResultSuccess
+	}
+	if result == metrics.ResultSuccess {
+		// best effort, the clients still get the leader board on their next change or request
+		_ = c.dep.Notifier.Notify(ctx, group.quizID)
+	}
+	log.Infof(ctx, "event_consumer|quiz_id:%v|messages:%d|result:%s", group.quizID, len(group.messages), result)
+
+	return result, nil
+}
+
+func decodeQuizEvent(message *sarama.ConsumerMessage) (*pb.QuizEvent, error) {
+	event := &pb.QuizEvent{}
+	err := proto.Unmarshal(message.Value, event)
+	if err != nil {
+		return nil, kafka.NonRetryable(err)
+	}
+	if event.Version != manager.QuizEventVersion {
+		return nil, kafka.NonRetryable(fmt.Errorf("unsupported event version %d", event.Version))
+	}
+	return event, nil
+}
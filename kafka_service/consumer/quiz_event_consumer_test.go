@@ -0,0 +1,168 @@
+package consumer
+
+import (
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/luulethe/quiz/quiz_lib/db/model"
+	"github.com/luulethe/quiz/quiz_lib/manager"
+	pb "github.com/luulethe/quiz/quiz_lib/pb/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// quizEventDescriptor derives another version of QuizEvent from the current one
+func quizEventDescriptor(t *testing.T, change func(file *descriptorpb.FileDescriptorProto, event *descriptorpb.DescriptorProto)) protoreflect.MessageDescriptor {
+	file := protodesc.ToFileDescriptorProto(pb.File_quiz_event_proto)
+	var event *descriptorpb.DescriptorProto
+	for _, message := range file.MessageType {
+		if message.GetName() == "QuizEvent" {
+			event = message
+		}
+	}
+	require.NotNil(t, event)
+	change(file, event)
+
+	descriptor, err := protodesc.NewFile(file, nil)
+	require.NoError(t, err)
+	return descriptor.Messages().ByName("QuizEvent")
+}
+
+// oldQuizEvent is QuizEvent before the participant state was added
+func oldQuizEvent(t *testing.T) protoreflect.MessageDescriptor {
+	return quizEventDescriptor(t, func(file *descriptorpb.FileDescriptorProto, event *descriptorpb.DescriptorProto) {
+		var fields []*descriptorpb.FieldDescriptorProto
+		for _, field := range event.Field {
+			if field.OneofIndex == nil {
+				fields = append(fields, field)
+			}
+		}
+		event.Field = fields
+		event.OneofDecl = nil
+		var messages []*descriptorpb.DescriptorProto
+		for _, message := range file.MessageType {
+			if message.GetName() != "ParticipantState" {
+				messages = append(messages, message)
+			}
+		}
+		file.MessageType = messages
+	})
+}
+
+// newerQuizEvent is a later QuizEvent with a field unknown to this server
+func newerQuizEvent(t *testing.T) protoreflect.MessageDescriptor {
+	return quizEventDescriptor(t, func(file *descriptorpb.FileDescriptorProto, event *descriptorpb.DescriptorProto) {
+		event.Field = append(event.Field, &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String("note"),
+			JsonName: proto.String("note"),
+			Number:   proto.Int32(100),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+		})
+	})
+}
+
+func setField(message *dynamicpb.Message, name string, value protoreflect.Value) {
+	message.Set(message.Descriptor().Fields().ByName(protoreflect.Name(name)), value)
+}
+
+func TestDecodeQuizEvent_OldVersion(t *testing.T) {
+	old := dynamicpb.NewMessage(oldQuizEvent(t))
+	setField(old, "version", protoreflect.ValueOfInt32(manager.QuizEventVersion))
+	setField(old, "type", protoreflect.ValueOfEnum(protoreflect.EnumNumber(pb.QuizEventType_QUIZ_EVENT_SCORE_CHANGED)))
+	setField(old, "quiz_id", protoreflect.ValueOfInt64(7))
+	setField(old, "user_id", protoreflect.ValueOfInt64(11))
+	setField(old, "delta", protoreflect.ValueOfInt32(5))
+	payload, err := proto.Marshal(old)
+	require.NoError(t, err)
+
+	event, err := decodeQuizEvent(&sarama.ConsumerMessage{Value: payload})
+	require.NoError(t, err)
+	assert.Equal(t, pb.QuizEventType_QUIZ_EVENT_SCORE_CHANGED, event.Type)
+	assert.Equal(t, int64(7), event.QuizId)
+	assert.Equal(t, int64(11), event.UserId)
+	assert.Equal(t, int32(5), event.Delta)
+	// without a state the leader board is rebuilt from database
+	assert.Nil(t, event.GetParticipant())
+	assert.Nil(t, event.State)
+}
+
+func TestDecodeQuizEvent_ByOldVersion(t *testing.T) {
+	participant := &model.QuizParticipantTab{QuizID: 7, UserID: 11, Score: 15, UpdatedTime: 1617235200000}
+	event := manager.NewParticipantEvent(pb.QuizEventType_QUIZ_EVENT_SCORE_CHANGED, participant, 5)
+	payload, err := proto.Marshal(event)
+	require.NoError(t, err)
+
+	old := dynamicpb.NewMessage(oldQuizEvent(t))
+	require.NoError(t, proto.Unmarshal(payload, old))
+	assert.Equal(t, int64(7), old.Get(old.Descriptor().Fields().ByName("quiz_id")).Int())
+	assert.Equal(t, int64(5), old.Get(old.Descriptor().Fields().ByName("delta")).Int())
+	assert.NotEmpty(t, old.GetUnknown(), "the state is an unknown field of the old version")
+
+	// an old relay or retry forwarder keeps the state it doesn't know
+	forwarded, err := proto.Marshal(old)
+	require.NoError(t, err)
+	decoded, err := decodeQuizEvent(&sarama.ConsumerMessage{Value: forwarded})
+	require.NoError(t, err)
+	require.NotNil(t, decoded.GetParticipant())
+	assert.Equal(t, int32(15), decoded.GetParticipant().Score)
+	assert.Equal(t, int64(1617235200000), decoded.GetParticipant().UpdatedTime)
+}
+
+func TestDecodeQuizEvent_NewerVersion(t *testing.T) {
+	newer := dynamicpb.NewMessage(newerQuizEvent(t))
+	setField(newer, "version", protoreflect.ValueOfInt32(manager.QuizEventVersion))
+	setField(newer, "type", protoreflect.ValueOfEnum(protoreflect.EnumNumber(pb.QuizEventType_QUIZ_EVENT_PARTICIPANT_JOINED)))
+	setField(newer, "quiz_id", protoreflect.ValueOfInt64(7))
+	setField(newer, "user_id", protoreflect.ValueOfInt64(11))
+	setField(newer, "note", protoreflect.ValueOfString("added later"))
+	state := newer.NewField(newer.Descriptor().Fields().ByName("participant")).Message()
+	state.Set(state.Descriptor().Fields().ByName("updated_time"), protoreflect.ValueOfInt64(1617235200000))
+	setField(newer, "participant", protoreflect.ValueOfMessage(state))
+	payload, err := proto.Marshal(newer)
+	require.NoError(t, err)
+
+	event, err := decodeQuizEvent(&sarama.ConsumerMessage{Value: payload})
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), event.UserId)
+	require.NotNil(t, event.GetParticipant())
+	assert.Equal(t, int64(1617235200000), event.GetParticipant().UpdatedTime)
+	assert.NotEmpty(t, event.ProtoReflect().GetUnknown())
+
+	encoded, err := proto.Marshal(event)
+	require.NoError(t, err)
+	decoded := dynamicpb.NewMessage(newerQuizEvent(t))
+	require.NoError(t, proto.Unmarshal(encoded, decoded))
+	assert.Equal(t, "added later", decoded.Get(decoded.Descriptor().Fields().ByName("note")).String())
+}
+
+func TestDecodeQuizEvent_UnsupportedVersion(t *testing.T) {
+	payload, err := proto.Marshal(&pb.QuizEvent{Version: manager.QuizEventVersion + 1, QuizId: 7})
+	require.NoError(t, err)
+
+	_, err = decodeQuizEvent(&sarama.ConsumerMessage{Value: payload})
+	assert.Error(t, err)
+}
+
+func TestQuizEventGroup(t *testing.T) {
+	group := newQuizEventGroup(7)
+	events := []*pb.QuizEvent{
+		{Type: pb.QuizEventType_QUIZ_EVENT_PARTICIPANT_JOINED, QuizId: 7, UserId: 1},
+		{Type: pb.QuizEventType_QUIZ_EVENT_STATUS_CHANGED, QuizId: 7, ToStatus: model.QuizStatusInProgress},
+		{Type: pb.QuizEventType_QUIZ_EVENT_SCORE_CHANGED, QuizId: 7, UserId: 1, Delta: 5},
+		{Type: pb.QuizEventType_QUIZ_EVENT_SCORE_CHANGED, QuizId: 7, UserId: 1, Delta: 3},
+		{Type: pb.QuizEventType_QUIZ_EVENT_STATUS_CHANGED, QuizId: 7, ToStatus: model.QuizStatusFinished},
+	}
+	for _, event := range events {
+		group.add(&sarama.ConsumerMessage{}, event)
+	}
+
+	assert.Len(t, group.messages, 5)
+	assert.Equal(t, []*pb.QuizEvent{events[0], events[2], events[3]}, group.participants)
+	assert.Equal(t, events[4], group.status)
+}
@@ -3,12 +3,11 @@ package main
 import (
 	"context"
 	"flag"
-	"fmt"
 	"os"
 	"os/signal"
-	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/Shopify/sarama"
 	"github.com/luulethe/quiz/config"
@@ -22,16 +21,23 @@ import (
 )
 
 var (
-	confPath   = flag.String("config", fmt.Sprintf("config/%s.yml", os.Getenv("DEPLOY")), "set config file")
+	confFlags  = config.RegisterFlags(flag.CommandLine)
 	consoleLog = flag.Bool("console", true, "enable console log")
+	replayDLQ  = flag.Bool("replay-dlq", false, "publish dead letters back to the quiz event topic then exit")
 	logPath    = os.Getenv("APP_LOG_PATH")
 )
 
+const replayIdleTimeout = 10 * time.Second
+
 func main() {
-	config := &config.Configuration{}
+	flag.Parse()
+	config, err := confFlags.Load()
+	util.ExitOnErr(context.Background(), err)
+	if confFlags.PrintConfig {
+		util.ExitOnErr(context.Background(), config.WriteMasked(os.Stdout))
+		return
+	}
 	ctx, cancel := context.WithCancel(context.Background())
-	err := config.LoadFromFile(*confPath)
-	util.ExitOnErr(ctx, err)
 
 	if config.SentryDNS != "" {
 		err := sentry.Init(config.SentryDNS)
@@ -46,25 +52,36 @@ func main() {
 	defer log.Flush(ctx)
 	log.Debugf(ctx, "config: %v\nStarting quiz event consumer\n", config)
 
-	stats := metrics.StartMonitor(ctx, "note_kafka", config.ProfileAddr)
+	stats := metrics.StartMonitor(ctx, "quiz_kafka", config.ProfileAddr)
 
 	dep := &manager.Dependency{}
 	err = dep.Init(ctx, config, stats, nil)
 	util.ExitOnErr(ctx, err)
 	defer dep.Close()
+	dep.Dynamic.Watch(ctx, confFlags.Loader)
 
-	consumerGroup, err := kafka.NewKafkaConsumerClient(config.NoteKafka.Brokers, config.NoteKafka.ConsumerGroup, config.NoteKafka.Version)
+	kqueue, err := kafka.NewSyncKafkaProducer(ctx, config.QuizKafka.Brokers)
 	util.ExitOnErr(ctx, err)
+	defer kqueue.Close()
 
-	brokers := strings.Split(config.NoteKafka.Brokers, ",")
-	kqueue, err := sarama.NewSyncProducer(brokers, nil)
+	if *replayDLQ {
+		replayDeadLetters(ctx, config, kqueue)
+		return
+	}
+
+	consumerGroup, err := kafka.NewKafkaConsumerClient(config.QuizKafka.Brokers, config.QuizKafka.ConsumerGroup, config.QuizKafka.Version)
 	util.ExitOnErr(ctx, err)
 
 	// setup consumer
 	wg := &sync.WaitGroup{}
-	topics := []string{consumer.NoteEventTopic}
-	handler := consumer.NewNoteEventConsumer(ctx, dep, kqueue, stats)
-	kafka.ConsumerServe(ctx, wg, consumerGroup, handler, topics)
+	handler := consumer.NewQuizEventConsumer(ctx, dep, kqueue, stats)
+	kafka.ConsumerServe(ctx, wg, consumerGroup, handler, handler.Topics())
+
+	// relay quiz_outbox_tab to kafka
+	manager.NewOutboxRelay(dep).Run(ctx, wg)
+
+	// move quizzes along their lifecycle
+	manager.NewQuizScheduler(dep).Run(ctx, wg)
 
 	sigterm := make(chan os.Signal, 1)
 	signal.Notify(sigterm, syscall.SIGINT, syscall.SIGTERM)
@@ -80,3 +97,15 @@ func main() {
 		log.Errorf(ctx, "Error closing client: %v", err)
 	}
 }
+
+func replayDeadLetters(ctx context.Context, config *config.Configuration, kqueue sarama.SyncProducer) {
+	consumerGroup, err := kafka.NewKafkaReplayConsumerClient(
+		config.QuizKafka.Brokers, config.QuizKafka.ConsumerGroup+"_dlq_replay", config.QuizKafka.Version,
+	)
+	util.ExitOnErr(ctx, err)
+	defer consumerGroup.Close()
+
+	count, err := kafka.ReplayDeadLetters(ctx, consumerGroup, config.QuizKafka.Retry.DeadLetterTopic, kqueue, replayIdleTimeout)
+	util.ExitOnErr(ctx, err)
+	log.Infof(ctx, "replayed %d dead letters", count)
+}
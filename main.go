@@ -3,13 +3,13 @@ package main
 import (
 	"context"
 	"flag"
-	"fmt"
 	"io/ioutil"
 	"net/http"
 	"os"
 	"strconv"
 	"time"
 
+	"github.com/gin-gonic/gin"
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
 	"github.com/luulethe/quiz/config"
@@ -30,7 +30,7 @@ import (
 )
 
 var (
-	confPath   = flag.String("config", fmt.Sprintf("config/%s.yml", os.Getenv("DEPLOY")), "set config file")
+	confFlags  = config.RegisterFlags(flag.CommandLine)
 	consoleLog = flag.Bool("console", true, "enable console log")
 	logPath    = os.Getenv("APP_LOG_PATH")
 )
@@ -38,14 +38,23 @@ var (
 func main() {
 	flag.Parse()
 
-	conf := &config.Configuration{}
-	util.ExitOnErr(log.DefaultContext, conf.LoadFromFile(*confPath))
+	conf, err := confFlags.Load()
+	util.ExitOnErr(log.DefaultContext, err)
+	if confFlags.PrintConfig {
+		util.ExitOnErr(log.DefaultContext, conf.WriteMasked(os.Stdout))
+		return
+	}
 
 	ctx := util.InitLog(context.Background(), logPath, conf.Debug, *consoleLog, log.FileConfig{
 		log.ErrorLevel: {"error.log", "info.log"},
 		log.InfoLevel:  {"info.log"},
 	})
 	defer log.Flush(ctx)
+
+	if flag.Arg(0) == "migrate" {
+		exitOnErr(ctx, runMigration(conf, flag.Args()[1:]))
+		return
+	}
 	log.Debug(ctx, "Starting GRPC Http Server\n")
 
 	if conf.SentryDNS != "" {
@@ -62,9 +71,10 @@ func main() {
 	extraMetrics := &manager.MetricsCollection{}
 
 	dependency := &manager.Dependency{}
-	err := dependency.Init(ctx, conf, statCollector, extraMetrics)
+	err = dependency.Init(ctx, conf, statCollector, extraMetrics)
 	util.ExitOnErr(ctx, err)
 	defer dependency.Close()
+	dependency.Dynamic.Watch(ctx, confFlags.Loader)
 
 	if conf.ProfileAddr != "" { // setup pprof & metrics
 		l, err := fork.Listen("tcp4", conf.ProfileAddr)
@@ -102,6 +112,7 @@ func main() {
 	// grpc_handler.UnaryInterceptor(metricsInterceptor(requestCounter, requestLatencySummary)),
 	// )
 	rpc.RegisterQuizServiceServer(gRPCServer, quizServer)
+	rpc.RegisterQuizServiceV2Server(gRPCServer, quiz_api.NewQuizServerV2(ctx, dependency))
 
 	healthServer := health.NewServer()
 	healthrpc.RegisterHealthServer(gRPCServer, healthServer)
@@ -122,6 +133,27 @@ func main() {
 		defer gRPCServer.GracefulStop()
 	}
 
+	// ======================= HTTP Gateway ======================= //
+
+	if conf.HTTPListen != "" {
+		if !conf.Debug {
+			gin.SetMode(gin.ReleaseMode)
+		}
+		httpListener, err := fork.Listen("tcp4", conf.HTTPListen)
+		exitOnErr(ctx, err)
+		log.Infof(ctx, "http server listener: %s", httpListener.Addr().String())
+
+		httpServer := &http.Server{Handler: quiz_api.NewHTTPServer(ctx, dependency)}
+		go func() {
+			if err := httpServer.Serve(httpListener); err != http.ErrServerClosed {
+				log.Fatalff(ctx, "http server not serving|err:%v", err)
+			}
+		}()
+		defer util.WithErrorCaptured(ctx, func() error {
+			return httpServer.Shutdown(ctx)
+		}, "httpServer.Shutdown")
+	}
+
 	err = fork.SignalParent()
 	if err != nil {
 		log.Errorff(ctx, "[SIGNAL] parent|err:%v", err)
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/luulethe/quiz/config"
+	"github.com/luulethe/quiz/quiz_lib/db"
+	"github.com/luulethe/quiz/quiz_lib/db/migration"
+)
+
+// runMigration runs "migrate up|down|status|redo|baseline VERSION" on every mysql database of the config,
+// the shards have the same schema as the main database
+func runMigration(conf *config.Configuration, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: migrate up|down|status|redo|baseline VERSION")
+	}
+	if len(conf.MySQL) == 0 {
+		return errors.New("missing mysql config")
+	}
+
+	for i, dbConf := range conf.MySQL {
+		if err := migrateDatabase(dbConf, args); err != nil {
+			return fmt.Errorf("database %d %s: %w", i, dbConf.DBName, err)
+		}
+	}
+	return nil
+}
+
+func migrateDatabase(dbConf db.Config, args []string) error {
+	conn, err := db.Connect(dbConf)
+	if err != nil {
+		return err
+	}
+	sqlDB, err := conn.DB()
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close()
+	return migration.Run(sqlDB, args[0], args[1:]...)
+}
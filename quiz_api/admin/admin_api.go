@@ -0,0 +1,195 @@
+package admin
+
+import (
+	"context"
+
+	"github.com/luulethe/quiz/quiz_lib/db/model"
+	"github.com/luulethe/quiz/quiz_lib/manager"
+	pb "github.com/luulethe/quiz/quiz_lib/pb/gen"
+	"github.com/luulethe/quiz/quiz_lib/quiz_error"
+	"google.golang.org/protobuf/proto"
+)
+
+func CreateQuiz(ctx context.Context, dep *manager.Dependency, request *pb.RequestData, response *pb.ResponseData) (err error) {
+	requestData := pb.AdminCreateQuizRequest{}
+	err = proto.Unmarshal(request.Request, &requestData)
+	if err != nil {
+		return quiz_error.Wrap(pb.Error_ERROR_INVALID_PARAMETER, err)
+	}
+
+	quizID, err := dep.QuizManager.CreateQuiz(ctx, requestData.Name, requestData.StartTime, requestData.EndTime)
+	if err != nil {
+		return err
+	}
+
+	reply := pb.AdminCreateQuizReply{QuizId: quizID}
+
+	response.Response, err = proto.Marshal(&reply)
+
+	return err
+}
+
+func UpdateQuiz(ctx context.Context, dep *manager.Dependency, request *pb.RequestData, response *pb.ResponseData) (err error) {
+	requestData := pb.AdminUpdateQuizRequest{}
+	err = proto.Unmarshal(request.Request, &requestData)
+	if err != nil {
+		return quiz_error.Wrap(pb.Error_ERROR_INVALID_PARAMETER, err)
+	}
+
+	err = dep.QuizManager.UpdateQuiz(
+		ctx, requestData.QuizId, requestData.Name, requestData.StartTime, requestData.EndTime,
+	)
+	if err != nil {
+		return err
+	}
+
+	reply := pb.AdminUpdateQuizReply{}
+
+	response.Response, err = proto.Marshal(&reply)
+
+	return err
+}
+
+func AddQuestion(ctx context.Context, dep *manager.Dependency, request *pb.RequestData, response *pb.ResponseData) (err error) {
+	requestData := pb.AdminAddQuestionRequest{}
+	err = proto.Unmarshal(request.Request, &requestData)
+	if err != nil {
+		return quiz_error.Wrap(pb.Error_ERROR_INVALID_PARAMETER, err)
+	}
+
+	question := &model.QuizQuestionTab{
+		QuizID:   requestData.QuizId,
+		Content:  requestData.Content,
+		Score:    requestData.Score,
+		Duration: requestData.Duration,
+	}
+	options := make([]*model.QuizAnswerOptionTab, 0, len(requestData.Options))
+	for _, option := range requestData.Options {
+		options = append(options, &model.QuizAnswerOptionTab{Content: option.Content, IsCorrect: option.IsCorrect})
+	}
+
+	questionID, err := dep.QuizManager.AddQuestion(ctx, question, options)
+	if err != nil {
+		return err
+	}
+
+	reply := pb.AdminAddQuestionReply{QuestionId: questionID}
+
+	response.Response, err = proto.Marshal(&reply)
+
+	return err
+}
+
+func ReorderQuestions(ctx context.Context, dep *manager.Dependency, request *pb.RequestData, response *pb.ResponseData) (err error) {
+	requestData := pb.AdminReorderQuestionsRequest{}
+	err = proto.Unmarshal(request.Request, &requestData)
+	if err != nil {
+		return quiz_error.Wrap(pb.Error_ERROR_INVALID_PARAMETER, err)
+	}
+
+	err = dep.QuizManager.ReorderQuestions(ctx, requestData.QuizId, requestData.QuestionIds)
+	if err != nil {
+		return err
+	}
+
+	reply := pb.AdminReorderQuestionsReply{}
+
+	response.Response, err = proto.Marshal(&reply)
+
+	return err
+}
+
+func PublishQuiz(ctx context.Context, dep *manager.Dependency, request *pb.RequestData, response *pb.ResponseData) (err error) {
+	requestData := pb.AdminPublishQuizRequest{}
+	err = proto.Unmarshal(request.Request, &requestData)
+	if err != nil {
+		return quiz_error.Wrap(pb.Error_ERROR_INVALID_PARAMETER, err)
+	}
+
+	err = dep.QuizManager.PublishQuiz(ctx, requestData.QuizId)
+	if err != nil {
+		return err
+	}
+
+	reply := pb.AdminPublishQuizReply{}
+
+	response.Response, err = proto.Marshal(&reply)
+
+	return err
+}
+
+func CloseQuiz(ctx context.Context, dep *manager.Dependency, request *pb.RequestData, response *pb.ResponseData) (err error) {
+	requestData := pb.AdminCloseQuizRequest{}
+	err = proto.Unmarshal(request.Request, &requestData)
+	if err != nil {
+		return quiz_error.Wrap(pb.Error_ERROR_INVALID_PARAMETER, err)
+	}
+
+	err = dep.QuizManager.CloseQuiz(ctx, requestData.QuizId)
+	if err != nil {
+		return err
+	}
+
+	reply := pb.AdminCloseQuizReply{}
+
+	response.Response, err = proto.Marshal(&reply)
+
+	return err
+}
+
+func PauseQuiz(ctx context.Context, dep *manager.Dependency, request *pb.RequestData, response *pb.ResponseData) (err error) {
+	requestData := pb.AdminPauseQuizRequest{}
+	err = proto.Unmarshal(request.Request, &requestData)
+	if err != nil {
+		return quiz_error.Wrap(pb.Error_ERROR_INVALID_PARAMETER, err)
+	}
+
+	err = dep.QuizManager.PauseQuiz(ctx, requestData.QuizId)
+	if err != nil {
+		return err
+	}
+
+	reply := pb.AdminPauseQuizReply{}
+
+	response.Response, err = proto.Marshal(&reply)
+
+	return err
+}
+
+func ResumeQuiz(ctx context.Context, dep *manager.Dependency, request *pb.RequestData, response *pb.ResponseData) (err error) {
+	requestData := pb.AdminResumeQuizRequest{}
+	err = proto.Unmarshal(request.Request, &requestData)
+	if err != nil {
+		return quiz_error.Wrap(pb.Error_ERROR_INVALID_PARAMETER, err)
+	}
+
+	err = dep.QuizManager.ResumeQuiz(ctx, requestData.QuizId)
+	if err != nil {
+		return err
+	}
+
+	reply := pb.AdminResumeQuizReply{}
+
+	response.Response, err = proto.Marshal(&reply)
+
+	return err
+}
+
+func DeleteQuiz(ctx context.Context, dep *manager.Dependency, request *pb.RequestData, response *pb.ResponseData) (err error) {
+	requestData := pb.AdminDeleteQuizRequest{}
+	err = proto.Unmarshal(request.Request, &requestData)
+	if err != nil {
+		return quiz_error.Wrap(pb.Error_ERROR_INVALID_PARAMETER, err)
+	}
+
+	err = dep.QuizManager.DeleteQuiz(ctx, requestData.QuizId)
+	if err != nil {
+		return err
+	}
+
+	reply := pb.AdminDeleteQuizReply{}
+
+	response.Response, err = proto.Marshal(&reply)
+
+	return err
+}
@@ -0,0 +1,248 @@
+package quiz_api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	sentrygin "github.com/getsentry/sentry-go/gin"
+	"github.com/gin-gonic/gin"
+	"github.com/luulethe/quiz/go_common/sentry"
+	"github.com/luulethe/quiz/quiz_lib/manager"
+	pb "github.com/luulethe/quiz/quiz_lib/pb/gen"
+	"github.com/luulethe/quiz/quiz_lib/quiz_error"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// httpRoute maps a REST endpoint onto a command of routers. The request message is decoded from the JSON body,
+// then its fields named like the path params (":id" is quiz_id) and the query params are set from them.
+type httpRoute struct {
+	method     string
+	path       string
+	command    pb.Command
+	newRequest func() proto.Message
+	newReply   func() proto.Message
+}
+
+// the service commands, e.g. CMD_GET_LEADERBOARD_RANKS, are only served over grpc
+var httpRoutes = []httpRoute{
+	{http.MethodPost, "/quizzes/:id/join", pb.Command_CMD_JOIN_QUIZ,
+		func() proto.Message { return &pb.JoinQuizRequest{} }, func() proto.Message { return &pb.JoinQuizRequestReply{} }},
+	{http.MethodPost, "/quizzes/:id/answers", pb.Command_CMD_SUBMIT_ANSWER,
+		func() proto.Message { return &pb.SubmitAnswerRequest{} }, func() proto.Message { return &pb.SubmitAnswerReply{} }},
+	{http.MethodGet, "/quizzes/:id/leaderboard", pb.Command_CMD_GET_LEADERBOARD,
+		func() proto.Message { return &pb.GetLeaderBoardRequest{} }, func() proto.Message { return &pb.GetLeaderBoardReply{} }},
+
+	{http.MethodPost, "/admin/quizzes", pb.Command_CMD_ADMIN_CREATE_QUIZ,
+		func() proto.Message { return &pb.AdminCreateQuizRequest{} }, func() proto.Message { return &pb.AdminCreateQuizReply{} }},
+	{http.MethodPut, "/admin/quizzes/:id", pb.Command_CMD_ADMIN_UPDATE_QUIZ,
+		func() proto.Message { return &pb.AdminUpdateQuizRequest{} }, func() proto.Message { return &pb.AdminUpdateQuizReply{} }},
+	{http.MethodDelete, "/admin/quizzes/:id", pb.Command_CMD_ADMIN_DELETE_QUIZ,
+		func() proto.Message { return &pb.AdminDeleteQuizRequest{} }, func() proto.Message { return &pb.AdminDeleteQuizReply{} }},
+	{http.MethodPost, "/admin/quizzes/:id/questions", pb.Command_CMD_ADMIN_ADD_QUESTION,
+		func() proto.Message { return &pb.AdminAddQuestionRequest{} }, func() proto.Message { return &pb.AdminAddQuestionReply{} }},
+	{http.MethodPut, "/admin/quizzes/:id/questions/order", pb.Command_CMD_ADMIN_REORDER_QUESTIONS,
+		func() proto.Message { return &pb.AdminReorderQuestionsRequest{} }, func() proto.Message { return &pb.AdminReorderQuestionsReply{} }},
+	{http.MethodPost, "/admin/quizzes/:id/publish", pb.Command_CMD_ADMIN_PUBLISH_QUIZ,
+		func() proto.Message { return &pb.AdminPublishQuizRequest{} }, func() proto.Message { return &pb.AdminPublishQuizReply{} }},
+	{http.MethodPost, "/admin/quizzes/:id/close", pb.Command_CMD_ADMIN_CLOSE_QUIZ,
+		func() proto.Message { return &pb.AdminCloseQuizRequest{} }, func() proto.Message { return &pb.AdminCloseQuizReply{} }},
+	{http.MethodPost, "/admin/quizzes/:id/pause", pb.Command_CMD_ADMIN_PAUSE_QUIZ,
+		func() proto.Message { return &pb.AdminPauseQuizRequest{} }, func() proto.Message { return &pb.AdminPauseQuizReply{} }},
+	{http.MethodPost, "/admin/quizzes/:id/resume", pb.Command_CMD_ADMIN_RESUME_QUIZ,
+		func() proto.Message { return &pb.AdminResumeQuizRequest{} }, func() proto.Message { return &pb.AdminResumeQuizReply{} }},
+}
+
+// httpMetadataHeaders are copied into the incoming grpc metadata, so the middlewares see the same caller as over grpc
+var httpMetadataHeaders = []string{"Authorization", AdminKeyMetadata}
+
+// maxHTTPBodySize bounds the body read for a request, a bigger one is answered with 413
+const maxHTTPBodySize = 1 << 20
+
+var errHTTPBodyTooLarge = errors.New("http body too large")
+
+var (
+	jsonUnmarshalOptions = protojson.UnmarshalOptions{DiscardUnknown: true}
+	jsonMarshalOptions   = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+)
+
+// NewHTTPServer serves the REST/JSON endpoints of httpRoutes, the reply is {"result": "ERROR_OK", "data": {...}}
+func NewHTTPServer(ctx context.Context, dep *manager.Dependency) http.Handler {
+	engine := gin.New()
+	engine.Use(gin.Recovery(), sentrygin.New(sentrygin.Options{Repanic: true}), sentry.ContextMiddleware())
+	for _, route := range httpRoutes {
+		engine.Handle(route.method, route.path, httpHandler(dep, route))
+	}
+	return engine
+}
+
+func httpHandler(dep *manager.Dependency, route httpRoute) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		request := route.newRequest()
+		err := decodeHTTPRequest(c, request)
+		if errors.Is(err, errHTTPBodyTooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"result": pb.Error_ERROR_INVALID_PARAMETER.String()})
+			return
+		}
+		if err != nil {
+			writeHTTPResult(c, pb.Error_ERROR_INVALID_PARAMETER, nil)
+			return
+		}
+		data, err := proto.Marshal(request)
+		if err != nil {
+			writeHTTPResult(c, pb.Error_ERROR_INVALID_PARAMETER, nil)
+			return
+		}
+
+		command := route.command
+		requestData := &pb.RequestData{Command: command, Request: data, RequestId: c.GetHeader(IdempotencyKeyMetadata)}
+		res, err := handleCommand(httpContext(c), dep, &command, requestData)
+		if err != nil {
+			writeHTTPResult(c, pb.Error_ERROR_INTERNAL, nil)
+			return
+		}
+		if res.Result != pb.Error_ERROR_OK {
+			writeHTTPResult(c, res.Result, nil)
+			return
+		}
+
+		reply := route.newReply()
+		if err := proto.Unmarshal(res.Response, reply); err != nil {
+			writeHTTPResult(c, pb.Error_ERROR_INTERNAL, nil)
+			return
+		}
+		writeHTTPResult(c, res.Result, reply)
+	}
+}
+
+func httpContext(c *gin.Context) context.Context {
+	md := metadata.MD{}
+	for _, header := range httpMetadataHeaders {
+		if value := c.GetHeader(header); value != "" {
+			md.Set(header, value)
+		}
+	}
+	return metadata.NewIncomingContext(c.Request.Context(), md)
+}
+
+func decodeHTTPRequest(c *gin.Context, request proto.Message) error {
+	body, err := ioutil.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxHTTPBodySize))
+	if err != nil && len(body) >= maxHTTPBodySize {
+		return errHTTPBodyTooLarge
+	}
+	if err != nil {
+		return err
+	}
+	if len(body) > 0 {
+		if err := jsonUnmarshalOptions.Unmarshal(body, request); err != nil {
+			return err
+		}
+	}
+
+	for _, param := range c.Params {
+		name := param.Key
+		if name == "id" {
+			name = "quiz_id"
+		}
+		if err := setField(request, name, param.Value); err != nil {
+			return err
+		}
+	}
+	for name, values := range c.Request.URL.Query() {
+		if err := setField(request, name, strings.Join(values, ",")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setField parses value into the scalar field of the name, a repeated field takes comma separated values.
+// Unknown names are ignored.
+func setField(message proto.Message, name string, value string) error {
+	reflected := message.ProtoReflect()
+	field := reflected.Descriptor().Fields().ByName(protoreflect.Name(name))
+	if field == nil {
+		return nil
+	}
+	if !field.IsList() {
+		fieldValue, err := parseFieldValue(field, value)
+		if err != nil {
+			return err
+		}
+		reflected.Set(field, fieldValue)
+		return nil
+	}
+
+	list := reflected.Mutable(field).List()
+	for _, item := range strings.Split(value, ",") {
+		fieldValue, err := parseFieldValue(field, item)
+		if err != nil {
+			return err
+		}
+		list.Append(fieldValue)
+	}
+	return nil
+}
+
+func parseFieldValue(field protoreflect.FieldDescriptor, value string) (protoreflect.Value, error) {
+	switch field.Kind() {
+	case protoreflect.Int64Kind:
+		v, err := strconv.ParseInt(value, 10, 64)
+		return protoreflect.ValueOfInt64(v), err
+	case protoreflect.Int32Kind:
+		v, err := strconv.ParseInt(value, 10, 32)
+		return protoreflect.ValueOfInt32(int32(v)), err
+	case protoreflect.BoolKind:
+		v, err := strconv.ParseBool(value)
+		return protoreflect.ValueOfBool(v), err
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(value), nil
+	}
+	return protoreflect.Value{}, fmt.Errorf("unsupported field %s", field.Name())
+}
+
+func writeHTTPResult(c *gin.Context, result pb.Error, reply proto.Message) {
+	body := gin.H{"result": result.String()}
+	if reply != nil {
+		data, err := jsonMarshalOptions.Marshal(reply)
+		if err != nil {
+			result = pb.Error_ERROR_INTERNAL
+			body["result"] = result.String()
+		} else {
+			body["data"] = json.RawMessage(data)
+		}
+	}
+	c.JSON(httpStatus(result), body)
+}
+
+// httpStatus follows the grpc-gateway mapping of the grpc code of the result
+func httpStatus(result pb.Error) int {
+	switch quiz_error.Code(result) {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument, codes.FailedPrecondition:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
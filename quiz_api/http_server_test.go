@@ -0,0 +1,76 @@
+package quiz_api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pb "github.com/luulethe/quiz/quiz_lib/pb/gen"
+	"github.com/luulethe/quiz/quiz_lib/quiz_error"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+)
+
+func serveHTTP(method string, path string, body string) *httptest.ResponseRecorder {
+	handler := NewHTTPServer(context.Background(), newTestDependency())
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return recorder
+}
+
+func TestHTTPServer_BodyTooLarge(t *testing.T) {
+	body := `{"name": "` + strings.Repeat("a", maxHTTPBodySize) + `"}`
+	recorder := serveHTTP(http.MethodPost, "/admin/quizzes", body)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+	assert.JSONEq(t, `{"result": "ERROR_INVALID_PARAMETER"}`, recorder.Body.String())
+
+	// the admin key is missing, the body was read
+	recorder = serveHTTP(http.MethodPost, "/admin/quizzes", `{"name": "quiz"}`)
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+}
+
+func TestHTTPServer_NoServiceRoutes(t *testing.T) {
+	recorder := serveHTTP(http.MethodGet, "/quizzes/1/leaderboard/ranks?user_ids=1,2", "")
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestHTTPStatus(t *testing.T) {
+	tests := []struct {
+		result pb.Error
+		code   codes.Code
+		status int
+	}{
+		{pb.Error_ERROR_OK, codes.OK, http.StatusOK},
+		{pb.Error_ERROR_QUIZ_NOT_EXITED, codes.NotFound, http.StatusNotFound},
+		{pb.Error_ERROR_QUIZ_FINISHED, codes.FailedPrecondition, http.StatusBadRequest},
+		{pb.Error_ERROR_USER_JOINED, codes.AlreadyExists, http.StatusConflict},
+		{pb.Error_ERROR_USER_NOT_EXISTED, codes.NotFound, http.StatusNotFound},
+		{pb.Error_ERROR_USER_NOT_JOINED, codes.FailedPrecondition, http.StatusBadRequest},
+		{pb.Error_ERROR_QUESTION_NOT_EXISTED, codes.NotFound, http.StatusNotFound},
+		{pb.Error_ERROR_ANSWER_NOT_EXISTED, codes.NotFound, http.StatusNotFound},
+		{pb.Error_ERROR_ANSWER_SUBMITTED, codes.AlreadyExists, http.StatusConflict},
+		{pb.Error_ERROR_INVALID_PARAMETER, codes.InvalidArgument, http.StatusBadRequest},
+		{pb.Error_ERROR_INTERNAL, codes.Internal, http.StatusInternalServerError},
+		{pb.Error_ERROR_QUIZ_NOT_STARTED, codes.FailedPrecondition, http.StatusBadRequest},
+		{pb.Error_ERROR_QUIZ_PAUSED, codes.FailedPrecondition, http.StatusBadRequest},
+		{pb.Error_ERROR_QUESTION_CLOSED, codes.FailedPrecondition, http.StatusBadRequest},
+		{pb.Error_ERROR_INVALID_QUIZ_STATUS, codes.FailedPrecondition, http.StatusBadRequest},
+		{pb.Error_ERROR_PERMISSION_DENIED, codes.PermissionDenied, http.StatusForbidden},
+		{pb.Error_ERROR_UNAUTHENTICATED, codes.Unauthenticated, http.StatusUnauthorized},
+		{pb.Error_ERROR_RATE_LIMITED, codes.ResourceExhausted, http.StatusTooManyRequests},
+		{pb.Error_ERROR_REQUEST_IN_PROGRESS, codes.Aborted, http.StatusConflict},
+		{pb.Error_ERROR_SERVICE_UNAVAILABLE, codes.Unavailable, http.StatusServiceUnavailable},
+		// a result added by a newer server
+		{pb.Error(9999), codes.Internal, http.StatusInternalServerError},
+	}
+	// every result is mapped on purpose
+	assert.Len(t, tests, len(pb.Error_name)+1)
+	for _, test := range tests {
+		t.Run(test.result.String(), func(t *testing.T) {
+			assert.Equal(t, test.code, quiz_error.Code(test.result))
+			assert.Equal(t, test.status, httpStatus(test.result))
+		})
+	}
+}
@@ -2,14 +2,22 @@ package quiz_api
 
 import (
 	"context"
+	"crypto/subtle"
+	"errors"
 	"fmt"
+	"strings"
 
 	basesentry "github.com/getsentry/sentry-go"
 	"github.com/google/uuid"
+	"github.com/luulethe/quiz/config"
+	"github.com/luulethe/quiz/go_common/auth"
 	"github.com/luulethe/quiz/go_common/log"
 	"github.com/luulethe/quiz/go_common/sentry"
 	"github.com/luulethe/quiz/quiz_lib/manager"
 	pb "github.com/luulethe/quiz/quiz_lib/pb/gen"
+	"github.com/luulethe/quiz/quiz_lib/quiz_error"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
 
 	"time"
 )
@@ -83,8 +91,263 @@ func MetricsMiddleware(handlerFunc HandlerFunc) HandlerFunc {
 	}
 }
 
+// ErrorMiddleware answers the business rejections of the handler in response.Result, so that they are replayed
+// and counted as handled requests. Only the faults are returned, to be reported by handleCommand.
+func ErrorMiddleware(handlerFunc HandlerFunc) HandlerFunc {
+	return func(ctx context.Context, dep *manager.Dependency, request *pb.RequestData, response *pb.ResponseData) (err error) {
+		err = handlerFunc(ctx, dep, request, response)
+		if rejection, ok := quiz_error.AsRejection(err); ok {
+			log.Infof(ctx, "ErrorMiddleware|rejected|command:%s|err:%v", request.Command.String(), rejection)
+			response.Result = rejection.Result
+			response.Response = nil
+			return nil
+		}
+		return err
+	}
+}
+
+// AdminKeyMetadata is the gRPC metadata key carrying the key of an admin caller
+const AdminKeyMetadata = "x-admin-key"
+
+// AdminMiddleware only lets through the callers presenting one of the configured admin keys
+func AdminMiddleware(handlerFunc HandlerFunc) HandlerFunc {
+	return func(ctx context.Context, dep *manager.Dependency, request *pb.RequestData, response *pb.ResponseData) (err error) {
+		if !isAdmin(ctx, dep) {
+			log.Infof(ctx, "AdminMiddleware|permission denied|command:%s", request.Command.String())
+			response.Result = pb.Error_ERROR_PERMISSION_DENIED
+			return nil
+		}
+		return handlerFunc(ctx, dep, request, response)
+	}
+}
+
+func isAdmin(ctx context.Context, dep *manager.Dependency) bool {
+	return dep.Conf != nil && hasKey(ctx, AdminKeyMetadata, dep.Conf.Admin.Keys)
+}
+
+// ServiceKeyMetadata is the gRPC metadata key carrying the key of an internal service, e.g. the websocket gateway
+const ServiceKeyMetadata = "x-service-key"
+
+// ServiceMiddleware only lets through the internal services presenting one of the configured service keys,
+// and the admin callers. A service key only grants the read-only commands of serviceMiddlewareGroup.
+func ServiceMiddleware(handlerFunc HandlerFunc) HandlerFunc {
+	return func(ctx context.Context, dep *manager.Dependency, request *pb.RequestData, response *pb.ResponseData) (err error) {
+		if !isService(ctx, dep) && !isAdmin(ctx, dep) {
+			log.Infof(ctx, "ServiceMiddleware|permission denied|command:%s", request.Command.String())
+			response.Result = pb.Error_ERROR_PERMISSION_DENIED
+			return nil
+		}
+		return handlerFunc(ctx, dep, request, response)
+	}
+}
+
+func isService(ctx context.Context, dep *manager.Dependency) bool {
+	return dep.Conf != nil && hasKey(ctx, ServiceKeyMetadata, dep.Conf.Admin.ServiceKeys)
+}
+
+// hasKey tells if the metadata key of the caller holds one of keys
+func hasKey(ctx context.Context, metadataKey string, keys []string) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	for _, value := range md.Get(metadataKey) {
+		for _, key := range keys {
+			if key != "" && subtle.ConstantTimeCompare([]byte(value), []byte(key)) == 1 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AuthorizationMetadata is the gRPC metadata key carrying the "Bearer <token>" of the user
+const AuthorizationMetadata = "authorization"
+
+// AuthMiddleware verifies the user token and puts the user id into the context, handlers take the user from it
+// with auth.UserIDFromContext rather than from the request payload
+func AuthMiddleware(handlerFunc HandlerFunc) HandlerFunc {
+	return func(ctx context.Context, dep *manager.Dependency, request *pb.RequestData, response *pb.ResponseData) (err error) {
+		userID, ok := authenticate(ctx, dep)
+		if !ok {
+			log.Infof(ctx, "AuthMiddleware|unauthenticated|command:%s", request.Command.String())
+			response.Result = pb.Error_ERROR_UNAUTHENTICATED
+			return nil
+		}
+		ctx = log.WithFields(auth.WithUserID(ctx, userID), log.Fields{"user_id": userID})
+		return handlerFunc(ctx, dep, request, response)
+	}
+}
+
+func authenticate(ctx context.Context, dep *manager.Dependency) (int64, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || dep.Auth == nil {
+		return 0, false
+	}
+	for _, value := range md.Get(AuthorizationMetadata) {
+		if !strings.HasPrefix(value, "Bearer ") {
+			continue
+		}
+		if userID, err := dep.Auth.Verify(strings.TrimPrefix(value, "Bearer ")); err == nil {
+			return userID, true
+		}
+	}
+	return 0, false
+}
+
+// quizRequest is a request of a quiz, which can be limited per quiz
+type quizRequest interface {
+	proto.Message
+	GetQuizId() int64
+}
+
+var quizRequests = map[pb.Command]func() quizRequest{
+	pb.Command_CMD_JOIN_QUIZ:       func() quizRequest { return &pb.JoinQuizRequest{} },
+	pb.Command_CMD_SUBMIT_ANSWER:   func() quizRequest { return &pb.SubmitAnswerRequest{} },
+	pb.Command_CMD_GET_LEADERBOARD: func() quizRequest { return &pb.GetLeaderBoardRequest{} },
+}
+
+// RateLimitMiddleware takes a token from the buckets of the authenticated user and of the quiz of the command,
+// as configured in rate_limit.commands of the active config. The requests are let through when the limiter fails.
+func RateLimitMiddleware(handlerFunc HandlerFunc) HandlerFunc {
+	return func(ctx context.Context, dep *manager.Dependency, request *pb.RequestData, response *pb.ResponseData) (err error) {
+		if dep.RateLimiter == nil || dep.Config() == nil {
+			return handlerFunc(ctx, dep, request, response)
+		}
+		limits, ok := dep.Config().RateLimit.Commands[request.Command.String()]
+		if !ok {
+			return handlerFunc(ctx, dep, request, response)
+		}
+
+		if userID, ok := auth.UserIDFromContext(ctx); ok && limits.PerUser.Rate > 0 {
+			if !allowRequest(ctx, dep, request.Command, fmt.Sprintf("%s_user_%d", request.Command, userID), limits.PerUser) {
+				response.Result = pb.Error_ERROR_RATE_LIMITED
+				return nil
+			}
+		}
+		if newRequest, ok := quizRequests[request.Command]; ok && limits.PerQuiz.Rate > 0 {
+			quizRequest := newRequest()
+			if proto.Unmarshal(request.Request, quizRequest) == nil && quizRequest.GetQuizId() > 0 {
+				key := fmt.Sprintf("%s_quiz_%d", request.Command, quizRequest.GetQuizId())
+				if !allowRequest(ctx, dep, request.Command, key, limits.PerQuiz) {
+					response.Result = pb.Error_ERROR_RATE_LIMITED
+					return nil
+				}
+			}
+		}
+		return handlerFunc(ctx, dep, request, response)
+	}
+}
+
+func allowRequest(ctx context.Context, dep *manager.Dependency, command pb.Command, key string, limit config.RateLimit) bool {
+	err, allowed := dep.RateLimiter.Allow(ctx, key, limit)
+	if err != nil {
+		// the limiter has logged the error, the request is let through
+		if dep.Stats != nil {
+			dep.Stats.ReportCount(1, "RateLimitError", command.String())
+		}
+		return true
+	}
+	if !allowed {
+		log.Infof(ctx, "RateLimitMiddleware|rate limited|key:%s", key)
+		if dep.Stats != nil {
+			dep.Stats.ReportCount(1, "RateLimited", command.String())
+		}
+	}
+	return allowed
+}
+
+const maxRequestIDLength = 64
+
+// idempotentCommands are the mutating commands replayed by their request_id
+var idempotentCommands = map[pb.Command]bool{
+	pb.Command_CMD_JOIN_QUIZ:     true,
+	pb.Command_CMD_SUBMIT_ANSWER: true,
+}
+
+// IdempotencyMiddleware replays the first response of the command and request_id of the authenticated user,
+// a duplicate arriving while the first request is in flight gets ERROR_REQUEST_IN_PROGRESS and one with
+// another payload ERROR_INVALID_PARAMETER. The request runs without the idempotency check when the store fails.
+func IdempotencyMiddleware(handlerFunc HandlerFunc) HandlerFunc {
+	return func(ctx context.Context, dep *manager.Dependency, request *pb.RequestData, response *pb.ResponseData) (err error) {
+		userID, ok := auth.UserIDFromContext(ctx)
+		if !ok || request.RequestId == "" || dep.Idempotency == nil || !idempotentCommands[request.Command] {
+			return handlerFunc(ctx, dep, request, response)
+		}
+		if len(request.RequestId) > maxRequestIDLength {
+			response.Result = pb.Error_ERROR_INVALID_PARAMETER
+			return nil
+		}
+
+		idempotent := &manager.IdempotentRequest{
+			UserID: userID, Command: request.Command, RequestID: request.RequestId, Payload: request.Request,
+		}
+		err, stored, started := dep.Idempotency.Begin(ctx, idempotent)
+		if errors.Is(err, manager.ErrRequestIDReused) {
+			log.Warnf(ctx, "IdempotencyMiddleware|Reused|request_id:%s", request.RequestId)
+			response.Result = quiz_error.Result(err)
+			return nil
+		}
+		if err != nil {
+			return handlerFunc(ctx, dep, request, response)
+		}
+		if !started {
+			result := "InProgress"
+			if stored != nil {
+				result = "Replayed"
+				response.Result, response.Response = stored.Result, stored.Response
+			} else {
+				response.Result = pb.Error_ERROR_REQUEST_IN_PROGRESS
+			}
+			log.Infof(ctx, "IdempotencyMiddleware|%s|request_id:%s", result, request.RequestId)
+			if dep.Stats != nil {
+				dep.Stats.ReportCount(1, "Idempotency", result)
+			}
+			return nil
+		}
+
+		err = handlerFunc(ctx, dep, request, response)
+		if err != nil {
+			_ = dep.Idempotency.Abort(ctx, idempotent)
+			return err
+		}
+		_ = dep.Idempotency.Complete(ctx, idempotent, response)
+		return nil
+	}
+}
+
+// middlewareGroup runs AuthMiddleware, RateLimitMiddleware then IdempotencyMiddleware, which need the
+// authenticated user, innermost so that rejected calls are still logged and counted. ErrorMiddleware is
+// the innermost one, so that IdempotencyMiddleware stores the rejections as responses.
 var middlewareGroup = MiddlewareGroup{
 	Middlewares: []GRPCMiddleware{
+		ErrorMiddleware,
+		IdempotencyMiddleware,
+		RateLimitMiddleware,
+		AuthMiddleware,
+		LogMiddleware,
+		SentryMiddleware,
+		MetricsMiddleware,
+	},
+}
+
+// serviceMiddlewareGroup is the adminMiddlewareGroup of the read-only commands of the internal services
+var serviceMiddlewareGroup = MiddlewareGroup{
+	Middlewares: []GRPCMiddleware{
+		ErrorMiddleware,
+		ServiceMiddleware,
+		LogMiddleware,
+		SentryMiddleware,
+		MetricsMiddleware,
+	},
+}
+
+// adminMiddlewareGroup has AdminMiddleware innermost after ErrorMiddleware, so that denied calls are still
+// logged and counted
+var adminMiddlewareGroup = MiddlewareGroup{
+	Middlewares: []GRPCMiddleware{
+		ErrorMiddleware,
+		AdminMiddleware,
 		LogMiddleware,
 		SentryMiddleware,
 		MetricsMiddleware,
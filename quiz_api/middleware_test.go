@@ -0,0 +1,144 @@
+package quiz_api
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/luulethe/quiz/config"
+	"github.com/luulethe/quiz/quiz_lib/manager"
+	"github.com/luulethe/quiz/quiz_lib/manager/managertest"
+	pb "github.com/luulethe/quiz/quiz_lib/pb/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+func newTestDependency() *manager.Dependency {
+	dep := &manager.Dependency{
+		Conf: &config.Configuration{
+			Admin: config.AdminConfig{Keys: []string{"admin-key"}, ServiceKeys: []string{"service-key"}},
+		},
+		QuizDAO: managertest.NewQuizDAO(),
+	}
+	dep.QuizManager = manager.NewQuizManager(dep)
+	return dep
+}
+
+func handleWithMetadata(t *testing.T, dep *manager.Dependency, command pb.Command, request proto.Message, pairs ...string) *pb.ResponseData {
+	data, err := proto.Marshal(request)
+	require.NoError(t, err)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(pairs...))
+	response, err := handleCommand(ctx, dep, &command, &pb.RequestData{Command: command, Request: data})
+	require.NoError(t, err)
+	return response
+}
+
+func TestServiceKey(t *testing.T) {
+	dep := newTestDependency()
+	ranks := &pb.GetLeaderBoardRanksRequest{QuizId: 1}
+	createQuiz := &pb.AdminCreateQuizRequest{Name: "quiz"}
+
+	// the quiz doesn't exist, the command went through
+	response := handleWithMetadata(t, dep, pb.Command_CMD_GET_LEADERBOARD_RANKS, ranks, ServiceKeyMetadata, "service-key")
+	assert.Equal(t, pb.Error_ERROR_QUIZ_NOT_EXITED, response.Result)
+	response = handleWithMetadata(t, dep, pb.Command_CMD_GET_LEADERBOARD_RANKS, ranks, AdminKeyMetadata, "admin-key")
+	assert.Equal(t, pb.Error_ERROR_QUIZ_NOT_EXITED, response.Result)
+
+	response = handleWithMetadata(t, dep, pb.Command_CMD_ADMIN_CREATE_QUIZ, createQuiz, ServiceKeyMetadata, "service-key")
+	assert.Equal(t, pb.Error_ERROR_PERMISSION_DENIED, response.Result)
+	response = handleWithMetadata(t, dep, pb.Command_CMD_ADMIN_CREATE_QUIZ, createQuiz, AdminKeyMetadata, "service-key")
+	assert.Equal(t, pb.Error_ERROR_PERMISSION_DENIED, response.Result)
+	response = handleWithMetadata(t, dep, pb.Command_CMD_GET_LEADERBOARD_RANKS, ranks, ServiceKeyMetadata, "admin")
+	assert.Equal(t, pb.Error_ERROR_PERMISSION_DENIED, response.Result)
+}
+
+func TestAdminKey(t *testing.T) {
+	dep := newTestDependency()
+
+	admins := 0
+	for command := range routers {
+		if !strings.HasPrefix(command.String(), "CMD_ADMIN_") {
+			continue
+		}
+		admins++
+		request := &pb.AdminDeleteQuizRequest{QuizId: 1}
+		response := handleWithMetadata(t, dep, command, request)
+		assert.Equal(t, pb.Error_ERROR_PERMISSION_DENIED, response.Result, command.String())
+		response = handleWithMetadata(t, dep, command, request, AdminKeyMetadata, "wrong-key")
+		assert.Equal(t, pb.Error_ERROR_PERMISSION_DENIED, response.Result, command.String())
+		response = handleWithMetadata(t, dep, command, request, ServiceKeyMetadata, "service-key")
+		assert.Equal(t, pb.Error_ERROR_PERMISSION_DENIED, response.Result, command.String())
+	}
+	assert.Equal(t, 9, admins)
+
+	response := handleWithMetadata(t, dep, pb.Command_CMD_ADMIN_CREATE_QUIZ, &pb.AdminCreateQuizRequest{Name: "quiz"},
+		AdminKeyMetadata, "admin-key")
+	require.Equal(t, pb.Error_ERROR_OK, response.Result)
+	reply := &pb.AdminCreateQuizReply{}
+	require.NoError(t, proto.Unmarshal(response.Response, reply))
+	assert.NotZero(t, reply.QuizId)
+}
+
+// failingLimiter fails every call, as a redis limiter while redis is down
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(ctx context.Context, key string, limit config.RateLimit) (error, bool) {
+	return errors.New("redis unavailable"), false
+}
+
+func newRateLimitedDependency(commands map[string]config.CommandRateLimit) *manager.Dependency {
+	dep := newTestDependency()
+	dep.Conf.Auth = config.AuthConfig{Keys: []config.AuthKey{{ID: "test", Secret: "test-secret"}}}
+	dep.Conf.RateLimit = config.RateLimitConfig{Backend: manager.RateLimitBackendMemory, Commands: commands}
+	dep.Auth = manager.NewTokenVerifier(dep.Conf.Auth)
+	dep.RateLimiter = manager.NewMemoryRateLimiter()
+	return dep
+}
+
+// joinQuiz joins a missing quiz, ERROR_QUIZ_NOT_EXITED tells the request went through the limiter
+func joinQuiz(t *testing.T, dep *manager.Dependency, userID int64, quizID int64) pb.Error {
+	data, err := proto.Marshal(&pb.JoinQuizRequest{QuizId: quizID})
+	require.NoError(t, err)
+	command := pb.Command_CMD_JOIN_QUIZ
+	response, err := handleCommand(userContext(t, userID), dep, &command, &pb.RequestData{Command: command, Request: data})
+	require.NoError(t, err)
+	return response.Result
+}
+
+func TestRateLimit_PerUser(t *testing.T) {
+	dep := newRateLimitedDependency(map[string]config.CommandRateLimit{
+		"CMD_JOIN_QUIZ": {PerUser: config.RateLimit{Rate: 0.001, Burst: 2}},
+	})
+
+	// the bucket of a user is shared by its quizzes, not by the other users
+	assert.Equal(t, pb.Error_ERROR_QUIZ_NOT_EXITED, joinQuiz(t, dep, 1, 100))
+	assert.Equal(t, pb.Error_ERROR_QUIZ_NOT_EXITED, joinQuiz(t, dep, 1, 101))
+	assert.Equal(t, pb.Error_ERROR_RATE_LIMITED, joinQuiz(t, dep, 1, 102))
+	assert.Equal(t, pb.Error_ERROR_QUIZ_NOT_EXITED, joinQuiz(t, dep, 2, 102))
+}
+
+func TestRateLimit_PerQuiz(t *testing.T) {
+	dep := newRateLimitedDependency(map[string]config.CommandRateLimit{
+		"CMD_JOIN_QUIZ": {PerQuiz: config.RateLimit{Rate: 0.001, Burst: 2}},
+	})
+
+	// the bucket of a quiz is shared by its users, not by the other quizzes
+	assert.Equal(t, pb.Error_ERROR_QUIZ_NOT_EXITED, joinQuiz(t, dep, 1, 100))
+	assert.Equal(t, pb.Error_ERROR_QUIZ_NOT_EXITED, joinQuiz(t, dep, 2, 100))
+	assert.Equal(t, pb.Error_ERROR_RATE_LIMITED, joinQuiz(t, dep, 3, 100))
+	assert.Equal(t, pb.Error_ERROR_QUIZ_NOT_EXITED, joinQuiz(t, dep, 3, 101))
+}
+
+func TestRateLimit_FailsOpen(t *testing.T) {
+	dep := newRateLimitedDependency(map[string]config.CommandRateLimit{
+		"CMD_JOIN_QUIZ": {PerUser: config.RateLimit{Rate: 0.001, Burst: 1}, PerQuiz: config.RateLimit{Rate: 0.001, Burst: 1}},
+	})
+	dep.RateLimiter = failingLimiter{}
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, pb.Error_ERROR_QUIZ_NOT_EXITED, joinQuiz(t, dep, 1, 100))
+	}
+}
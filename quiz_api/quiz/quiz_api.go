@@ -3,8 +3,10 @@ package quiz
 import (
 	"context"
 
+	"github.com/luulethe/quiz/go_common/auth"
 	"github.com/luulethe/quiz/quiz_lib/manager"
 	pb "github.com/luulethe/quiz/quiz_lib/pb/gen"
+	"github.com/luulethe/quiz/quiz_lib/quiz_error"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -12,10 +14,15 @@ func JoinQuiz(ctx context.Context, dep *manager.Dependency, request *pb.RequestD
 	requestData := pb.JoinQuizRequest{}
 	err = proto.Unmarshal(request.Request, &requestData)
 	if err != nil {
-		return
+		return quiz_error.Wrap(pb.Error_ERROR_INVALID_PARAMETER, err)
+	}
+	userID, ok := auth.UserIDFromContext(ctx)
+	if !ok {
+		response.Result = pb.Error_ERROR_UNAUTHENTICATED
+		return nil
 	}
 
-	err, joinStatus := dep.QuizManager.JoinQuiz(ctx, requestData.QuizId, requestData.UserId)
+	err = dep.QuizManager.JoinQuiz(ctx, requestData.QuizId, userID)
 	if err != nil {
 		return err
 	}
@@ -23,7 +30,96 @@ func JoinQuiz(ctx context.Context, dep *manager.Dependency, request *pb.RequestD
 	reply := pb.JoinQuizRequestReply{}
 
 	response.Response, err = proto.Marshal(&reply)
-	response.Result = joinStatus
 
 	return err
 }
+
+func SubmitAnswer(ctx context.Context, dep *manager.Dependency, request *pb.RequestData, response *pb.ResponseData) (err error) {
+	requestData := pb.SubmitAnswerRequest{}
+	err = proto.Unmarshal(request.Request, &requestData)
+	if err != nil {
+		return quiz_error.Wrap(pb.Error_ERROR_INVALID_PARAMETER, err)
+	}
+	userID, ok := auth.UserIDFromContext(ctx)
+	if !ok {
+		response.Result = pb.Error_ERROR_UNAUTHENTICATED
+		return nil
+	}
+
+	result, err := dep.QuizManager.SubmitAnswer(
+		ctx, requestData.QuizId, userID, requestData.QuestionId, requestData.AnswerId,
+	)
+	if err != nil {
+		return err
+	}
+
+	reply := pb.SubmitAnswerReply{}
+	if result != nil {
+		reply.Correct = result.Correct
+		reply.Score = result.Score
+		reply.TotalScore = result.TotalScore
+	}
+
+	response.Response, err = proto.Marshal(&reply)
+
+	return err
+}
+
+func GetLeaderBoard(ctx context.Context, dep *manager.Dependency, request *pb.RequestData, response *pb.ResponseData) (err error) {
+	requestData := pb.GetLeaderBoardRequest{}
+	err = proto.Unmarshal(request.Request, &requestData)
+	if err != nil {
+		return quiz_error.Wrap(pb.Error_ERROR_INVALID_PARAMETER, err)
+	}
+
+	entries, total, err := dep.QuizManager.GetLeaderBoard(
+		ctx, requestData.QuizId, requestData.PageIndex, requestData.PageSize,
+	)
+	if err != nil {
+		return err
+	}
+
+	reply := pb.GetLeaderBoardReply{Total: total, Entries: toLeaderBoardEntries(entries)}
+
+	response.Response, err = proto.Marshal(&reply)
+
+	return err
+}
+
+func GetLeaderBoardRanks(ctx context.Context, dep *manager.Dependency, request *pb.RequestData, response *pb.ResponseData) (err error) {
+	requestData := pb.GetLeaderBoardRanksRequest{}
+	err = proto.Unmarshal(request.Request, &requestData)
+	if err != nil {
+		return quiz_error.Wrap(pb.Error_ERROR_INVALID_PARAMETER, err)
+	}
+
+	ranks, err := dep.QuizManager.GetLeaderBoardRanks(
+		ctx, requestData.QuizId, requestData.TopN, requestData.UserIds, requestData.WithoutTop,
+	)
+	if err != nil {
+		return err
+	}
+
+	reply := pb.GetLeaderBoardRanksReply{}
+	if ranks != nil {
+		reply.Top = toLeaderBoardEntries(ranks.Top)
+		reply.Ranks = toLeaderBoardEntries(ranks.Ranks)
+		reply.Total = ranks.Total
+	}
+
+	response.Response, err = proto.Marshal(&reply)
+
+	return err
+}
+
+func toLeaderBoardEntries(entries []*manager.LeaderBoardEntry) []*pb.LeaderBoardEntry {
+	result := make([]*pb.LeaderBoardEntry, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, &pb.LeaderBoardEntry{
+			UserId: entry.UserID,
+			Score:  entry.Score,
+			Rank:   entry.Rank,
+		})
+	}
+	return result
+}
@@ -1,10 +1,26 @@
 package quiz_api
 
 import (
+	"github.com/luulethe/quiz/quiz_api/admin"
 	"github.com/luulethe/quiz/quiz_api/quiz"
 	pb "github.com/luulethe/quiz/quiz_lib/pb/gen"
 )
 
 var routers = map[pb.Command]HandlerFunc{
-	pb.Command_CMD_JOIN_QUIZ: middlewareGroup.Wrap(quiz.JoinQuiz),
+	pb.Command_CMD_JOIN_QUIZ:       middlewareGroup.Wrap(quiz.JoinQuiz),
+	pb.Command_CMD_SUBMIT_ANSWER:   middlewareGroup.Wrap(quiz.SubmitAnswer),
+	pb.Command_CMD_GET_LEADERBOARD: middlewareGroup.Wrap(quiz.GetLeaderBoard),
+
+	// the ranks of any users are read by the websocket gateway to push the leader board
+	pb.Command_CMD_GET_LEADERBOARD_RANKS: serviceMiddlewareGroup.Wrap(quiz.GetLeaderBoardRanks),
+
+	pb.Command_CMD_ADMIN_CREATE_QUIZ:       adminMiddlewareGroup.Wrap(admin.CreateQuiz),
+	pb.Command_CMD_ADMIN_UPDATE_QUIZ:       adminMiddlewareGroup.Wrap(admin.UpdateQuiz),
+	pb.Command_CMD_ADMIN_ADD_QUESTION:      adminMiddlewareGroup.Wrap(admin.AddQuestion),
+	pb.Command_CMD_ADMIN_REORDER_QUESTIONS: adminMiddlewareGroup.Wrap(admin.ReorderQuestions),
+	pb.Command_CMD_ADMIN_PUBLISH_QUIZ:      adminMiddlewareGroup.Wrap(admin.PublishQuiz),
+	pb.Command_CMD_ADMIN_CLOSE_QUIZ:        adminMiddlewareGroup.Wrap(admin.CloseQuiz),
+	pb.Command_CMD_ADMIN_DELETE_QUIZ:       adminMiddlewareGroup.Wrap(admin.DeleteQuiz),
+	pb.Command_CMD_ADMIN_PAUSE_QUIZ:        adminMiddlewareGroup.Wrap(admin.PauseQuiz),
+	pb.Command_CMD_ADMIN_RESUME_QUIZ:       adminMiddlewareGroup.Wrap(admin.ResumeQuiz),
 }
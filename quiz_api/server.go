@@ -3,9 +3,13 @@ package quiz_api
 import (
 	"context"
 
+	"github.com/luulethe/quiz/go_common/log"
 	"github.com/luulethe/quiz/go_common/sentry"
 	"github.com/luulethe/quiz/quiz_lib/manager"
 	pb "github.com/luulethe/quiz/quiz_lib/pb/gen"
+	"github.com/luulethe/quiz/quiz_lib/quiz_error"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type Server struct {
@@ -22,10 +26,18 @@ func (s *Server) Handle(ctx context.Context, in *pb.RequestData) (*pb.ResponseDa
 }
 
 func handleCommand(ctx context.Context, dep *manager.Dependency, command *pb.Command, in *pb.RequestData) (*pb.ResponseData, error) {
+	handler, ok := routers[*command]
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "unknown command %v", *command)
+	}
+
 	res := &pb.ResponseData{}
-	err := routers[*command](ctx, dep, in, res)
+	err := handler(ctx, dep, in, res)
 	if err != nil {
+		// the rejections are answered in res.Result by ErrorMiddleware, what's left is a fault
+		log.Errorff(ctx, "handleCommand|command:%v|err:%v", *command, err)
 		sentry.CaptureError(ctx, err, 0)
+		return nil, quiz_error.Status(err).Err()
 	}
-	return res, err
+	return res, nil
 }
@@ -0,0 +1,91 @@
+package quiz_api
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/luulethe/quiz/config"
+	"github.com/luulethe/quiz/quiz_lib/manager"
+	pb "github.com/luulethe/quiz/quiz_lib/pb/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestHandle_UnknownCommand(t *testing.T) {
+	server := NewQuizServer(context.Background(), newTestDependency())
+
+	// a command added by a newer client
+	response, err := server.Handle(context.Background(), &pb.RequestData{Command: pb.Command(9999)})
+	assert.Nil(t, response)
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+// resultOf returns the result carried by the status of a typed rpc
+func resultOf(t *testing.T, err error) pb.Error {
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	if st.Code() == codes.OK {
+		return pb.Error_ERROR_OK
+	}
+	for _, detail := range st.Details() {
+		if resultDetail, ok := detail.(*pb.ResultDetail); ok {
+			return resultDetail.Result
+		}
+	}
+	require.Fail(t, "no result detail", "status: %v", st)
+	return pb.Error_ERROR_INTERNAL
+}
+
+func userContext(t *testing.T, userID int64) context.Context {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   strconv.FormatInt(userID, 10),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	})
+	token.Header["kid"] = "test"
+	signed, err := token.SignedString([]byte("test-secret"))
+	require.NoError(t, err)
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(AuthorizationMetadata, "Bearer "+signed))
+}
+
+func TestServerV2_Routing(t *testing.T) {
+	dep := newTestDependency()
+	dep.Conf.Auth = config.AuthConfig{Keys: []config.AuthKey{{ID: "test", Secret: "test-secret"}}}
+	dep.Auth = manager.NewTokenVerifier(dep.Conf.Auth)
+	server := NewQuizServerV2(context.Background(), dep)
+	adminCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(AdminKeyMetadata, "admin-key"))
+
+	// the typed rpcs go through the middlewares and the handlers of their command
+	created, err := server.AdminCreateQuiz(adminCtx, &pb.AdminCreateQuizRequest{Name: "quiz"})
+	require.NoError(t, err)
+	assert.NotZero(t, created.QuizId)
+	_, err = server.AdminAddQuestion(adminCtx, &pb.AdminAddQuestionRequest{
+		QuizId: created.QuizId, Content: "question",
+		Options: []*pb.AdminAnswerOption{{Content: "right", IsCorrect: true}, {Content: "wrong"}},
+	})
+	require.NoError(t, err)
+
+	_, err = server.AdminCreateQuiz(context.Background(), &pb.AdminCreateQuizRequest{Name: "quiz"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Equal(t, pb.Error_ERROR_PERMISSION_DENIED, resultOf(t, err))
+	_, err = server.AdminCreateQuiz(adminCtx, &pb.AdminCreateQuizRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = server.AdminPublishQuiz(adminCtx, &pb.AdminPublishQuizRequest{QuizId: created.QuizId + 100})
+	assert.Equal(t, pb.Error_ERROR_QUESTION_NOT_EXISTED, resultOf(t, err))
+	_, err = server.AdminDeleteQuiz(adminCtx, &pb.AdminDeleteQuizRequest{QuizId: created.QuizId})
+	assert.NoError(t, err)
+
+	_, err = server.JoinQuiz(context.Background(), &pb.JoinQuizRequest{QuizId: created.QuizId})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = server.JoinQuiz(userContext(t, 42), &pb.JoinQuizRequest{QuizId: created.QuizId})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, pb.Error_ERROR_QUIZ_NOT_EXITED, resultOf(t, err))
+
+	_, err = server.GetLeaderBoardRanks(context.Background(), &pb.GetLeaderBoardRanksRequest{QuizId: created.QuizId})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
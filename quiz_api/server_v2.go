@@ -0,0 +1,126 @@
+package quiz_api
+
+import (
+	"context"
+
+	"github.com/luulethe/quiz/quiz_lib/manager"
+	pb "github.com/luulethe/quiz/quiz_lib/pb/gen"
+	"github.com/luulethe/quiz/quiz_lib/quiz_error"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// IdempotencyKeyMetadata is the gRPC metadata key carrying the request_id of a typed rpc
+const IdempotencyKeyMetadata = "idempotency-key"
+
+// ServerV2 serves the typed rpcs of QuizServiceV2 with the handlers of the legacy envelope
+type ServerV2 struct {
+	pb.UnimplementedQuizServiceV2Server
+	dep *manager.Dependency
+}
+
+func NewQuizServerV2(ctx context.Context, dep *manager.Dependency) *ServerV2 {
+	return &ServerV2{dep: dep}
+}
+
+func (s *ServerV2) JoinQuiz(ctx context.Context, in *pb.JoinQuizRequest) (*pb.JoinQuizRequestReply, error) {
+	reply := &pb.JoinQuizRequestReply{}
+	return reply, s.call(ctx, pb.Command_CMD_JOIN_QUIZ, in, reply)
+}
+
+func (s *ServerV2) SubmitAnswer(ctx context.Context, in *pb.SubmitAnswerRequest) (*pb.SubmitAnswerReply, error) {
+	reply := &pb.SubmitAnswerReply{}
+	return reply, s.call(ctx, pb.Command_CMD_SUBMIT_ANSWER, in, reply)
+}
+
+func (s *ServerV2) GetLeaderBoard(ctx context.Context, in *pb.GetLeaderBoardRequest) (*pb.GetLeaderBoardReply, error) {
+	reply := &pb.GetLeaderBoardReply{}
+	return reply, s.call(ctx, pb.Command_CMD_GET_LEADERBOARD, in, reply)
+}
+
+func (s *ServerV2) GetLeaderBoardRanks(
+	ctx context.Context, in *pb.GetLeaderBoardRanksRequest,
+) (*pb.GetLeaderBoardRanksReply, error) {
+	reply := &pb.GetLeaderBoardRanksReply{}
+	return reply, s.call(ctx, pb.Command_CMD_GET_LEADERBOARD_RANKS, in, reply)
+}
+
+func (s *ServerV2) AdminCreateQuiz(ctx context.Context, in *pb.AdminCreateQuizRequest) (*pb.AdminCreateQuizReply, error) {
+	reply := &pb.AdminCreateQuizReply{}
+	return reply, s.call(ctx, pb.Command_CMD_ADMIN_CREATE_QUIZ, in, reply)
+}
+
+func (s *ServerV2) AdminUpdateQuiz(ctx context.Context, in *pb.AdminUpdateQuizRequest) (*pb.AdminUpdateQuizReply, error) {
+	reply := &pb.AdminUpdateQuizReply{}
+	return reply, s.call(ctx, pb.Command_CMD_ADMIN_UPDATE_QUIZ, in, reply)
+}
+
+func (s *ServerV2) AdminAddQuestion(ctx context.Context, in *pb.AdminAddQuestionRequest) (*pb.AdminAddQuestionReply, error) {
+	reply := &pb.AdminAddQuestionReply{}
+	return reply, s.call(ctx, pb.Command_CMD_ADMIN_ADD_QUESTION, in, reply)
+}
+
+func (s *ServerV2) AdminReorderQuestions(
+	ctx context.Context, in *pb.AdminReorderQuestionsRequest,
+) (*pb.AdminReorderQuestionsReply, error) {
+	reply := &pb.AdminReorderQuestionsReply{}
+	return reply, s.call(ctx, pb.Command_CMD_ADMIN_REORDER_QUESTIONS, in, reply)
+}
+
+func (s *ServerV2) AdminPublishQuiz(ctx context.Context, in *pb.AdminPublishQuizRequest) (*pb.AdminPublishQuizReply, error) {
+	reply := &pb.AdminPublishQuizReply{}
+	return reply, s.call(ctx, pb.Command_CMD_ADMIN_PUBLISH_QUIZ, in, reply)
+}
+
+func (s *ServerV2) AdminCloseQuiz(ctx context.Context, in *pb.AdminCloseQuizRequest) (*pb.AdminCloseQuizReply, error) {
+	reply := &pb.AdminCloseQuizReply{}
+	return reply, s.call(ctx, pb.Command_CMD_ADMIN_CLOSE_QUIZ, in, reply)
+}
+
+func (s *ServerV2) AdminDeleteQuiz(ctx context.Context, in *pb.AdminDeleteQuizRequest) (*pb.AdminDeleteQuizReply, error) {
+	reply := &pb.AdminDeleteQuizReply{}
+	return reply, s.call(ctx, pb.Command_CMD_ADMIN_DELETE_QUIZ, in, reply)
+}
+
+func (s *ServerV2) AdminPauseQuiz(ctx context.Context, in *pb.AdminPauseQuizRequest) (*pb.AdminPauseQuizReply, error) {
+	reply := &pb.AdminPauseQuizReply{}
+	return reply, s.call(ctx, pb.Command_CMD_ADMIN_PAUSE_QUIZ, in, reply)
+}
+
+func (s *ServerV2) AdminResumeQuiz(ctx context.Context, in *pb.AdminResumeQuizRequest) (*pb.AdminResumeQuizReply, error) {
+	reply := &pb.AdminResumeQuizReply{}
+	return reply, s.call(ctx, pb.Command_CMD_ADMIN_RESUME_QUIZ, in, reply)
+}
+
+// call runs the router of the command, so both services share the handlers and their middlewares
+func (s *ServerV2) call(ctx context.Context, command pb.Command, in proto.Message, reply proto.Message) error {
+	data, err := proto.Marshal(in)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	request := &pb.RequestData{Command: command, Request: data, RequestId: requestID(ctx)}
+	res, err := handleCommand(ctx, s.dep, &command, request)
+	if err != nil {
+		return err
+	}
+	if res.Result != pb.Error_ERROR_OK {
+		return quiz_error.Status(quiz_error.FromResult(res.Result)).Err()
+	}
+
+	return proto.Unmarshal(res.Response, reply)
+}
+
+// requestID is the idempotency key of the typed rpcs, taken from the metadata
+func requestID(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(IdempotencyKeyMetadata); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -3,10 +3,12 @@ package db
 import (
 	"database/sql"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"time"
 
+	mysqldriver "github.com/go-sql-driver/mysql"
 	"github.com/prometheus/client_golang/prometheus"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -18,6 +20,10 @@ const (
 	MaxDBConns = 50
 	// Maximum lifetime of a connection
 	MaxDBConnLifeTime = time.Minute * 4
+	// MySQL error number of a unique key violation
+	mysqlErrDuplicateEntry = 1062
+	// MySQL error number of a statement needing a privilege the user hasn't, e.g. SHOW SLAVE STATUS
+	mysqlErrSpecificAccessDenied = 1227
 )
 
 // Config defines the database connection settings
@@ -26,16 +32,27 @@ const (
 // DBUsername: database connection user, for both primary db and replica db
 // DBPassword: database connection password, for both primary db and replica db
 // DBName: <optional>, for sharding use only, a list of sharding database names under the DBAddress
+// MaxReplicaLag: <optional>, a replica lagging more is ejected until it catches up
+// StickyMasterWindow: <optional>, reads of a user go to the primary database for the window after its writes
 type Config struct {
 	DBAddress       string        `yaml:"address" xml:"address"`
 	Replica         []string      `yaml:"replica" xml:"replica"`
 	DBUsername      string        `yaml:"username" xml:"username"`
-	DBPassword      string        `yaml:"password" xml:"password"`
+	DBPassword      string        `yaml:"password" xml:"password" secret:"true"`
 	DBName          string        `yaml:"name" xml:"name"`
 	MaxOpenConns    int           `yaml:"max_open_conns" xml:"max_open_conns"`
 	MaxIdleConns    int           `yaml:"max_idle_conns" xml:"max_idle_conns"`
 	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" xml:"conn_max_lifetime"`
-	DriverName      string        `yaml:"-" xml:"-"`
+
+	MaxReplicaLag       time.Duration `yaml:"max_replica_lag" xml:"max_replica_lag"`
+	HealthCheckInterval time.Duration `yaml:"health_check_interval" xml:"health_check_interval"`
+	StickyMasterWindow  time.Duration `yaml:"sticky_master_window" xml:"sticky_master_window"`
+	DriverName          string        `yaml:"-" xml:"-"`
+}
+
+// Connect opens the primary database of the config, without its replicas
+func Connect(config Config) (*gorm.DB, error) {
+	return dbConnect(config)
 }
 
 func dbConnect(config Config) (*gorm.DB, error) {
@@ -80,6 +97,9 @@ func dbConnectToReplica(config Config) ([]*gorm.DB, error) {
 		config.DBAddress = replica
 		replica, err := dbConnect(config)
 		if err != nil {
+			for _, conn := range replicas {
+				closeConn(conn)
+			}
 			return nil, err
 		}
 		replicas = append(replicas, replica)
@@ -104,10 +124,34 @@ func ReadConfigs(rd io.Reader) (conf []Config, err error) {
 	return
 }
 
+func closeConn(conn *gorm.DB) {
+	if sqlDB, err := conn.DB(); err == nil {
+		_ = sqlDB.Close()
+	}
+}
+
+// IsDuplicateKeyError reports whether err is caused by a unique key violation
+func IsDuplicateKeyError(err error) bool {
+	var mysqlErr *mysqldriver.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry
+}
+
+func isAccessDeniedError(err error) bool {
+	var mysqlErr *mysqldriver.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrSpecificAccessDenied
+}
+
 type Stater interface {
 	MasterStats() *sql.DBStats
 	SlaveStats() []*sql.DBStats
 }
+
+// ReplicaStater is optionally implemented by a Stater to report the health and the selection of the replicas
+type ReplicaStater interface {
+	MasterSelected() int64
+	ReplicaStatus() []ReplicaStatus
+}
+
 type dbStatsCollector struct {
 	ds   Stater
 	desc *prometheus.Desc
@@ -137,6 +181,27 @@ func (c *dbStatsCollector) Collect(ch chan<- prometheus.Metric) {
 	for i, stats := range c.ds.SlaveStats() {
 		dbStatsCollect(ch, c.desc, *stats, fmt.Sprintf("slave_%d", i))
 	}
+
+	rs, ok := c.ds.(ReplicaStater)
+	if !ok {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(rs.MasterSelected()), "master", "Selected")
+	for i, status := range rs.ReplicaStatus() {
+		replica := fmt.Sprintf("slave_%d", i)
+		healthy := 0.0
+		if status.Healthy {
+			healthy = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, healthy, replica, "Healthy")
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, status.Lag.Seconds(), replica, "LagSeconds")
+		lagUnknown := 0.0
+		if status.LagUnknown {
+			lagUnknown = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, lagUnknown, replica, "LagUnknown")
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(status.Selected), replica, "Selected")
+	}
 }
 
 func dbStatsCollect(ch chan<- prometheus.Metric, desc *prometheus.Desc, stats sql.DBStats, replica string) {
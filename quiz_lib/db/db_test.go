@@ -0,0 +1,22 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMySQLErrors(t *testing.T) {
+	duplicate := &mysqldriver.MySQLError{Number: mysqlErrDuplicateEntry, Message: "Duplicate entry"}
+	denied := &mysqldriver.MySQLError{Number: mysqlErrSpecificAccessDenied, Message: "Access denied"}
+
+	assert.True(t, IsDuplicateKeyError(fmt.Errorf("create: %w", duplicate)))
+	assert.False(t, IsDuplicateKeyError(denied))
+	assert.True(t, isAccessDeniedError(fmt.Errorf("check: %w", denied)))
+	assert.False(t, isAccessDeniedError(duplicate))
+	assert.False(t, isAccessDeniedError(errors.New("connection refused")))
+	assert.False(t, isAccessDeniedError(nil))
+}
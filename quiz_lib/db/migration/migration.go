@@ -0,0 +1,145 @@
+// Package migration versions the quiz database schema with goose. The SQL migrations are embedded
+// from quiz_lib/db/schema, the Go migrations are added with Register.
+package migration
+
+import (
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/luulethe/quiz/quiz_lib/db/schema"
+	"github.com/pressly/goose"
+)
+
+const (
+	CommandUp     = "up"
+	CommandDown   = "down"
+	CommandStatus = "status"
+	CommandRedo   = "redo"
+	// CommandBaseline marks the migrations up to a version as applied without running them,
+	// for a database whose schema was applied by hand
+	CommandBaseline = "baseline"
+)
+
+func init() {
+	if err := goose.SetDialect("mysql"); err != nil {
+		panic(err)
+	}
+}
+
+// Register adds a Go migration, the version orders it with the SQL migrations
+func Register(version int64, name string, up func(*sql.Tx) error, down func(*sql.Tx) error) {
+	goose.AddNamedMigration(fmt.Sprintf("%d_%s.go", version, name), up, down)
+}
+
+// Run runs a migration command on the database
+func Run(db *sql.DB, command string, args ...string) error {
+	dir, err := extractSchema()
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	switch command {
+	case CommandUp:
+		return goose.Up(db, dir)
+	case CommandDown:
+		return goose.Down(db, dir)
+	case CommandStatus:
+		return goose.Status(db, dir)
+	case CommandRedo:
+		return goose.Redo(db, dir)
+	case CommandBaseline:
+		if len(args) == 0 {
+			return fmt.Errorf("baseline must be of form: migrate baseline VERSION")
+		}
+		version, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("version must be a number (got '%s')", args[0])
+		}
+		return baseline(db, dir, version)
+	}
+	return fmt.Errorf("unknown migration command %q, expected up, down, status, redo or baseline", command)
+}
+
+// Versions returns the version of the database and the latest version of the migrations
+func Versions(db *sql.DB) (int64, int64, error) {
+	dir, err := extractSchema()
+	if err != nil {
+		return 0, 0, err
+	}
+	defer os.RemoveAll(dir)
+
+	current, err := goose.GetDBVersion(db)
+	if err != nil {
+		return 0, 0, err
+	}
+	migrations, err := goose.CollectMigrations(dir, 0, math.MaxInt64)
+	if err != nil {
+		return 0, 0, err
+	}
+	latest, err := migrations.Last()
+	if err != nil {
+		return current, 0, nil
+	}
+	return current, latest.Version, nil
+}
+
+// CheckLatest fails when the database is behind the migrations
+func CheckLatest(db *sql.DB) error {
+	current, latest, err := Versions(db)
+	if err != nil {
+		return err
+	}
+	if current < latest {
+		return fmt.Errorf("database schema version %d is behind %d, run the migrate up command", current, latest)
+	}
+	return nil
+}
+
+func baseline(db *sql.DB, dir string, version int64) error {
+	current, err := goose.EnsureDBVersion(db)
+	if err != nil || version <= current {
+		return err
+	}
+	migrations, err := goose.CollectMigrations(dir, current, version)
+	if err != nil {
+		return err
+	}
+	for _, migration := range migrations {
+		if _, err := db.Exec(
+			fmt.Sprintf("INSERT INTO %s (version_id, is_applied) VALUES (?, true)", goose.TableName()), migration.Version,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// extractSchema writes the embedded SQL migrations to a temporary directory, where goose reads them from
+func extractSchema() (string, error) {
+	dir, err := ioutil.TempDir("", "quiz_schema")
+	if err != nil {
+		return "", err
+	}
+	err = fs.WalkDir(schema.FS, ".", func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || filepath.Ext(path) != ".sql" {
+			return err
+		}
+		data, err := schema.FS.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(filepath.Join(dir, filepath.Base(path)), data, 0600)
+	})
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	return dir, nil
+}
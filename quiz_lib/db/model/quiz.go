@@ -1,15 +1,29 @@
 package model
 
+// Quiz statuses. Before the lifecycle a quiz was open (1) or finished (2), the lifecycle migration moved
+// the open quizzes to in progress since they could be answered, 1 is a lobby from then on.
 const (
-	QuizStatusOpen     = 1
-	QuizStatusFinished = 2
+	QuizStatusLobby      = 1 // open for joining, waiting for start_time
+	QuizStatusFinished   = 2
+	QuizStatusDraft      = 3
+	QuizStatusScheduled  = 4
+	QuizStatusInProgress = 5
+	QuizStatusPaused     = 6
+	QuizStatusArchived   = 7
 )
 
+// QuizTab times are in milliseconds, a quiz runs from start_time to end_time, which is pushed back on pause.
+// paused_duration is the total time the quiz has been paused, paused_time is the start of the current pause.
 type QuizTab struct {
-	ID          int64 `gorm:"primarykey"`
-	Status      int32
-	Name        string
-	CreatedTime int64
+	ID             int64 `gorm:"primarykey"`
+	Status         int32
+	Name           string
+	StartTime      int64
+	EndTime        int64
+	PausedTime     int64
+	PausedDuration int64
+	CreatedTime    int64
+	UpdatedTime    int64
 }
 
 type QuizParticipantTab struct {
@@ -20,3 +34,48 @@ type QuizParticipantTab struct {
 	CreatedTime int64
 	UpdatedTime int64
 }
+
+type QuizQuestionTab struct {
+	ID          int64 `gorm:"primarykey"`
+	QuizID      int64
+	Content     string
+	Score       int32
+	Seq         int32
+	Duration    int32 // seconds to answer the question, 0 means until the end of the quiz
+	CreatedTime int64
+	UpdatedTime int64
+}
+
+type QuizAnswerOptionTab struct {
+	ID          int64 `gorm:"primarykey"`
+	QuestionID  int64
+	Content     string
+	IsCorrect   bool
+	CreatedTime int64
+}
+
+type QuizUserAnswerTab struct {
+	ID          int64 `gorm:"primarykey"`
+	QuizID      int64
+	QuestionID  int64
+	UserID      int64
+	AnswerID    int64
+	IsCorrect   bool
+	Score       int32
+	CreatedTime int64
+}
+
+const (
+	OutboxStatusPending = 1
+	OutboxStatusSent    = 2
+)
+
+type QuizOutboxTab struct {
+	ID          int64 `gorm:"primarykey"`
+	Topic       string
+	MsgKey      string
+	Payload     []byte
+	Status      int32
+	CreatedTime int64
+	SentTime    int64
+}
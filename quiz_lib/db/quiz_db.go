@@ -1,38 +1,126 @@
 package db
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"math/rand"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/luulethe/quiz/go_common/auth"
+	"github.com/luulethe/quiz/go_common/log"
 	"gorm.io/gorm"
 )
 
+const (
+	DefaultMaxReplicaLag       = 5 * time.Second
+	DefaultHealthCheckInterval = 5 * time.Second
+	DefaultStickyMasterWindow  = 2 * time.Second
+
+	replicaCheckTimeout = 3 * time.Second
+)
+
+var errReplicationStopped = errors.New("replication stopped")
+
 type QuizDB interface {
 	Master() *gorm.DB
+	// Slave returns a random healthy replica, the master when none is healthy
 	Slave() *gorm.DB
+	// Reader returns the master within the sticky window of the user of ctx, Slave otherwise,
+	// so that a user reads its own writes whatever the replication lag
+	Reader(ctx context.Context) *gorm.DB
+	// MarkWrite starts the sticky master window of the user of ctx
+	MarkWrite(ctx context.Context)
 	Close() error
 }
 
+// StickyStore remembers the users which wrote within the sticky master window. The default one is per server,
+// a store shared by the servers lets a user read its writes whichever server it reads through.
+type StickyStore interface {
+	MarkWrite(ctx context.Context, userID int64)
+	IsSticky(ctx context.Context, userID int64) bool
+}
+
+// ReplicaStatus is the last health check of a replica
+type ReplicaStatus struct {
+	Healthy    bool
+	Lag        time.Duration
+	LagUnknown bool  // the replication status can't be read, the replica is kept
+	Selected   int64 // number of reads sent to the replica
+}
+
+type replica struct {
+	db         *gorm.DB
+	address    string
+	healthy    int32
+	lag        int64
+	lagUnknown int32
+	selected   int64
+}
+
+func (r *replica) isHealthy() bool {
+	return atomic.LoadInt32(&r.healthy) == 1
+}
+
 type quizDB struct {
-	master   *gorm.DB
-	replicas []*gorm.DB
+	master         *gorm.DB
+	replicas       []*replica
+	maxLag         time.Duration
+	sticky         StickyStore
+	masterSelected int64
+
+	stop chan struct{}
+	wg   sync.WaitGroup
 }
 
-func NewQuizDBFromConfig(config Config) (QuizDB, error) {
+// NewQuizDBFromConfig connects to the database of the config and its replicas, the sticky master windows
+// are kept in sticky, or per server when it's nil
+func NewQuizDBFromConfig(config Config, sticky StickyStore) (QuizDB, error) {
 	master, err := dbConnect(config)
 	if err != nil {
 		return nil, err
 	}
-	replicas, err := dbConnectToReplica(config)
+	replicaConns, err := dbConnectToReplica(config)
 	if err != nil {
+		closeConn(master)
 		return nil, err
 	}
 
-	return &quizDB{master: master, replicas: replicas}, nil
+	if sticky == nil {
+		sticky = newStickyMaster(config.StickyMasterWindow)
+	}
+	quizDB := &quizDB{
+		master: master,
+		maxLag: config.MaxReplicaLag,
+		sticky: sticky,
+		stop:   make(chan struct{}),
+	}
+	if quizDB.maxLag <= 0 {
+		quizDB.maxLag = DefaultMaxReplicaLag
+	}
+	for i, conn := range replicaConns {
+		// replicas are trusted until their first health check
+		quizDB.replicas = append(quizDB.replicas, &replica{db: conn, address: config.Replica[i], healthy: 1})
+	}
+
+	interval := config.HealthCheckInterval
+	if interval <= 0 {
+		interval = DefaultHealthCheckInterval
+	}
+	if len(quizDB.replicas) > 0 {
+		quizDB.wg.Add(1)
+		go quizDB.runHealthCheck(interval)
+	}
+	return quizDB, nil
 }
 
 // NewNoteDBForTest creates a QuizDB object for testing
 func NewNoteDBForTest(dbConn *gorm.DB) QuizDB {
-	return &quizDB{dbConn, nil}
+	return &quizDB{master: dbConn, sticky: newStickyMaster(0), stop: make(chan struct{})}
 }
 
 func (om *quizDB) Master() *gorm.DB {
@@ -40,13 +128,164 @@ func (om *quizDB) Master() *gorm.DB {
 }
 
 func (om *quizDB) Slave() *gorm.DB {
-	if len(om.replicas) > 0 {
-		return om.replicas[rand.Intn(len(om.replicas))] //nolint
+	healthy := make([]*replica, 0, len(om.replicas))
+	for _, r := range om.replicas {
+		if r.isHealthy() {
+			healthy = append(healthy, r)
+		}
 	}
-	return om.master
+	if len(healthy) == 0 {
+		atomic.AddInt64(&om.masterSelected, 1)
+		return om.master
+	}
+	r := healthy[rand.Intn(len(healthy))] //nolint
+	atomic.AddInt64(&r.selected, 1)
+	return r.db
+}
+
+func (om *quizDB) Reader(ctx context.Context) *gorm.DB {
+	if userID, ok := auth.UserIDFromContext(ctx); ok && om.sticky.IsSticky(ctx, userID) {
+		atomic.AddInt64(&om.masterSelected, 1)
+		return om.master
+	}
+	return om.Slave()
+}
+
+func (om *quizDB) MarkWrite(ctx context.Context) {
+	if userID, ok := auth.UserIDFromContext(ctx); ok {
+		om.sticky.MarkWrite(ctx, userID)
+	}
+}
+
+func (om *quizDB) runHealthCheck(interval time.Duration) {
+	defer om.wg.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		om.checkReplicas()
+		select {
+		case <-om.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkReplicas ejects the replicas which are down or lag more than maxLag, and brings back the healthy ones.
+// A replica whose replication status is denied to the user is kept, ejecting it would eject every replica.
+func (om *quizDB) checkReplicas() {
+	for _, r := range om.replicas {
+		lag, err := replicationLag(r.db)
+		lagUnknown := isAccessDeniedError(err)
+		if lagUnknown {
+			log.Errorf(log.DefaultContext, "quizDB.checkReplicas|replica:%s|lag unknown, "+
+				"the user needs the REPLICATION CLIENT privilege|err:%v", r.address, err)
+			err = nil
+		}
+		healthy := err == nil && lag <= om.maxLag
+		atomic.StoreInt64(&r.lag, int64(lag))
+		atomic.StoreInt32(&r.lagUnknown, boolToInt32(lagUnknown))
+
+		status := boolToInt32(healthy)
+		if atomic.SwapInt32(&r.healthy, status) != status {
+			log.Infof(log.DefaultContext, "quizDB.checkReplicas|replica:%s|healthy:%v|lag:%v|err:%v", r.address, healthy, lag, err)
+		}
+	}
+}
+
+func boolToInt32(value bool) int32 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// replicationLag reads Seconds_Behind_Master, a server which isn't replicating, e.g. the master itself
+// configured as replica in local setups, has no lag
+func replicationLag(conn *gorm.DB) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), replicaCheckTimeout)
+	defer cancel()
+	rows, err := conn.WithContext(ctx).Raw("SHOW SLAVE STATUS").Rows()
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return 0, rows.Err()
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	values := make([]sql.NullString, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return 0, err
+	}
+	for i, column := range columns {
+		if column != "Seconds_Behind_Master" {
+			continue
+		}
+		if !values[i].Valid {
+			return 0, errReplicationStopped
+		}
+		seconds, err := strconv.ParseInt(values[i].String, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(seconds) * time.Second, nil
+	}
+	return 0, fmt.Errorf("missing Seconds_Behind_Master")
+}
+
+func (om *quizDB) MasterStats() *sql.DBStats {
+	return dbStats(om.master)
+}
+
+func (om *quizDB) SlaveStats() []*sql.DBStats {
+	stats := make([]*sql.DBStats, 0, len(om.replicas))
+	for _, r := range om.replicas {
+		if s := dbStats(r.db); s != nil {
+			stats = append(stats, s)
+		}
+	}
+	return stats
+}
+
+func (om *quizDB) MasterSelected() int64 {
+	return atomic.LoadInt64(&om.masterSelected)
+}
+
+func (om *quizDB) ReplicaStatus() []ReplicaStatus {
+	status := make([]ReplicaStatus, 0, len(om.replicas))
+	for _, r := range om.replicas {
+		status = append(status, ReplicaStatus{
+			Healthy:    r.isHealthy(),
+			Lag:        time.Duration(atomic.LoadInt64(&r.lag)),
+			LagUnknown: atomic.LoadInt32(&r.lagUnknown) == 1,
+			Selected:   atomic.LoadInt64(&r.selected),
+		})
+	}
+	return status
+}
+
+func dbStats(conn *gorm.DB) *sql.DBStats {
+	sqlDB, err := conn.DB()
+	if err != nil {
+		return nil
+	}
+	stats := sqlDB.Stats()
+	return &stats
 }
 
 func (om *quizDB) Close() (err error) {
+	close(om.stop)
+	om.wg.Wait()
+
 	sql, err := om.master.DB()
 	if err != nil {
 		return
@@ -57,7 +296,7 @@ func (om *quizDB) Close() (err error) {
 	}
 
 	for _, replica := range om.replicas {
-		sql, err := replica.DB()
+		sql, err := replica.db.DB()
 		if err != nil {
 			return err
 		}
@@ -68,3 +307,41 @@ func (om *quizDB) Close() (err error) {
 	}
 	return
 }
+
+// stickyMaster remembers the users which wrote within the window, per server
+type stickyMaster struct {
+	window    time.Duration
+	mutex     sync.Mutex
+	writes    map[int64]time.Time
+	lastSweep time.Time
+}
+
+func newStickyMaster(window time.Duration) *stickyMaster {
+	if window <= 0 {
+		window = DefaultStickyMasterWindow
+	}
+	return &stickyMaster{window: window, writes: map[int64]time.Time{}, lastSweep: time.Now()}
+}
+
+func (s *stickyMaster) MarkWrite(ctx context.Context, userID int64) {
+	now := time.Now()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.writes[userID] = now
+	if now.Sub(s.lastSweep) < s.window {
+		return
+	}
+	s.lastSweep = now
+	for user, written := range s.writes {
+		if now.Sub(written) >= s.window {
+			delete(s.writes, user)
+		}
+	}
+}
+
+func (s *stickyMaster) IsSticky(ctx context.Context, userID int64) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	written, ok := s.writes[userID]
+	return ok && time.Since(written) < s.window
+}
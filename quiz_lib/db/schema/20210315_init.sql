@@ -1,3 +1,4 @@
+-- +goose Up
 CREATE TABLE `quiz_tab` (
   `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
   `status` tinyint(1) UNSIGNED NOT NULL,
@@ -16,4 +17,8 @@ CREATE TABLE `quiz_participant_tab` (
     PRIMARY KEY (`id`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
-CREATE UNIQUE INDEX quiz_participant_tab_quiz_user_index ON quiz_participant_tab (quiz_id, user_id);
\ No newline at end of file
+CREATE UNIQUE INDEX quiz_participant_tab_quiz_user_index ON quiz_participant_tab (quiz_id, user_id);
+
+-- +goose Down
+DROP TABLE `quiz_participant_tab`;
+DROP TABLE `quiz_tab`;
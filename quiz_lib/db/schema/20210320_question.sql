@@ -0,0 +1,43 @@
+-- +goose Up
+CREATE TABLE `quiz_question_tab` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `quiz_id` bigint(20) unsigned NOT NULL,
+  `content` varchar(4096) NOT NULL,
+  `score` int(11) NOT NULL,
+  `seq` int(11) NOT NULL,
+  `created_time` bigint(20) UNSIGNED NOT NULL,
+  `updated_time` bigint(20) UNSIGNED NOT NULL,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE INDEX quiz_question_tab_quiz_index ON quiz_question_tab (quiz_id, seq);
+
+CREATE TABLE `quiz_answer_option_tab` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `question_id` bigint(20) unsigned NOT NULL,
+  `content` varchar(1024) NOT NULL,
+  `is_correct` tinyint(1) NOT NULL,
+  `created_time` bigint(20) UNSIGNED NOT NULL,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE INDEX quiz_answer_option_tab_question_index ON quiz_answer_option_tab (question_id);
+
+CREATE TABLE `quiz_user_answer_tab` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `quiz_id` bigint(20) unsigned NOT NULL,
+  `question_id` bigint(20) unsigned NOT NULL,
+  `user_id` bigint(20) unsigned NOT NULL,
+  `answer_id` bigint(20) unsigned NOT NULL,
+  `is_correct` tinyint(1) NOT NULL,
+  `score` int(11) NOT NULL,
+  `created_time` bigint(20) UNSIGNED NOT NULL,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE UNIQUE INDEX quiz_user_answer_tab_quiz_question_user_index ON quiz_user_answer_tab (quiz_id, question_id, user_id);
+
+-- +goose Down
+DROP TABLE `quiz_user_answer_tab`;
+DROP TABLE `quiz_answer_option_tab`;
+DROP TABLE `quiz_question_tab`;
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE `quiz_outbox_tab` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `topic` varchar(255) NOT NULL,
+  `msg_key` varchar(255) NOT NULL,
+  `payload` mediumblob NOT NULL,
+  `status` tinyint(1) UNSIGNED NOT NULL,
+  `created_time` bigint(20) UNSIGNED NOT NULL,
+  `sent_time` bigint(20) UNSIGNED NOT NULL DEFAULT 0,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE INDEX quiz_outbox_tab_status_index ON quiz_outbox_tab (status, id);
+
+-- +goose Down
+DROP TABLE `quiz_outbox_tab`;
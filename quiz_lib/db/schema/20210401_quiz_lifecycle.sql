@@ -0,0 +1,32 @@
+-- +goose Up
+ALTER TABLE `quiz_tab`
+  ADD COLUMN `start_time` bigint(20) UNSIGNED NOT NULL DEFAULT 0,
+  ADD COLUMN `end_time` bigint(20) UNSIGNED NOT NULL DEFAULT 0,
+  ADD COLUMN `paused_time` bigint(20) UNSIGNED NOT NULL DEFAULT 0,
+  ADD COLUMN `paused_duration` bigint(20) UNSIGNED NOT NULL DEFAULT 0,
+  ADD COLUMN `updated_time` bigint(20) UNSIGNED NOT NULL DEFAULT 0;
+
+CREATE INDEX quiz_tab_status_start_time_index ON quiz_tab (status, start_time);
+CREATE INDEX quiz_tab_status_end_time_index ON quiz_tab (status, end_time);
+
+-- quizzes opened before the lifecycle could be joined and answered, which is in progress now
+UPDATE quiz_tab SET status = 5 WHERE status = 1;
+
+ALTER TABLE `quiz_question_tab`
+  ADD COLUMN `duration` int(11) NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE `quiz_question_tab`
+  DROP COLUMN `duration`;
+
+UPDATE quiz_tab SET status = 1 WHERE status = 5;
+
+DROP INDEX quiz_tab_status_end_time_index ON quiz_tab;
+DROP INDEX quiz_tab_status_start_time_index ON quiz_tab;
+
+ALTER TABLE `quiz_tab`
+  DROP COLUMN `start_time`,
+  DROP COLUMN `end_time`,
+  DROP COLUMN `paused_time`,
+  DROP COLUMN `paused_duration`,
+  DROP COLUMN `updated_time`;
@@ -0,0 +1,7 @@
+// Package schema embeds the goose SQL migrations, named <version>_<name>.sql
+package schema
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,62 @@
+package db
+
+import (
+	"fmt"
+)
+
+// ShardedQuizDB keeps the quizzes and their questions in the main database, the participants and the answers
+// of a quiz with the outbox of their events are in the database of its shard, so that they are written in one
+// local transaction. The shard of a quiz is quiz_id modulo the number of shards.
+type ShardedQuizDB interface {
+	QuizDB // the main database
+	// Shard returns the database holding the participants and the answers of the quiz
+	Shard(quizID int64) QuizDB
+	// Databases returns every database once, the main database first
+	Databases() []QuizDB
+}
+
+type shardedQuizDB struct {
+	QuizDB
+	databases []QuizDB
+	shards    []QuizDB
+}
+
+// NewShardedQuizDB routes the shards to the databases, shards[i] is the index in databases of the shard i.
+// databases[0] is the main database, it holds every shard when shards is empty.
+func NewShardedQuizDB(databases []QuizDB, shards []int) (ShardedQuizDB, error) {
+	if len(databases) == 0 {
+		return nil, fmt.Errorf("missing database")
+	}
+	if len(shards) == 0 {
+		shards = []int{0}
+	}
+	sharded := &shardedQuizDB{QuizDB: databases[0], databases: databases}
+	for shard, database := range shards {
+		if database < 0 || database >= len(databases) {
+			return nil, fmt.Errorf("shard %d is on database %d, only %d databases are configured", shard, database, len(databases))
+		}
+		sharded.shards = append(sharded.shards, databases[database])
+	}
+	return sharded, nil
+}
+
+func (s *shardedQuizDB) Shard(quizID int64) QuizDB {
+	shard := quizID % int64(len(s.shards))
+	if shard < 0 {
+		shard = -shard
+	}
+	return s.shards[shard]
+}
+
+func (s *shardedQuizDB) Databases() []QuizDB {
+	return s.databases
+}
+
+func (s *shardedQuizDB) Close() (err error) {
+	for _, database := range s.databases {
+		if closeErr := database.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return
+}
@@ -0,0 +1,51 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDatabases(count int) []QuizDB {
+	databases := make([]QuizDB, 0, count)
+	for i := 0; i < count; i++ {
+		databases = append(databases, NewNoteDBForTest(nil))
+	}
+	return databases
+}
+
+func TestShardedQuizDB_Shard(t *testing.T) {
+	databases := newTestDatabases(3)
+	shards := []int{0, 1, 2, 1}
+	sharded, err := NewShardedQuizDB(databases, shards)
+	require.NoError(t, err)
+
+	// the shard of a quiz is quiz_id modulo the number of shards, two shards may share a database
+	for quizID := int64(0); quizID < 12; quizID++ {
+		assert.Same(t, databases[shards[quizID%4]], sharded.Shard(quizID), "quiz_id: %d", quizID)
+	}
+	assert.Same(t, databases[1], sharded.Shard(-3))
+	assert.Equal(t, databases, sharded.Databases())
+}
+
+func TestShardedQuizDB_SingleDatabase(t *testing.T) {
+	databases := newTestDatabases(2)
+	sharded, err := NewShardedQuizDB(databases, nil)
+	require.NoError(t, err)
+
+	// without shards the main database holds every quiz, the other databases are still listed
+	for quizID := int64(1); quizID < 5; quizID++ {
+		assert.Same(t, databases[0], sharded.Shard(quizID))
+	}
+	assert.Equal(t, databases, sharded.Databases())
+}
+
+func TestShardedQuizDB_InvalidShards(t *testing.T) {
+	_, err := NewShardedQuizDB(nil, nil)
+	assert.Error(t, err)
+	_, err = NewShardedQuizDB(newTestDatabases(2), []int{0, 2})
+	assert.Error(t, err)
+	_, err = NewShardedQuizDB(newTestDatabases(2), []int{-1})
+	assert.Error(t, err)
+}
@@ -0,0 +1,222 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/luulethe/quiz/config"
+	"github.com/luulethe/quiz/go_common/cache"
+	"github.com/luulethe/quiz/go_common/cache/cache_wrapper"
+	"github.com/luulethe/quiz/go_common/log"
+	"github.com/luulethe/quiz/go_common/metrics"
+	pb "github.com/luulethe/quiz/quiz_lib/pb/gen"
+	"github.com/luulethe/quiz/quiz_lib/quiz_error"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	AccountProtocolGRPC = "grpc"
+	AccountProtocolHTTP = "http"
+
+	accountCacheType          = "quiz_account"
+	accountCacheKeyFormat     = "quiz_account_user_%d"
+	defaultAccountTimeout     = 500 * time.Millisecond
+	defaultAccountPositiveTTL = 10 * time.Minute
+	defaultAccountNegativeTTL = 30 * time.Second
+)
+
+// ErrAccountUnavailable rejects the requests while the circuit breaker of the account service is open,
+// the failures opening it were already reported
+var ErrAccountUnavailable = quiz_error.Newf(pb.Error_ERROR_SERVICE_UNAVAILABLE, "account service unavailable")
+
+// AccountClient looks up the users of the account service
+type AccountClient interface {
+	UserExisted(ctx context.Context, userID int64) (error, bool)
+}
+
+// NewAccountClient connects to the account service of the config, with the lookups cached in cache
+func NewAccountClient(conf *config.AccountConfig, cache cache.SimpleCache, stats *metrics.StatsCollector) (AccountClient, error) {
+	timeout := conf.Timeout
+	if timeout <= 0 {
+		timeout = defaultAccountTimeout
+	}
+
+	var client AccountClient
+	switch conf.Protocol {
+	case AccountProtocolGRPC, "":
+		conn, err := grpc.Dial(conf.Address, grpc.WithInsecure())
+		if err != nil {
+			return nil, err
+		}
+		client = &GRPCAccountClient{conn: conn, client: pb.NewAccountServiceClient(conn), timeout: timeout}
+	case AccountProtocolHTTP:
+		client = &HTTPAccountClient{baseURL: strings.TrimRight(conf.Address, "/"), client: &http.Client{Timeout: timeout}}
+	default:
+		return nil, fmt.Errorf("unknown account protocol %q", conf.Protocol)
+	}
+	return NewCachedAccountClient(client, cache, conf, stats), nil
+}
+
+// GRPCAccountClient calls AccountService.GetUser, NOT_FOUND means the user doesn't exist
+type GRPCAccountClient struct {
+	conn    *grpc.ClientConn
+	client  pb.AccountServiceClient
+	timeout time.Duration
+}
+
+func (c *GRPCAccountClient) UserExisted(ctx context.Context, userID int64) (error, bool) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	_, err := c.client.GetUser(ctx, &pb.GetUserRequest{UserId: userID})
+	if status.Code(err) == codes.NotFound {
+		return nil, false
+	}
+	if err != nil {
+		return err, false
+	}
+	return nil, true
+}
+
+func (c *GRPCAccountClient) Close() error {
+	return c.conn.Close()
+}
+
+// HTTPAccountClient calls GET <address>/users/<user_id>, 404 means the user doesn't exist
+type HTTPAccountClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+func (c *HTTPAccountClient) UserExisted(ctx context.Context, userID int64) (error, bool) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/users/%d", c.baseURL, userID), nil)
+	if err != nil {
+		return err, false
+	}
+	response, err := c.client.Do(request)
+	if err != nil {
+		return err, false
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		return nil, true
+	case http.StatusNotFound:
+		return nil, false
+	}
+	return fmt.Errorf("account service status %d", response.StatusCode), false
+}
+
+type accountUser struct {
+	Existed bool `json:"existed"`
+}
+
+// CachedAccountClient caches the lookups of client per user, missing users for a shorter ttl,
+// and stops calling client while it keeps failing.
+type CachedAccountClient struct {
+	client      AccountClient
+	breaker     *circuitBreaker
+	cacheConfig *cache_wrapper.WrapperConfig
+	failOpen    bool
+	stats       *metrics.StatsCollector
+	positiveTTL int64 // time.Duration, changed by ApplyConfig
+	negativeTTL int64
+}
+
+func NewCachedAccountClient(
+	client AccountClient, cache cache.SimpleCache, conf *config.AccountConfig, stats *metrics.StatsCollector,
+) *CachedAccountClient {
+	cache_wrapper.RegisterCacheType(accountCacheType, cache)
+
+	c := &CachedAccountClient{
+		client:   client,
+		breaker:  newCircuitBreaker(conf.Breaker.FailureThreshold, conf.Breaker.OpenDuration),
+		failOpen: conf.Breaker.FailOpen,
+		stats:    stats,
+	}
+	c.setTTLs(conf)
+	c.cacheConfig = &cache_wrapper.WrapperConfig{
+		KeyFormat: accountCacheKeyFormat,
+		Expire:    defaultAccountPositiveTTL,
+		DataType:  &accountUser{},
+		CacheType: accountCacheType,
+		ResultExpire: func(result interface{}) time.Duration {
+			if user, ok := result.(*accountUser); ok && !user.Existed {
+				return time.Duration(atomic.LoadInt64(&c.negativeTTL))
+			}
+			return time.Duration(atomic.LoadInt64(&c.positiveTTL))
+		},
+	}
+	return c
+}
+
+// ApplyConfig takes the cache ttls of a reloaded config, the entries already cached keep their ttl
+func (c *CachedAccountClient) ApplyConfig(ctx context.Context, conf *config.Configuration) error {
+	if conf.Account != nil {
+		c.setTTLs(conf.Account)
+	}
+	return nil
+}
+
+func (c *CachedAccountClient) setTTLs(conf *config.AccountConfig) {
+	positiveTTL, negativeTTL := conf.PositiveTTL, conf.NegativeTTL
+	if positiveTTL <= 0 {
+		positiveTTL = defaultAccountPositiveTTL
+	}
+	if negativeTTL <= 0 {
+		negativeTTL = defaultAccountNegativeTTL
+	}
+	atomic.StoreInt64(&c.positiveTTL, int64(positiveTTL))
+	atomic.StoreInt64(&c.negativeTTL, int64(negativeTTL))
+}
+
+// UserExisted returns true without error when the account service fails and the breaker fails open
+func (c *CachedAccountClient) UserExisted(ctx context.Context, userID int64) (error, bool) {
+	result, err := cache_wrapper.WithCache(c.cacheConfig, userID, func(input interface{}) (interface{}, error) {
+		trial, ok := c.breaker.allow()
+		if !ok {
+			return nil, ErrAccountUnavailable
+		}
+		err, existed := c.client.UserExisted(ctx, input.(int64))
+		c.breaker.done(trial, err == nil)
+		if err != nil {
+			return nil, err
+		}
+		return &accountUser{Existed: existed}, nil
+	})
+	if errors.Is(err, ErrAccountUnavailable) {
+		log.Warnff(ctx, "AccountClient.UserExisted|user_id:%v|fail_open:%v|err:%v", userID, c.failOpen, err)
+	} else if err != nil {
+		log.Errorff(ctx, "AccountClient.UserExisted|user_id:%v|fail_open:%v|err:%v", userID, c.failOpen, err)
+	}
+	if err != nil {
+		c.reportCount(metrics.ResultError)
+		if c.failOpen {
+			return nil, true
+		}
+		return err, false
+	}
+	c.reportCount(metrics.ResultSuccess)
+	return nil, result.(*accountUser).Existed
+}
+
+func (c *CachedAccountClient) Close() error {
+	if closer, ok := c.client.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (c *CachedAccountClient) reportCount(result metrics.ResultType) {
+	if c.stats != nil {
+		c.stats.ReportCount(1, "AccountUserExisted", string(result))
+	}
+}
@@ -0,0 +1,57 @@
+package manager_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/luulethe/quiz/config"
+	"github.com/luulethe/quiz/go_common/cache"
+	"github.com/luulethe/quiz/quiz_lib/manager"
+	"github.com/luulethe/quiz/quiz_lib/manager/managertest"
+	pb "github.com/luulethe/quiz/quiz_lib/pb/gen"
+	"github.com/luulethe/quiz/quiz_lib/quiz_error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachedAccountClient_Breaker(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	redisCache, err := cache.NewRedisClient(server.Addr(), &cache.RedisOption{})
+	require.NoError(t, err)
+	t.Cleanup(func() { redisCache.Close() })
+
+	accounts := managertest.NewAccountClient(1)
+	client := manager.NewCachedAccountClient(accounts, redisCache, &config.AccountConfig{
+		Breaker: config.CircuitBreakerConfig{FailureThreshold: 2, OpenDuration: 20 * time.Millisecond},
+	}, nil)
+
+	err, existed := client.UserExisted(ctx, 1)
+	require.NoError(t, err)
+	assert.True(t, existed)
+
+	accounts.SetErr(errors.New("connection refused"))
+	for userID := int64(2); userID <= 3; userID++ {
+		err, _ = client.UserExisted(ctx, userID)
+		assert.Equal(t, pb.Error_ERROR_INTERNAL, quiz_error.Result(err))
+	}
+
+	// open, the service isn't called and the request is rejected, not reported as a fault
+	err, _ = client.UserExisted(ctx, 4)
+	assert.True(t, errors.Is(err, manager.ErrAccountUnavailable))
+	assert.Equal(t, pb.Error_ERROR_SERVICE_UNAVAILABLE, quiz_error.Result(err))
+	assert.Equal(t, 3, accounts.Calls())
+
+	// the trial call closes it
+	accounts.SetErr(nil)
+	time.Sleep(30 * time.Millisecond)
+	err, existed = client.UserExisted(ctx, 5)
+	require.NoError(t, err)
+	assert.False(t, existed)
+	err, _ = client.UserExisted(ctx, 6)
+	require.NoError(t, err)
+	assert.Equal(t, 5, accounts.Calls())
+}
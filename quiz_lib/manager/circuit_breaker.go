@@ -0,0 +1,63 @@
+package manager
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	defaultBreakerFailureThreshold = 5
+	defaultBreakerOpenDuration     = 10 * time.Second
+)
+
+// circuitBreaker opens after threshold consecutive failures. Once openDuration has passed,
+// a single trial call is let through, it closes the breaker on success and opens it again on failure.
+type circuitBreaker struct {
+	mutex        sync.Mutex
+	threshold    int
+	openDuration time.Duration
+	failures     int
+	openUntil    time.Time
+	trial        bool
+}
+
+func newCircuitBreaker(threshold int, openDuration time.Duration) *circuitBreaker {
+	if threshold <= 0 {
+		threshold = defaultBreakerFailureThreshold
+	}
+	if openDuration <= 0 {
+		openDuration = defaultBreakerOpenDuration
+	}
+	return &circuitBreaker{threshold: threshold, openDuration: openDuration}
+}
+
+// allow returns ok false while the breaker is open. A caller allowed must report its call with done
+// and the trial returned, so that only the end of the trial call lets another trial through.
+func (b *circuitBreaker) allow() (trial bool, ok bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.failures < b.threshold {
+		return false, true
+	}
+	if b.trial || time.Now().Before(b.openUntil) {
+		return false, false
+	}
+	b.trial = true
+	return true, true
+}
+
+func (b *circuitBreaker) done(trial bool, success bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if trial {
+		b.trial = false
+	}
+	if success {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openUntil = time.Now().Add(b.openDuration)
+	}
+}
@@ -0,0 +1,35 @@
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker_Trial(t *testing.T) {
+	breaker := newCircuitBreaker(1, 10*time.Millisecond)
+	_, ok := breaker.allow()
+	assert.True(t, ok)
+	slowTrial, slowOK := breaker.allow()
+	assert.True(t, slowOK)
+	breaker.done(false, false)
+	_, ok = breaker.allow()
+	assert.False(t, ok)
+
+	time.Sleep(20 * time.Millisecond)
+	trial, ok := breaker.allow()
+	assert.True(t, ok)
+	assert.True(t, trial)
+
+	// a call started before the breaker opened ends during the trial, only the trial lets the next one through
+	assert.False(t, slowTrial)
+	breaker.done(slowTrial, false)
+	_, ok = breaker.allow()
+	assert.False(t, ok)
+
+	breaker.done(trial, true)
+	trial, ok = breaker.allow()
+	assert.True(t, ok)
+	assert.False(t, trial)
+}
@@ -0,0 +1,267 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/luulethe/quiz/config"
+	"github.com/luulethe/quiz/go_common/cache"
+	"github.com/luulethe/quiz/go_common/log"
+)
+
+const (
+	// wsNodesKey is a sorted set of gateway nodes scored by their last heartbeat in milliseconds
+	wsNodesKey                 = "quiz_ws_nodes"
+	wsNodeConnectionsKeyFormat = "quiz_ws_node_connections_%s" // client_id -> user_id:quiz_id
+	wsQuizNodesKeyFormat       = "quiz_ws_quiz_nodes_%d"       // node_id -> number of connections
+	wsUserConnectionsKeyFormat = "quiz_ws_user_connections_%d" // client_id -> quiz_id:node_id
+	wsConnectionExpire         = 24 * time.Hour
+
+	registerConnectionScript = `
+if redis.call('HSET', KEYS[1], ARGV[1], ARGV[2]) == 1 then
+	redis.call('HINCRBY', KEYS[2], ARGV[3], 1)
+end
+redis.call('HSET', KEYS[3], ARGV[1], ARGV[4])
+redis.call('EXPIRE', KEYS[2], ARGV[5])
+redis.call('EXPIRE', KEYS[3], ARGV[5])
+return ''`
+
+	// ARGV[3] == '1' only removes the connection of a node which has been reaped,
+	// so a node coming back alive in the meantime keeps its connections
+	unregisterConnectionScript = `
+if ARGV[3] == '1' and redis.call('ZSCORE', KEYS[4], ARGV[2]) then
+	return ''
+end
+if redis.call('HDEL', KEYS[1], ARGV[1]) == 1 then
+	if redis.call('HINCRBY', KEYS[2], ARGV[2], -1) <= 0 then
+		redis.call('HDEL', KEYS[2], ARGV[2])
+	end
+end
+redis.call('HDEL', KEYS[3], ARGV[1])
+return ''`
+
+	removeStaleNodeScript = `
+local heartbeat = redis.call('ZSCORE', KEYS[1], ARGV[1])
+if heartbeat and tonumber(heartbeat) <= tonumber(ARGV[2]) then
+	redis.call('ZREM', KEYS[1], ARGV[1])
+	return '1'
+end
+return ''`
+)
+
+// Connection is a websocket client of a gateway node which has joined a quiz
+type Connection struct {
+	ClientID string
+	UserID   int64
+	QuizID   int64
+	NodeID   string
+}
+
+// ConnectionRegistry maps users and quizzes to the gateway nodes holding their websocket connections.
+// A node is alive while it heartbeats within the node ttl, the connections of a dead node are removed
+// by ReapStaleNodes.
+type ConnectionRegistry interface {
+	// Register adds a connection, a client is registered to one quiz at a time
+	Register(ctx context.Context, connection *Connection) error
+	Unregister(ctx context.Context, connection *Connection) error
+	// Heartbeat marks the node alive, it returns true when the node was unknown,
+	// i.e. it just started or has been reaped and must register its connections again
+	Heartbeat(ctx context.Context, nodeID string) (error, bool)
+	GetQuizNodes(ctx context.Context, quizID int64) (error, []string)
+	GetUserConnections(ctx context.Context, userID int64) (error, []*Connection)
+	// ReapStaleNodes removes the nodes missing their heartbeat with all of their connections
+	ReapStaleNodes(ctx context.Context) (error, int)
+}
+
+func NewConnectionRegistry(cache cache.EnhancedCache, nodeTTL time.Duration) ConnectionRegistry {
+	if nodeTTL <= 0 {
+		nodeTTL = config.DefaultNodeTTL
+	}
+	return &ConnectionRegistryImpl{cache: cache, nodeTTL: nodeTTL}
+}
+
+type ConnectionRegistryImpl struct {
+	cache   cache.EnhancedCache
+	nodeTTL time.Duration
+}
+
+func wsNodeConnectionsKey(nodeID string) string {
+	return fmt.Sprintf(wsNodeConnectionsKeyFormat, nodeID)
+}
+
+func wsQuizNodesKey(quizID int64) string {
+	return fmt.Sprintf(wsQuizNodesKeyFormat, quizID)
+}
+
+func wsUserConnectionsKey(userID int64) string {
+	return fmt.Sprintf(wsUserConnectionsKeyFormat, userID)
+}
+
+func (r *ConnectionRegistryImpl) Register(ctx context.Context, connection *Connection) error {
+	_, err := r.cache.Eval(
+		registerConnectionScript,
+		[]string{
+			wsNodeConnectionsKey(connection.NodeID),
+			wsQuizNodesKey(connection.QuizID),
+			wsUserConnectionsKey(connection.UserID),
+		},
+		[]string{
+			connection.ClientID,
+			fmt.Sprintf("%d:%d", connection.UserID, connection.QuizID),
+			connection.NodeID,
+			fmt.Sprintf("%d:%s", connection.QuizID, connection.NodeID),
+			strconv.FormatInt(int64(wsConnectionExpire/time.Second), 10),
+		},
+	)
+	if err != nil {
+		log.Errorff(ctx, "ConnectionRegistry.Register|client_id:%v|err:%v", connection.ClientID, err)
+	}
+	return err
+}
+
+func (r *ConnectionRegistryImpl) Unregister(ctx context.Context, connection *Connection) error {
+	err := r.unregister(connection, false)
+	if err != nil {
+		log.Errorff(ctx, "ConnectionRegistry.Unregister|client_id:%v|err:%v", connection.ClientID, err)
+	}
+	return err
+}
+
+func (r *ConnectionRegistryImpl) unregister(connection *Connection, onlyReaped bool) error {
+	reaped := "0"
+	if onlyReaped {
+		reaped = "1"
+	}
+	_, err := r.cache.Eval(
+		unregisterConnectionScript,
+		[]string{
+			wsNodeConnectionsKey(connection.NodeID),
+			wsQuizNodesKey(connection.QuizID),
+			wsUserConnectionsKey(connection.UserID),
+			wsNodesKey,
+		},
+		[]string{connection.ClientID, connection.NodeID, reaped},
+	)
+	return err
+}
+
+func (r *ConnectionRegistryImpl) Heartbeat(ctx context.Context, nodeID string) (error, bool) {
+	added, err := r.cache.ZAdd(wsNodesKey, redis.Z{Score: float64(time.Now().UnixMilli()), Member: nodeID})
+	if err != nil {
+		log.Errorff(ctx, "ConnectionRegistry.Heartbeat|node_id:%v|err:%v", nodeID, err)
+		return err, false
+	}
+	return nil, added > 0
+}
+
+func (r *ConnectionRegistryImpl) GetQuizNodes(ctx context.Context, quizID int64) (error, []string) {
+	nodes, err := r.cache.HGetAll(wsQuizNodesKey(quizID))
+	if err != nil {
+		log.Errorff(ctx, "ConnectionRegistry.GetQuizNodes|quiz_id:%v|err:%v", quizID, err)
+		return err, nil
+	}
+
+	aliveNodes := make([]string, 0, len(nodes))
+	for nodeID := range nodes {
+		err, alive := r.isAlive(nodeID)
+		if err != nil {
+			log.Errorff(ctx, "ConnectionRegistry.GetQuizNodes|node_id:%v|err:%v", nodeID, err)
+			return err, nil
+		}
+		if alive {
+			aliveNodes = append(aliveNodes, nodeID)
+		}
+	}
+	sort.Strings(aliveNodes)
+	return nil, aliveNodes
+}
+
+func (r *ConnectionRegistryImpl) GetUserConnections(ctx context.Context, userID int64) (error, []*Connection) {
+	values, err := r.cache.HGetAll(wsUserConnectionsKey(userID))
+	if err != nil {
+		log.Errorff(ctx, "ConnectionRegistry.GetUserConnections|user_id:%v|err:%v", userID, err)
+		return err, nil
+	}
+
+	connections := make([]*Connection, 0, len(values))
+	for clientID, value := range values {
+		parts := strings.SplitN(value, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		quizID, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		err, alive := r.isAlive(parts[1])
+		if err != nil {
+			log.Errorff(ctx, "ConnectionRegistry.GetUserConnections|node_id:%v|err:%v", parts[1], err)
+			return err, nil
+		}
+		if alive {
+			connections = append(connections, &Connection{ClientID: clientID, UserID: userID, QuizID: quizID, NodeID: parts[1]})
+		}
+	}
+	return nil, connections
+}
+
+func (r *ConnectionRegistryImpl) ReapStaleNodes(ctx context.Context) (error, int) {
+	deadline := strconv.FormatInt(time.Now().UnixMilli()-r.nodeTTL.Milliseconds(), 10)
+	staleNodes, err := r.cache.Client().ZRangeByScore(wsNodesKey, redis.ZRangeBy{Min: "-inf", Max: deadline}).Result()
+	if err != nil {
+		log.Errorff(ctx, "ConnectionRegistry.ReapStaleNodes|err:%v", err)
+		return err, 0
+	}
+
+	reaped := 0
+	for _, nodeID := range staleNodes {
+		// only one of the concurrent reapers removes the node
+		removed, err := r.cache.Eval(removeStaleNodeScript, []string{wsNodesKey}, []string{nodeID, deadline})
+		if err != nil {
+			log.Errorff(ctx, "ConnectionRegistry.ReapStaleNodes|node_id:%v|err:%v", nodeID, err)
+			return err, reaped
+		}
+		if removed == "" {
+			continue
+		}
+		if err := r.removeNodeConnections(nodeID); err != nil {
+			log.Errorff(ctx, "ConnectionRegistry.ReapStaleNodes|node_id:%v|err:%v", nodeID, err)
+			return err, reaped
+		}
+		reaped++
+	}
+	return nil, reaped
+}
+
+func (r *ConnectionRegistryImpl) removeNodeConnections(nodeID string) error {
+	values, err := r.cache.HGetAll(wsNodeConnectionsKey(nodeID))
+	if err != nil {
+		return err
+	}
+	for clientID, value := range values {
+		connection := &Connection{ClientID: clientID, NodeID: nodeID}
+		if _, err := fmt.Sscanf(value, "%d:%d", &connection.UserID, &connection.QuizID); err != nil {
+			continue
+		}
+		if err := r.unregister(connection, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ConnectionRegistryImpl) isAlive(nodeID string) (error, bool) {
+	heartbeat, err := r.cache.ZScore(wsNodesKey, nodeID)
+	if err == redis.Nil {
+		return nil, false
+	}
+	if err != nil {
+		return err, false
+	}
+	return nil, int64(heartbeat) > time.Now().UnixMilli()-r.nodeTTL.Milliseconds()
+}
@@ -2,15 +2,56 @@ package manager
 
 import (
 	"context"
+	"errors"
+	"github.com/luulethe/quiz/quiz_lib/db"
 	"github.com/luulethe/quiz/quiz_lib/db/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"time"
 )
 
+var (
+	ErrAnswerSubmitted     = errors.New("answer already submitted")
+	ErrParticipantJoined   = errors.New("quiz participant already joined")
+	ErrParticipantNotFound = errors.New("quiz participant not found")
+	ErrQuizStatusChanged   = errors.New("quiz status changed")
+	ErrQuestionsMismatch   = errors.New("questions mismatch")
+)
+
+// ParticipantMessages builds the outbox messages of a participant write from the participant it wrote,
+// within its transaction
+type ParticipantMessages func(participant *model.QuizParticipantTab) ([]*model.QuizOutboxTab, error)
+
 type QuizDAO interface {
 	FindQuizByID(ctx context.Context, quizID int64) (error, *model.QuizTab)
-	CreateQuizParticipant(ctx context.Context, quizID int64, userID int64) (error, *model.QuizParticipantTab)
+	FindQuizByIDFromMaster(ctx context.Context, quizID int64) (error, *model.QuizTab)
+	ListQuizzesToStart(ctx context.Context, status int32, startBefore int64, limit int) (error, []*model.QuizTab)
+	ListQuizzesToEnd(ctx context.Context, status int32, endBefore int64, limit int) (error, []*model.QuizTab)
+	CreateQuiz(ctx context.Context, quiz *model.QuizTab) (error, *model.QuizTab)
+	UpdateQuiz(
+		ctx context.Context, quizID int64, status int32, updates map[string]interface{}, messages ...*model.QuizOutboxTab,
+	) (error, bool)
+	DeleteQuiz(ctx context.Context, quizID int64, status int32) error
+	CreateQuizParticipant(
+		ctx context.Context, quizID int64, userID int64, messages ParticipantMessages,
+	) (error, *model.QuizParticipantTab)
 	FindQuizParticipant(ctx context.Context, quizID int64, userID int64) (error, *model.QuizParticipantTab)
+	ListQuizParticipants(ctx context.Context, quizID int64) (error, []*model.QuizParticipantTab)
+	FindQuestionByID(ctx context.Context, questionID int64) (error, *model.QuizQuestionTab)
+	ListQuizQuestions(ctx context.Context, quizID int64) (error, []*model.QuizQuestionTab)
+	ListQuizQuestionsFromMaster(ctx context.Context, quizID int64) (error, []*model.QuizQuestionTab)
+	CreateQuestion(
+		ctx context.Context, status int32, question *model.QuizQuestionTab, options []*model.QuizAnswerOptionTab,
+	) (error, *model.QuizQuestionTab)
+	ReorderQuestions(ctx context.Context, quizID int64, status int32, questionIDs []int64) error
+	FindAnswerOptionByID(ctx context.Context, answerID int64) (error, *model.QuizAnswerOptionTab)
+	SubmitAnswer(
+		ctx context.Context, answer *model.QuizUserAnswerTab, messages ParticipantMessages,
+	) (error, *model.QuizParticipantTab)
+	// the outbox methods work on the database of the index in DB.Databases(), each one has its outbox
+	ListPendingOutboxMessages(ctx context.Context, database int, limit int) (error, []*model.QuizOutboxTab)
+	MarkOutboxMessagesSent(ctx context.Context, database int, ids []int64) error
+	DeleteSentOutboxMessages(ctx context.Context, database int, sentBefore int64, limit int) (error, int64)
 }
 
 func NewQuizDAO(dep *Dependency) QuizDAO {
@@ -22,9 +63,17 @@ type QuizDAOImpl struct {
 }
 
 func (d *QuizDAOImpl) FindQuizByID(ctx context.Context, quizID int64) (error, *model.QuizTab) {
+	return findQuizByID(d.dep.DB.Reader(ctx), quizID)
+}
+
+// FindQuizByIDFromMaster is used before a write, when the quiz may just have been written
+func (d *QuizDAOImpl) FindQuizByIDFromMaster(ctx context.Context, quizID int64) (error, *model.QuizTab) {
+	return findQuizByID(d.dep.DB.Master(), quizID)
+}
+
+func findQuizByID(conn *gorm.DB, quizID int64) (error, *model.QuizTab) {
 	quiz := model.QuizTab{}
-	slave := d.dep.DB.Slave()
-	sqlResult := slave.First(&quiz, quizID)
+	sqlResult := conn.First(&quiz, quizID)
 
 	if sqlResult.Error == gorm.ErrRecordNotFound {
 		return nil, nil
@@ -36,7 +85,117 @@ func (d *QuizDAOImpl) FindQuizByID(ctx context.Context, quizID int64) (error, *m
 	return nil, &quiz
 }
 
-func (d *QuizDAOImpl) CreateQuizParticipant(ctx context.Context, quizID int64, userID int64) (error, *model.QuizParticipantTab) {
+// ListQuizzesToStart lists the quizzes of the status with a start_time set and not after startBefore
+func (d *QuizDAOImpl) ListQuizzesToStart(
+	ctx context.Context, status int32, startBefore int64, limit int,
+) (error, []*model.QuizTab) {
+	var quizzes []*model.QuizTab
+	master := d.dep.DB.Master()
+	sqlResult := master.Where("status = ? and start_time > 0 and start_time <= ?", status, startBefore).
+		Order("start_time").Limit(limit).Find(&quizzes)
+	if sqlResult.Error != nil {
+		return sqlResult.Error, nil
+	}
+
+	return nil, quizzes
+}
+
+// ListQuizzesToEnd lists the quizzes of the status with an end_time set and not after endBefore
+func (d *QuizDAOImpl) ListQuizzesToEnd(
+	ctx context.Context, status int32, endBefore int64, limit int,
+) (error, []*model.QuizTab) {
+	var quizzes []*model.QuizTab
+	master := d.dep.DB.Master()
+	sqlResult := master.Where("status = ? and end_time > 0 and end_time <= ?", status, endBefore).
+		Order("end_time").Limit(limit).Find(&quizzes)
+	if sqlResult.Error != nil {
+		return sqlResult.Error, nil
+	}
+
+	return nil, quizzes
+}
+
+func (d *QuizDAOImpl) CreateQuiz(ctx context.Context, quiz *model.QuizTab) (error, *model.QuizTab) {
+	now := time.Now().UnixMilli()
+	quiz.CreatedTime = now
+	quiz.UpdatedTime = now
+	master := d.dep.DB.Master()
+	sqlResult := master.Create(quiz)
+	if sqlResult.Error != nil {
+		return sqlResult.Error, nil
+	}
+
+	return nil, quiz
+}
+
+// UpdateQuiz applies the updates only if the quiz is still in the status, and stores the outbox messages
+// in the same transaction. It returns false if the quiz has been moved to another status meanwhile.
+func (d *QuizDAOImpl) UpdateQuiz(
+	ctx context.Context, quizID int64, status int32, updates map[string]interface{}, messages ...*model.QuizOutboxTab,
+) (error, bool) {
+	updated := false
+	master := d.dep.DB.Master()
+	err := master.Transaction(func(tx *gorm.DB) error {
+		sqlResult := tx.Model(&model.QuizTab{}).
+			Where("id = ? and status = ?", quizID, status).
+			Updates(updates)
+		if sqlResult.Error != nil {
+			return sqlResult.Error
+		}
+		if sqlResult.RowsAffected == 0 {
+			return nil
+		}
+		updated = true
+		return createOutboxMessages(tx, messages)
+	})
+	if err != nil {
+		return err, false
+	}
+
+	return nil, updated
+}
+
+// DeleteQuiz deletes the quiz with its questions and answer options if it is still in the status,
+// otherwise it returns ErrQuizStatusChanged
+func (d *QuizDAOImpl) DeleteQuiz(ctx context.Context, quizID int64, status int32) error {
+	master := d.dep.DB.Master()
+	return master.Transaction(func(tx *gorm.DB) error {
+		err := lockQuiz(tx, quizID, status)
+		if err != nil {
+			return err
+		}
+
+		questionIDs := tx.Model(&model.QuizQuestionTab{}).Select("id").Where("quiz_id = ?", quizID)
+		sqlResult := tx.Where("question_id IN (?)", questionIDs).Delete(&model.QuizAnswerOptionTab{})
+		if sqlResult.Error != nil {
+			return sqlResult.Error
+		}
+		sqlResult = tx.Where("quiz_id = ?", quizID).Delete(&model.QuizQuestionTab{})
+		if sqlResult.Error != nil {
+			return sqlResult.Error
+		}
+		return tx.Delete(&model.QuizTab{}, quizID).Error
+	})
+}
+
+// lockQuiz locks the quiz row for the transaction, it returns ErrQuizStatusChanged if the quiz is not in the status
+func lockQuiz(tx *gorm.DB, quizID int64, status int32) error {
+	quiz := model.QuizTab{}
+	sqlResult := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? and status = ?", quizID, status).
+		First(&quiz)
+	if sqlResult.Error == gorm.ErrRecordNotFound {
+		return ErrQuizStatusChanged
+	}
+	return sqlResult.Error
+}
+
+// CreateQuizParticipant stores the participant and its outbox messages in one transaction on the shard of the quiz.
+// It returns ErrParticipantJoined if the user already joined the quiz, as the unique index
+// of (quiz_id, user_id) rejects the insert.
+func (d *QuizDAOImpl) CreateQuizParticipant(
+	ctx context.Context, quizID int64, userID int64, messages ParticipantMessages,
+) (error, *model.QuizParticipantTab) {
 	quiz := &model.QuizParticipantTab{
 		QuizID:      quizID,
 		UserID:      userID,
@@ -44,19 +203,30 @@ func (d *QuizDAOImpl) CreateQuizParticipant(ctx context.Context, quizID int64, u
 		CreatedTime: time.Now().UnixMilli(),
 		UpdatedTime: time.Now().UnixMilli(),
 	}
-	master := d.dep.DB.Master()
-	sqlResult := master.Create(&quiz)
-	if sqlResult.Error != nil {
-		return sqlResult.Error, nil
+	shard := d.dep.DB.Shard(quizID)
+	err := shard.Master().Transaction(func(tx *gorm.DB) error {
+		sqlResult := tx.Create(&quiz)
+		if db.IsDuplicateKeyError(sqlResult.Error) {
+			return ErrParticipantJoined
+		}
+		if sqlResult.Error != nil {
+			return sqlResult.Error
+		}
+		return createParticipantMessages(tx, quiz, messages)
+	})
+	if err != nil {
+		return err, nil
 	}
+	shard.MarkWrite(ctx)
 
 	return nil, quiz
 }
 
+// FindQuizParticipant reads from master within the sticky window of the user, the participant is read right after joining
 func (d *QuizDAOImpl) FindQuizParticipant(ctx context.Context, quizID int64, userID int64) (error, *model.QuizParticipantTab) {
 	quiz := model.QuizParticipantTab{}
-	slave := d.dep.DB.Slave()
-	sqlResult := slave.Where("quiz_id = ? and  user_id = ? ", quizID, userID).First(&quiz)
+	reader := d.dep.DB.Shard(quizID).Reader(ctx)
+	sqlResult := reader.Where("quiz_id = ? and  user_id = ? ", quizID, userID).First(&quiz)
 
 	if sqlResult.Error == gorm.ErrRecordNotFound {
 		return nil, nil
@@ -67,3 +237,261 @@ func (d *QuizDAOImpl) FindQuizParticipant(ctx context.Context, quizID int64, use
 
 	return nil, &quiz
 }
+
+// ListQuizParticipants reads from master, the result is used to rebuild data right after a write
+func (d *QuizDAOImpl) ListQuizParticipants(ctx context.Context, quizID int64) (error, []*model.QuizParticipantTab) {
+	var participants []*model.QuizParticipantTab
+	master := d.dep.DB.Shard(quizID).Master()
+	sqlResult := master.Where("quiz_id = ?", quizID).Find(&participants)
+	if sqlResult.Error != nil {
+		return sqlResult.Error, nil
+	}
+
+	return nil, participants
+}
+
+func (d *QuizDAOImpl) FindQuestionByID(ctx context.Context, questionID int64) (error, *model.QuizQuestionTab) {
+	question := model.QuizQuestionTab{}
+	slave := d.dep.DB.Slave()
+	sqlResult := slave.First(&question, questionID)
+
+	if sqlResult.Error == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if sqlResult.Error != nil {
+		return sqlResult.Error, nil
+	}
+
+	return nil, &question
+}
+
+func (d *QuizDAOImpl) ListQuizQuestions(ctx context.Context, quizID int64) (error, []*model.QuizQuestionTab) {
+	return listQuizQuestions(d.dep.DB.Slave(), quizID)
+}
+
+// ListQuizQuestionsFromMaster is used before a write, when a question may just have been added
+func (d *QuizDAOImpl) ListQuizQuestionsFromMaster(ctx context.Context, quizID int64) (error, []*model.QuizQuestionTab) {
+	return listQuizQuestions(d.dep.DB.Master(), quizID)
+}
+
+func listQuizQuestions(conn *gorm.DB, quizID int64) (error, []*model.QuizQuestionTab) {
+	var questions []*model.QuizQuestionTab
+	sqlResult := conn.Where("quiz_id = ?", quizID).Order("seq, id").Find(&questions)
+	if sqlResult.Error != nil {
+		return sqlResult.Error, nil
+	}
+
+	return nil, questions
+}
+
+// CreateQuestion appends the question with its answer options to the quiz if the quiz is still in the status,
+// otherwise it returns ErrQuizStatusChanged
+func (d *QuizDAOImpl) CreateQuestion(
+	ctx context.Context, status int32, question *model.QuizQuestionTab, options []*model.QuizAnswerOptionTab,
+) (error, *model.QuizQuestionTab) {
+	now := time.Now().UnixMilli()
+	master := d.dep.DB.Master()
+	err := master.Transaction(func(tx *gorm.DB) error {
+		err := lockQuiz(tx, question.QuizID, status)
+		if err != nil {
+			return err
+		}
+
+		var maxSeq int32
+		sqlResult := tx.Model(&model.QuizQuestionTab{}).
+			Select("COALESCE(MAX(seq), 0)").
+			Where("quiz_id = ?", question.QuizID).
+			Scan(&maxSeq)
+		if sqlResult.Error != nil {
+			return sqlResult.Error
+		}
+
+		question.Seq = maxSeq + 1
+		question.CreatedTime = now
+		question.UpdatedTime = now
+		sqlResult = tx.Create(question)
+		if sqlResult.Error != nil {
+			return sqlResult.Error
+		}
+
+		for _, option := range options {
+			option.QuestionID = question.ID
+			option.CreatedTime = now
+		}
+		return tx.Create(&options).Error
+	})
+	if err != nil {
+		return err, nil
+	}
+
+	return nil, question
+}
+
+// ReorderQuestions sets the seq of the questions of the quiz to their position in questionIDs.
+// It returns ErrQuestionsMismatch unless questionIDs lists every question of the quiz once,
+// and ErrQuizStatusChanged if the quiz is not in the status anymore.
+func (d *QuizDAOImpl) ReorderQuestions(ctx context.Context, quizID int64, status int32, questionIDs []int64) error {
+	now := time.Now().UnixMilli()
+	master := d.dep.DB.Master()
+	return master.Transaction(func(tx *gorm.DB) error {
+		err := lockQuiz(tx, quizID, status)
+		if err != nil {
+			return err
+		}
+
+		var questions []*model.QuizQuestionTab
+		sqlResult := tx.Where("quiz_id = ?", quizID).Find(&questions)
+		if sqlResult.Error != nil {
+			return sqlResult.Error
+		}
+		existing := make(map[int64]bool, len(questions))
+		for _, question := range questions {
+			existing[question.ID] = true
+		}
+		if len(questionIDs) != len(questions) {
+			return ErrQuestionsMismatch
+		}
+		for _, questionID := range questionIDs {
+			if !existing[questionID] {
+				return ErrQuestionsMismatch
+			}
+			delete(existing, questionID)
+		}
+
+		for i, questionID := range questionIDs {
+			sqlResult = tx.Model(&model.QuizQuestionTab{}).
+				Where("id = ?", questionID).
+				Updates(map[string]interface{}{"seq": i + 1, "updated_time": now})
+			if sqlResult.Error != nil {
+				return sqlResult.Error
+			}
+		}
+		return nil
+	})
+}
+
+func (d *QuizDAOImpl) FindAnswerOptionByID(ctx context.Context, answerID int64) (error, *model.QuizAnswerOptionTab) {
+	option := model.QuizAnswerOptionTab{}
+	slave := d.dep.DB.Slave()
+	sqlResult := slave.First(&option, answerID)
+
+	if sqlResult.Error == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if sqlResult.Error != nil {
+		return sqlResult.Error, nil
+	}
+
+	return nil, &option
+}
+
+// SubmitAnswer stores the answer, adds its score to the participant and stores the outbox messages of the updated
+// participant in one transaction on the shard of the quiz.
+// It returns ErrAnswerSubmitted if the user already answered the question
+// and ErrParticipantNotFound if the user has not joined the quiz.
+func (d *QuizDAOImpl) SubmitAnswer(
+	ctx context.Context, answer *model.QuizUserAnswerTab, messages ParticipantMessages,
+) (error, *model.QuizParticipantTab) {
+	participant := &model.QuizParticipantTab{}
+	now := time.Now().UnixMilli()
+	answer.CreatedTime = now
+
+	shard := d.dep.DB.Shard(answer.QuizID)
+	err := shard.Master().Transaction(func(tx *gorm.DB) error {
+		sqlResult := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("quiz_id = ? and user_id = ?", answer.QuizID, answer.UserID).
+			First(participant)
+		if sqlResult.Error == gorm.ErrRecordNotFound {
+			return ErrParticipantNotFound
+		}
+		if sqlResult.Error != nil {
+			return sqlResult.Error
+		}
+
+		sqlResult = tx.Create(answer)
+		if db.IsDuplicateKeyError(sqlResult.Error) {
+			return ErrAnswerSubmitted
+		}
+		if sqlResult.Error != nil {
+			return sqlResult.Error
+		}
+
+		participant.Score += answer.Score
+		participant.UpdatedTime = now
+		sqlResult = tx.Model(participant).Updates(map[string]interface{}{
+			"score":        gorm.Expr("score + ?", answer.Score),
+			"updated_time": now,
+		})
+		if sqlResult.Error != nil {
+			return sqlResult.Error
+		}
+		return createParticipantMessages(tx, participant, messages)
+	})
+	if err != nil {
+		return err, nil
+	}
+	shard.MarkWrite(ctx)
+
+	return nil, participant
+}
+
+func createParticipantMessages(tx *gorm.DB, participant *model.QuizParticipantTab, messages ParticipantMessages) error {
+	if messages == nil {
+		return nil
+	}
+	outboxMessages, err := messages(participant)
+	if err != nil {
+		return err
+	}
+	return createOutboxMessages(tx, outboxMessages)
+}
+
+func createOutboxMessages(tx *gorm.DB, messages []*model.QuizOutboxTab) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	now := time.Now().UnixMilli()
+	for _, message := range messages {
+		message.Status = model.OutboxStatusPending
+		message.CreatedTime = now
+	}
+	return tx.Create(&messages).Error
+}
+
+// ListPendingOutboxMessages reads from master in insertion order, so that messages are relayed in order
+func (d *QuizDAOImpl) ListPendingOutboxMessages(ctx context.Context, database int, limit int) (error, []*model.QuizOutboxTab) {
+	var messages []*model.QuizOutboxTab
+	master := d.dep.DB.Databases()[database].Master()
+	sqlResult := master.Where("status = ?", model.OutboxStatusPending).Order("id").Limit(limit).Find(&messages)
+	if sqlResult.Error != nil {
+		return sqlResult.Error, nil
+	}
+
+	return nil, messages
+}
+
+func (d *QuizDAOImpl) MarkOutboxMessagesSent(ctx context.Context, database int, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	master := d.dep.DB.Databases()[database].Master()
+	return master.Model(&model.QuizOutboxTab{}).
+		Where("id in ?", ids).
+		Updates(map[string]interface{}{
+			"status":    model.OutboxStatusSent,
+			"sent_time": time.Now().UnixMilli(),
+		}).Error
+}
+
+func (d *QuizDAOImpl) DeleteSentOutboxMessages(ctx context.Context, database int, sentBefore int64, limit int) (error, int64) {
+	master := d.dep.DB.Databases()[database].Master()
+	sqlResult := master.Exec(
+		"DELETE FROM quiz_outbox_tab WHERE status = ? and sent_time < ? LIMIT ?",
+		model.OutboxStatusSent, sentBefore, limit,
+	)
+	if sqlResult.Error != nil {
+		return sqlResult.Error, 0
+	}
+
+	return nil, sqlResult.RowsAffected
+}
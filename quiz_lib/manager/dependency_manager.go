@@ -2,22 +2,55 @@ package manager
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+
 	"github.com/luulethe/quiz/config"
+	"github.com/luulethe/quiz/go_common/auth"
+	"github.com/luulethe/quiz/go_common/cache"
+	"github.com/luulethe/quiz/go_common/log"
 	"github.com/luulethe/quiz/go_common/metrics"
 	"github.com/luulethe/quiz/quiz_lib/db"
+	"github.com/luulethe/quiz/quiz_lib/db/migration"
 )
 
 // Dependency defines the global dependencies
 type Dependency struct {
-	DB          db.QuizDB
+	DB          db.ShardedQuizDB
 	QuizManager QuizManager
 	QuizDAO     QuizDAO
+	LeaderBoard LeaderBoard
+	Connections ConnectionRegistry
+	Notifier    LeaderBoardNotifier
+	Accounts    AccountClient // nil without account service, tests may set a fake
+	RateLimiter RateLimiter   // nil when rate limiting is disabled
+	Idempotency IdempotencyStore
+	Cache       cache.EnhancedCache
+	Producer    Producer
 	Stats       *metrics.StatsCollector
+	Auth        *auth.TokenVerifier
+	Conf        *config.Configuration // the config at startup, see Config for the reloaded one
+	Dynamic     *DynamicConfig
+
+	redisCache *cache.RedisCache
 }
 
 // Close release resources
 func (d *Dependency) Close() {
+	if d.Dynamic != nil {
+		d.Dynamic.Close()
+	}
 	d.DB.Close()
+	if closer, ok := d.Accounts.(io.Closer); ok {
+		closer.Close()
+	}
+	if d.Producer != nil {
+		d.Producer.Close()
+	}
+	if d.redisCache != nil {
+		d.redisCache.Close()
+	}
 }
 
 // Init initializes the dependency
@@ -25,14 +58,170 @@ func (d *Dependency) Init(
 	ctx context.Context, conf *config.Configuration, stats *metrics.StatsCollector, metricsCollection *MetricsCollection,
 ) error {
 	d.Stats = stats
-	mainDB := conf.MySQL[0]
-	quizDBManager, err := db.NewQuizDBFromConfig(mainDB)
+	d.Conf = conf
+	d.Auth = NewTokenVerifier(conf.Auth)
+	redisCache, err := NewRedisCache(conf.Redis)
+	if err != nil {
+		return err
+	}
+	d.redisCache = redisCache
+	d.Cache = redisCache
+
+	quizDBManager, err := NewShardedQuizDB(conf, redisCache, metricsCollection)
 	if err != nil {
 		return err
 	}
 	d.DB = quizDBManager
+
+	producer, err := NewProducer(ctx, conf.QuizKafka, stats)
+	if err != nil {
+		return err
+	}
+	d.Producer = producer
+
+	if conf.Account != nil {
+		accounts, err := NewAccountClient(conf.Account, redisCache, stats)
+		if err != nil {
+			return err
+		}
+		d.Accounts = accounts
+	}
+
+	rateLimiter, err := NewRateLimiter(conf.RateLimit, d.Cache)
+	if err != nil {
+		return err
+	}
+	d.RateLimiter = rateLimiter
+	d.Idempotency = NewIdempotencyStore(d.Cache, conf.Idempotency)
+	d.initDynamicConfig(conf, stats)
+
 	d.QuizManager = NewQuizManager(d)
 	d.QuizDAO = NewQuizDAO(d)
+	d.LeaderBoard = NewLeaderBoard(d)
+	d.Connections = NewConnectionRegistry(d.Cache, conf.NodeTTL())
+	d.Notifier = NewLeaderBoardNotifier(d)
 
 	return nil
 }
+
+// initDynamicConfig subscribes the components which take a reloaded config
+func (d *Dependency) initDynamicConfig(conf *config.Configuration, stats *metrics.StatsCollector) {
+	d.Dynamic = NewDynamicConfig(conf, d.Cache, stats)
+	d.Dynamic.Subscribe(func(ctx context.Context, reloaded *config.Configuration) error {
+		if reloaded.RateLimit.Backend != conf.RateLimit.Backend {
+			return fmt.Errorf("rate_limit.backend %q needs a restart", reloaded.RateLimit.Backend)
+		}
+		return nil
+	})
+	d.Dynamic.Subscribe(func(ctx context.Context, reloaded *config.Configuration) error {
+		level := log.InfoLevel
+		if reloaded.Debug {
+			level = log.DebugLevel
+		}
+		log.SetLevel(level)
+		return nil
+	})
+	if accounts, ok := d.Accounts.(*CachedAccountClient); ok {
+		d.Dynamic.Subscribe(accounts.ApplyConfig)
+	}
+}
+
+// Config returns the active config, the reloaded one if the config is watched
+func (d *Dependency) Config() *config.Configuration {
+	if d.Dynamic != nil {
+		return d.Dynamic.Current()
+	}
+	return d.Conf
+}
+
+// NewShardedQuizDB connects to every mysql database of the config with its replicas, with the sticky master
+// windows in cache, and registers the stats collector of each database
+func NewShardedQuizDB(
+	conf *config.Configuration, cache cache.EnhancedCache, metricsCollection *MetricsCollection,
+) (db.ShardedQuizDB, error) {
+	if len(conf.MySQL) == 0 {
+		return nil, errors.New("missing mysql config")
+	}
+	databases := make([]db.QuizDB, 0, len(conf.MySQL))
+	closeAll := func() {
+		for _, database := range databases {
+			database.Close()
+		}
+	}
+	for i, dbConf := range conf.MySQL {
+		sticky := NewRedisStickyStore(cache, databaseName(dbConf, i), dbConf.StickyMasterWindow)
+		quizDB, err := db.NewQuizDBFromConfig(dbConf, sticky)
+		if err != nil {
+			closeAll()
+			return nil, err
+		}
+		databases = append(databases, quizDB)
+
+		if conf.Migration.RequireLatest {
+			if err := checkLatestSchema(quizDB); err != nil {
+				closeAll()
+				return nil, fmt.Errorf("database %d: %w", i, err)
+			}
+		}
+		if metricsCollection != nil {
+			if stater, ok := quizDB.(db.Stater); ok {
+				metricsCollection.AddCollector(db.NewDBStatsCollector(databaseName(dbConf, i), stater))
+			}
+		}
+	}
+
+	sharded, err := db.NewShardedQuizDB(databases, conf.Sharding.Shards)
+	if err != nil {
+		closeAll()
+		return nil, err
+	}
+	return sharded, nil
+}
+
+func checkLatestSchema(quizDB db.QuizDB) error {
+	sqlDB, err := quizDB.Master().DB()
+	if err != nil {
+		return err
+	}
+	return migration.CheckLatest(sqlDB)
+}
+
+// databaseName labels the stats of a database, the shards may use the same database name on other servers
+func databaseName(conf db.Config, index int) string {
+	if index == 0 {
+		return conf.DBName
+	}
+	return fmt.Sprintf("%s_%d", conf.DBName, index)
+}
+
+// NewRedisCache connects to the redis of the config
+func NewRedisCache(conf *config.RedisConfig) (*cache.RedisCache, error) {
+	if conf == nil {
+		return nil, errors.New("missing redis config")
+	}
+	return cache.NewRedisClient(conf.Address, &cache.RedisOption{
+		Password:     conf.Password,
+		DB:           conf.DB,
+		PoolSize:     conf.PoolSize,
+		DialTimeout:  conf.Timeout,
+		ReadTimeout:  conf.Timeout,
+		WriteTimeout: conf.Timeout,
+	})
+}
+
+// NewTokenVerifier verifies the user tokens with the keys of the config
+func NewTokenVerifier(conf config.AuthConfig) *auth.TokenVerifier {
+	keys := make([]auth.Key, 0, len(conf.Keys))
+	for _, key := range conf.Keys {
+		keys = append(keys, auth.Key{ID: key.ID, Secret: key.Secret})
+	}
+	return auth.NewTokenVerifier(keys...)
+}
+
+// QuizEventTopic is the kafka topic of QuizEvent
+func (d *Dependency) QuizEventTopic() string {
+	if d.Conf == nil || d.Conf.QuizKafka == nil {
+		return ""
+	}
+	return d.Conf.QuizKafka.Topic
+}
@@ -0,0 +1,206 @@
+package manager
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/luulethe/quiz/config"
+	"github.com/luulethe/quiz/go_common/cache"
+	"github.com/luulethe/quiz/go_common/log"
+	"github.com/luulethe/quiz/go_common/metrics"
+)
+
+const (
+	configReloadAction  = "ConfigReload"
+	configVersionAction = "ConfigVersion"
+
+	configResultRollback metrics.ResultType = "Rollback"
+)
+
+// ConfigSubscriber applies a reloaded config. When a subscriber fails, the subscribers which were
+// already given the new config are given the previous one again and the previous one stays active.
+type ConfigSubscriber func(ctx context.Context, conf *config.Configuration) error
+
+// ConfigSnapshot is an active config, the version is increased by every applied reload
+type ConfigSnapshot struct {
+	Version int64
+	Conf    *config.Configuration
+}
+
+// DynamicConfig watches the config files and the reload.redis_key, and swaps the active config when they change
+type DynamicConfig struct {
+	cache cache.EnhancedCache
+	stats *metrics.StatsCollector
+
+	current atomic.Value // *ConfigSnapshot
+
+	mutex       sync.Mutex // serializes the reloads
+	loader      config.Loader
+	digest      [sha256.Size]byte // of the sources of the last reload
+	subscribers []ConfigSubscriber
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewDynamicConfig(conf *config.Configuration, cache cache.EnhancedCache, stats *metrics.StatsCollector) *DynamicConfig {
+	c := &DynamicConfig{cache: cache, stats: stats, stop: make(chan struct{})}
+	c.current.Store(&ConfigSnapshot{Version: 1, Conf: conf})
+	c.reportVersion(1)
+	return c
+}
+
+// Current returns the active config, it must not be modified
+func (c *DynamicConfig) Current() *config.Configuration {
+	return c.Snapshot().Conf
+}
+
+func (c *DynamicConfig) Snapshot() *ConfigSnapshot {
+	return c.current.Load().(*ConfigSnapshot)
+}
+
+// Subscribe adds a subscriber, which is called by the following reloads in the order of subscription
+func (c *DynamicConfig) Subscribe(subscriber ConfigSubscriber) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.subscribers = append(c.subscribers, subscriber)
+}
+
+// Watch reloads the config of the loader every reload.interval of the active config until Close
+func (c *DynamicConfig) Watch(ctx context.Context, loader config.Loader) {
+	interval := c.Current().Reload.Interval
+	if interval <= 0 {
+		return
+	}
+	c.mutex.Lock()
+	c.loader = loader
+	c.mutex.Unlock()
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			// failures are logged and reported by Reload, the last good config stays active
+			_ = c.Reload(ctx)
+			select {
+			case <-c.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Reload loads the config again when its sources changed, and applies it if it's valid and accepted by the subscribers
+func (c *DynamicConfig) Reload(ctx context.Context) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	err, remote, digest := c.readSources()
+	if err != nil {
+		log.Errorff(ctx, "DynamicConfig.Reload|readSources|err:%v", err)
+		c.reportCount(metrics.ResultError)
+		return err
+	}
+	if digest == c.digest {
+		return nil
+	}
+	// a failed config isn't loaded again until its sources change
+	c.digest = digest
+
+	loader := c.loader
+	loader.Remote = remote
+	conf, err := loader.Load()
+	if err != nil {
+		log.Errorff(ctx, "DynamicConfig.Reload|load|err:%v", err)
+		c.reportCount(metrics.ResultError)
+		return err
+	}
+
+	previous := c.Snapshot()
+	if reflect.DeepEqual(conf, previous.Conf) {
+		return nil
+	}
+	for i, subscriber := range c.subscribers {
+		if err := subscriber(ctx, conf); err != nil {
+			log.Errorff(ctx, "DynamicConfig.Reload|subscriber:%d|version:%d|err:%v", i, previous.Version, err)
+			c.rollback(ctx, previous.Conf, i)
+			c.reportCount(configResultRollback)
+			return err
+		}
+	}
+
+	version := previous.Version + 1
+	c.current.Store(&ConfigSnapshot{Version: version, Conf: conf})
+	log.Infof(ctx, "DynamicConfig.Reload|version:%d", version)
+	c.reportCount(metrics.ResultSuccess)
+	c.reportVersion(version)
+	return nil
+}
+
+// rollback gives the previous config to the subscribers up to the failed one
+func (c *DynamicConfig) rollback(ctx context.Context, previous *config.Configuration, failed int) {
+	for i := failed; i >= 0; i-- {
+		if err := c.subscribers[i](ctx, previous); err != nil {
+			log.Errorff(ctx, "DynamicConfig.rollback|subscriber:%d|err:%v", i, err)
+		}
+	}
+}
+
+// readSources returns the yaml of the redis key and the digest of every source
+func (c *DynamicConfig) readSources() (error, string, [sha256.Size]byte) {
+	hash := sha256.New()
+	for _, file := range []string{c.loader.BaseFile, c.loader.OverlayFile} {
+		if file == "" {
+			continue
+		}
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err, "", [sha256.Size]byte{}
+		}
+		fmt.Fprintf(hash, "%s:%d:", file, len(content))
+		hash.Write(content)
+	}
+
+	remote := ""
+	if key := c.Current().Reload.RedisKey; key != "" && c.cache != nil {
+		value, err := c.cache.Get(key)
+		if err != nil && err != redis.Nil {
+			return err, "", [sha256.Size]byte{}
+		}
+		remote, _ = value.(string)
+	}
+	fmt.Fprintf(hash, "remote:%d:", len(remote))
+	hash.Write([]byte(remote))
+
+	var digest [sha256.Size]byte
+	copy(digest[:], hash.Sum(nil))
+	return nil, remote, digest
+}
+
+func (c *DynamicConfig) reportCount(result metrics.ResultType) {
+	if c.stats != nil {
+		c.stats.ReportCount(1, configReloadAction, string(result))
+	}
+}
+
+func (c *DynamicConfig) reportVersion(version int64) {
+	if c.stats != nil {
+		c.stats.SetGauge(float64(version), configVersionAction, "")
+	}
+}
+
+// Close stops the watch
+func (c *DynamicConfig) Close() {
+	close(c.stop)
+	c.wg.Wait()
+}
@@ -0,0 +1,138 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/luulethe/quiz/config"
+	"github.com/luulethe/quiz/go_common/cache"
+	"github.com/luulethe/quiz/go_common/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDynamicConfig = `
+debug: true
+rate_limit:
+  backend: "memory"
+reload:
+  redis_key: "quiz_config"
+`
+
+// newTestDynamicConfig returns a dynamic config loaded from a temporary file, and the redis of its remote key
+func newTestDynamicConfig(t *testing.T) (*DynamicConfig, *miniredis.Miniredis, string) {
+	server := miniredis.RunT(t)
+	redisCache, err := cache.NewRedisClient(server.Addr(), &cache.RedisOption{})
+	require.NoError(t, err)
+	t.Cleanup(func() { redisCache.Close() })
+
+	file := filepath.Join(t.TempDir(), "base.yml")
+	require.NoError(t, ioutil.WriteFile(file, []byte(testDynamicConfig), 0644))
+	loader := config.Loader{BaseFile: file, Env: []string{}}
+	conf, err := loader.Load()
+	require.NoError(t, err)
+
+	c := NewDynamicConfig(conf, redisCache, nil)
+	c.loader = loader
+	// the first reload only records the digest of the sources
+	require.NoError(t, c.Reload(context.Background()))
+	require.Equal(t, int64(1), c.Snapshot().Version)
+	return c, server, file
+}
+
+func TestDynamicConfig_Reload(t *testing.T) {
+	ctx := context.Background()
+	c, server, file := newTestDynamicConfig(t)
+	var given []bool
+	c.Subscribe(func(ctx context.Context, conf *config.Configuration) error {
+		given = append(given, conf.Debug)
+		return nil
+	})
+
+	// the sources didn't change
+	require.NoError(t, c.Reload(ctx))
+	assert.Empty(t, given)
+
+	// the remote key is applied over the file
+	server.Set("quiz_config", "debug: false")
+	require.NoError(t, c.Reload(ctx))
+	assert.Equal(t, []bool{false}, given)
+	assert.Equal(t, int64(2), c.Snapshot().Version)
+	assert.False(t, c.Current().Debug)
+
+	// a changed source which gives the same config is not applied
+	server.Set("quiz_config", "debug: false # same")
+	require.NoError(t, c.Reload(ctx))
+	assert.Equal(t, []bool{false}, given)
+	assert.Equal(t, int64(2), c.Snapshot().Version)
+
+	// a changed file
+	require.NoError(t, ioutil.WriteFile(file, []byte(testDynamicConfig+"\nlisten: \":8081\"\n"), 0644))
+	server.Del("quiz_config")
+	require.NoError(t, c.Reload(ctx))
+	assert.Equal(t, []bool{false, true}, given)
+	assert.Equal(t, int64(3), c.Snapshot().Version)
+
+	// an invalid config keeps the active one, and isn't loaded again until its sources change
+	server.Set("quiz_config", "unknown_key: 1")
+	assert.Error(t, c.Reload(ctx))
+	assert.NoError(t, c.Reload(ctx))
+	assert.Equal(t, int64(3), c.Snapshot().Version)
+	assert.True(t, c.Current().Debug)
+}
+
+func TestDynamicConfig_Rollback(t *testing.T) {
+	ctx := context.Background()
+	c, server, _ := newTestDynamicConfig(t)
+	previous := c.Current()
+	var first []*config.Configuration
+	second := 0
+	c.Subscribe(func(ctx context.Context, conf *config.Configuration) error {
+		first = append(first, conf)
+		return nil
+	})
+	c.Subscribe(func(ctx context.Context, conf *config.Configuration) error {
+		second++
+		if !conf.Debug {
+			return errors.New("rejected")
+		}
+		return nil
+	})
+
+	// the second subscriber rejects the config, the first one is given the previous config again
+	server.Set("quiz_config", "debug: false")
+	assert.EqualError(t, c.Reload(ctx), "rejected")
+	require.Len(t, first, 2)
+	assert.False(t, first[0].Debug)
+	assert.Same(t, previous, first[1])
+	assert.Equal(t, 2, second)
+	assert.Same(t, previous, c.Current())
+	assert.Equal(t, int64(1), c.Snapshot().Version)
+}
+
+func TestDependency_DynamicConfig(t *testing.T) {
+	ctx := context.Background()
+	c, server, _ := newTestDynamicConfig(t)
+	d := &Dependency{Cache: c.cache}
+	d.initDynamicConfig(c.Current(), nil)
+	d.Dynamic.loader = c.loader
+	require.NoError(t, d.Dynamic.Reload(ctx))
+	level := log.GetLevel()
+	defer log.SetLevel(level)
+
+	// the level follows the debug of the reloaded config
+	server.Set("quiz_config", "debug: false")
+	require.NoError(t, d.Dynamic.Reload(ctx))
+	assert.Equal(t, log.InfoLevel, log.GetLevel())
+
+	// the backend can't change without a restart, the rejected config doesn't reach the level
+	server.Set("quiz_config", "debug: true\nrate_limit:\n  backend: redis")
+	assert.Error(t, d.Dynamic.Reload(ctx))
+	assert.Equal(t, log.InfoLevel, log.GetLevel())
+	assert.False(t, d.Config().Debug)
+	assert.Equal(t, int64(2), d.Dynamic.Snapshot().Version)
+}
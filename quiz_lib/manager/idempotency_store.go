@@ -0,0 +1,138 @@
+package manager
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/luulethe/quiz/config"
+	"github.com/luulethe/quiz/go_common/cache"
+	"github.com/luulethe/quiz/go_common/log"
+	pb "github.com/luulethe/quiz/quiz_lib/pb/gen"
+	"github.com/luulethe/quiz/quiz_lib/quiz_error"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	idempotencyKeyFormat      = "quiz_idempotency_%d_%d_%s" // user_id, command, request_id
+	defaultIdempotencyTTL     = 24 * time.Hour
+	defaultIdempotencyLockTTL = 30 * time.Second
+
+	// a stored value is the in flight marker, or the completed marker, followed by the payload hash,
+	// then the ResponseData for the completed marker
+	idempotencyInFlight  = "0"
+	idempotencyCompleted = "1"
+	idempotencyHashSize  = sha256.Size * 2
+)
+
+// ErrRequestIDReused rejects a request id sent again with another payload, its first response isn't an answer to it
+var ErrRequestIDReused = quiz_error.Newf(pb.Error_ERROR_INVALID_PARAMETER, "request_id was sent with another payload")
+
+// IdempotentRequest is a request of a user identified by its command and request id, Payload is the request sent
+type IdempotentRequest struct {
+	UserID    int64
+	Command   pb.Command
+	RequestID string
+	Payload   []byte
+}
+
+// IdempotencyStore keeps the first response per user, command and request id
+type IdempotencyStore interface {
+	// Begin marks the request in flight and returns true when it's the first one. Otherwise it returns
+	// the response of the first request, or nil while the first request is still in flight.
+	// It returns ErrRequestIDReused when the first request had another payload.
+	Begin(ctx context.Context, request *IdempotentRequest) (error, *pb.ResponseData, bool)
+	Complete(ctx context.Context, request *IdempotentRequest, response *pb.ResponseData) error
+	// Abort releases a request which failed, so that a retry runs again
+	Abort(ctx context.Context, request *IdempotentRequest) error
+}
+
+func NewIdempotencyStore(cache cache.EnhancedCache, conf config.IdempotencyConfig) IdempotencyStore {
+	store := &IdempotencyStoreImpl{cache: cache, ttl: conf.TTL, inFlightTTL: conf.InFlightTTL}
+	if store.ttl <= 0 {
+		store.ttl = defaultIdempotencyTTL
+	}
+	if store.inFlightTTL <= 0 {
+		store.inFlightTTL = defaultIdempotencyLockTTL
+	}
+	return store
+}
+
+type IdempotencyStoreImpl struct {
+	cache       cache.EnhancedCache
+	ttl         time.Duration
+	inFlightTTL time.Duration
+}
+
+func idempotencyKey(request *IdempotentRequest) string {
+	return fmt.Sprintf(idempotencyKeyFormat, request.UserID, request.Command, request.RequestID)
+}
+
+func payloadHash(request *IdempotentRequest) string {
+	hash := sha256.Sum256(request.Payload)
+	return hex.EncodeToString(hash[:])
+}
+
+func (s *IdempotencyStoreImpl) Begin(ctx context.Context, request *IdempotentRequest) (error, *pb.ResponseData, bool) {
+	key := idempotencyKey(request)
+	hash := payloadHash(request)
+	started, err := s.cache.SetNX(key, idempotencyInFlight+hash, s.inFlightTTL)
+	if err != nil {
+		log.Errorff(ctx, "IdempotencyStore.Begin|key:%v|err:%v", key, err)
+		return err, nil, false
+	}
+	if started {
+		return nil, nil, true
+	}
+
+	value, err := s.cache.Get(key)
+	if err == redis.Nil {
+		// the first request has just been released, it's retried later like an in flight one
+		return nil, nil, false
+	}
+	if err != nil {
+		log.Errorff(ctx, "IdempotencyStore.Begin|key:%v|err:%v", key, err)
+		return err, nil, false
+	}
+	stored, _ := value.(string)
+	if len(stored) < 1+idempotencyHashSize {
+		return nil, nil, false
+	}
+	if stored[1:1+idempotencyHashSize] != hash {
+		return ErrRequestIDReused, nil, false
+	}
+	if stored[:1] != idempotencyCompleted {
+		return nil, nil, false
+	}
+
+	response := &pb.ResponseData{}
+	if err := proto.Unmarshal([]byte(stored[1+idempotencyHashSize:]), response); err != nil {
+		log.Errorff(ctx, "IdempotencyStore.Begin|key:%v|err:%v", key, err)
+		return err, nil, false
+	}
+	return nil, response, false
+}
+
+func (s *IdempotencyStoreImpl) Complete(ctx context.Context, request *IdempotentRequest, response *pb.ResponseData) error {
+	key := idempotencyKey(request)
+	data, err := proto.Marshal(response)
+	if err == nil {
+		err = s.cache.Set(key, idempotencyCompleted+payloadHash(request)+string(data), s.ttl)
+	}
+	if err != nil {
+		log.Errorff(ctx, "IdempotencyStore.Complete|key:%v|err:%v", key, err)
+	}
+	return err
+}
+
+func (s *IdempotencyStoreImpl) Abort(ctx context.Context, request *IdempotentRequest) error {
+	key := idempotencyKey(request)
+	_, err := s.cache.Del(key)
+	if err != nil {
+		log.Errorff(ctx, "IdempotencyStore.Abort|key:%v|err:%v", key, err)
+	}
+	return err
+}
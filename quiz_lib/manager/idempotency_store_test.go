@@ -0,0 +1,64 @@
+package manager_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/luulethe/quiz/config"
+	"github.com/luulethe/quiz/go_common/cache"
+	"github.com/luulethe/quiz/quiz_lib/manager"
+	pb "github.com/luulethe/quiz/quiz_lib/pb/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyStore(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	redisCache, err := cache.NewRedisClient(server.Addr(), &cache.RedisOption{})
+	require.NoError(t, err)
+	t.Cleanup(func() { redisCache.Close() })
+	store := manager.NewIdempotencyStore(redisCache, config.IdempotencyConfig{})
+
+	join := &manager.IdempotentRequest{
+		UserID: 1, Command: pb.Command_CMD_JOIN_QUIZ, RequestID: "r1", Payload: []byte("quiz 1"),
+	}
+	err, _, started := store.Begin(ctx, join)
+	require.NoError(t, err)
+	assert.True(t, started)
+
+	// the same request id on another command is another request
+	submit := &manager.IdempotentRequest{
+		UserID: 1, Command: pb.Command_CMD_SUBMIT_ANSWER, RequestID: "r1", Payload: []byte("answer 1"),
+	}
+	err, _, started = store.Begin(ctx, submit)
+	require.NoError(t, err)
+	assert.True(t, started)
+
+	err, stored, started := store.Begin(ctx, join)
+	require.NoError(t, err)
+	assert.False(t, started)
+	assert.Nil(t, stored)
+	reused := &manager.IdempotentRequest{
+		UserID: 1, Command: pb.Command_CMD_JOIN_QUIZ, RequestID: "r1", Payload: []byte("quiz 2"),
+	}
+	err, _, _ = store.Begin(ctx, reused)
+	assert.True(t, errors.Is(err, manager.ErrRequestIDReused))
+
+	response := &pb.ResponseData{Result: pb.Error_ERROR_USER_JOINED}
+	require.NoError(t, store.Complete(ctx, join, response))
+	err, stored, started = store.Begin(ctx, join)
+	require.NoError(t, err)
+	assert.False(t, started)
+	require.NotNil(t, stored)
+	assert.Equal(t, pb.Error_ERROR_USER_JOINED, stored.Result)
+	err, _, _ = store.Begin(ctx, reused)
+	assert.True(t, errors.Is(err, manager.ErrRequestIDReused))
+
+	require.NoError(t, store.Abort(ctx, submit))
+	err, _, started = store.Begin(ctx, submit)
+	require.NoError(t, err)
+	assert.True(t, started)
+}
@@ -0,0 +1,407 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/luulethe/quiz/quiz_lib/db/model"
+)
+
+const (
+	leaderBoardKeyFormat     = "quiz_leader_board_%d"
+	leaderBoardTempKeyFormat = "quiz_leader_board_%d_rebuilding"
+	leaderBoardExpire        = 24 * time.Hour
+	leaderBoardBatchSize     = 500
+
+	// leaderBoardEmptyKeyFormat marks a quiz without participants, or which doesn't exist, so that its reads
+	// don't rebuild it from database every time. A score written for the quiz removes the marker.
+	leaderBoardEmptyKeyFormat = "quiz_leader_board_%d_empty"
+	leaderBoardEmptyExpire    = 10 * time.Second
+	// leaderBoardLockKeyFormat elects the server rebuilding a missing leader board for the reads, the other
+	// servers wait up to leaderBoardRebuildWait for it before rebuilding it themselves
+	leaderBoardLockKeyFormat  = "quiz_leader_board_%d_lock"
+	leaderBoardLockExpire     = 10 * time.Second
+	leaderBoardRebuildWait    = 2 * time.Second
+	leaderBoardRebuildPolling = 20 * time.Millisecond
+
+	// leaderBoardTieBits is the width of the tie-break in the low bits of a member score. A float64 holds the
+	// integers up to 2^53 exactly, so the score takes the 31 bits above and must be in [0, 2^31).
+	leaderBoardTieBits = 21
+	// leaderBoardMaxTie is the last second of the tie-break, about 24 days after the quiz start.
+	// The updates after it tie, they are then ordered by user id.
+	leaderBoardMaxTie = 1<<leaderBoardTieBits - 1
+
+	// the member is only updated when the leader board is cached,
+	// a missing leader board is rebuilt from database on the next read
+	updateLeaderBoardScript = `
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	redis.call('ZADD', KEYS[1], ARGV[1], ARGV[2])
+else
+	redis.call('DEL', KEYS[2])
+end
+return ''`
+
+	// applyLeaderBoardChangesScript applies the changes in their order, ARGV[1] is the multiplier of the score
+	// then each change is (member, delta, score, encoded score). A change is stale when the member already has
+	// its score, e.g. it was written by the request, and it follows the member when its delta leads to the score.
+	// It returns GAP when a change doesn't follow, then the leader board is rebuilt from database.
+	applyLeaderBoardChangesScript = `
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	redis.call('DEL', KEYS[2])
+	return 'OK'
+end
+local tie = tonumber(ARGV[1])
+for i = 2, #ARGV, 4 do
+	local member = ARGV[i]
+	local delta = tonumber(ARGV[i + 1])
+	local score = tonumber(ARGV[i + 2])
+	local current = redis.call('ZSCORE', KEYS[1], member)
+	if current then
+		local cur = math.floor(tonumber(current) / tie)
+		if cur < score then
+			if cur + delta ~= score then
+				return 'GAP'
+			end
+			redis.call('ZADD', KEYS[1], ARGV[i + 3], member)
+		end
+	elseif score == delta then
+		redis.call('ZADD', KEYS[1], ARGV[i + 3], member)
+	else
+		return 'GAP'
+	end
+end
+return 'OK'`
+	leaderBoardChangesGap = "GAP"
+)
+
+type LeaderBoardEntry struct {
+	UserID int64
+	Score  int32
+	Rank   int64
+}
+
+// LeaderBoardChange is a change of the score of a participant by delta, to score at updated time
+type LeaderBoardChange struct {
+	UserID      int64
+	Delta       int32
+	Score       int32
+	UpdatedTime int64
+}
+
+// LeaderBoard keeps a redis sorted set per quiz, ordered by score then the earliest update
+// in seconds since the start of the quiz
+type LeaderBoard interface {
+	GetPage(ctx context.Context, quizID int64, pageIndex int32, pageSize int32) (error, []*LeaderBoardEntry, int64)
+	GetRank(ctx context.Context, quizID int64, userID int64) (error, *LeaderBoardEntry)
+	GetRanks(ctx context.Context, quizID int64, userIDs []int64) (error, []*LeaderBoardEntry)
+	UpdateScore(ctx context.Context, quiz *model.QuizTab, participant *model.QuizParticipantTab) error
+	// ApplyChanges returns false when a change doesn't follow the cached score of its participant
+	ApplyChanges(ctx context.Context, quiz *model.QuizTab, changes []*LeaderBoardChange) (error, bool)
+	Rebuild(ctx context.Context, quizID int64) error
+}
+
+func NewLeaderBoard(dep *Dependency) LeaderBoard {
+	return &LeaderBoardImpl{dep: dep}
+}
+
+type LeaderBoardImpl struct {
+	dep *Dependency
+
+	// rebuilds are the rebuilds for the reads in flight per quiz, the concurrent reads wait for the same one
+	mutex    sync.Mutex
+	rebuilds map[int64]*leaderBoardRebuild
+}
+
+type leaderBoardRebuild struct {
+	done chan struct{}
+	err  error
+}
+
+func leaderBoardKey(quizID int64) string {
+	return fmt.Sprintf(leaderBoardKeyFormat, quizID)
+}
+
+func leaderBoardEmptyKey(quizID int64) string {
+	return fmt.Sprintf(leaderBoardEmptyKeyFormat, quizID)
+}
+
+// leaderBoardEpoch is the start of the tie-break of the quiz, its creation when it has no start_time
+func leaderBoardEpoch(quiz *model.QuizTab) int64 {
+	if quiz.StartTime > 0 {
+		return quiz.StartTime
+	}
+	return quiz.CreatedTime
+}
+
+// encodeLeaderBoardScore packs the score above leaderBoardTieBits and the seconds left from the update
+// to leaderBoardMaxTie below, so that a higher score ranks first and an earlier update wins a tie
+func encodeLeaderBoardScore(score int32, updatedTime int64, epoch int64) float64 {
+	elapsed := (updatedTime - epoch) / int64(time.Second/time.Millisecond)
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	if elapsed > leaderBoardMaxTie {
+		elapsed = leaderBoardMaxTie
+	}
+	return float64(int64(score)<<leaderBoardTieBits | (leaderBoardMaxTie - elapsed))
+}
+
+func decodeLeaderBoardScore(value float64) int32 {
+	return int32(int64(value) >> leaderBoardTieBits)
+}
+
+func (l *LeaderBoardImpl) GetPage(
+	ctx context.Context, quizID int64, pageIndex int32, pageSize int32,
+) (error, []*LeaderBoardEntry, int64) {
+	key := leaderBoardKey(quizID)
+	err := l.ensureCached(ctx, quizID)
+	if err != nil {
+		return err, nil, 0
+	}
+
+	start := int64(pageIndex) * int64(pageSize)
+	members, err := l.dep.Cache.ZRevRangeWithScores(key, start, start+int64(pageSize)-1)
+	if err != nil {
+		return err, nil, 0
+	}
+	total, err := l.dep.Cache.ZCard(key)
+	if err != nil {
+		return err, nil, 0
+	}
+
+	entries := make([]*LeaderBoardEntry, 0, len(members))
+	for i, member := range members {
+		userID, err := strconv.ParseInt(member.Member.(string), 10, 64)
+		if err != nil {
+			return err, nil, 0
+		}
+		entries = append(entries, &LeaderBoardEntry{
+			UserID: userID,
+			Score:  decodeLeaderBoardScore(member.Score),
+			Rank:   start + int64(i) + 1,
+		})
+	}
+
+	return nil, entries, total
+}
+
+// GetRank returns nil if the user is not on the leader board
+func (l *LeaderBoardImpl) GetRank(ctx context.Context, quizID int64, userID int64) (error, *LeaderBoardEntry) {
+	key := leaderBoardKey(quizID)
+	err := l.ensureCached(ctx, quizID)
+	if err != nil {
+		return err, nil
+	}
+
+	member := strconv.FormatInt(userID, 10)
+	rank, err := l.dep.Cache.ZRevRank(key, member)
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return err, nil
+	}
+	score, err := l.dep.Cache.ZScore(key, member)
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return err, nil
+	}
+
+	return nil, &LeaderBoardEntry{
+		UserID: userID,
+		Score:  decodeLeaderBoardScore(score),
+		Rank:   rank + 1,
+	}
+}
+
+// GetRanks reads the ranks of many users in one round trip, the users not on the leader board are skipped
+func (l *LeaderBoardImpl) GetRanks(ctx context.Context, quizID int64, userIDs []int64) (error, []*LeaderBoardEntry) {
+	key := leaderBoardKey(quizID)
+	err := l.ensureCached(ctx, quizID)
+	if err != nil {
+		return err, nil
+	}
+
+	pipeline := l.dep.Cache.Client().Pipeline()
+	ranks := make([]*redis.IntCmd, len(userIDs))
+	scores := make([]*redis.FloatCmd, len(userIDs))
+	for i, userID := range userIDs {
+		member := strconv.FormatInt(userID, 10)
+		ranks[i] = pipeline.ZRevRank(key, member)
+		scores[i] = pipeline.ZScore(key, member)
+	}
+	_, err = pipeline.Exec()
+	if err != nil && err != redis.Nil {
+		return err, nil
+	}
+
+	entries := make([]*LeaderBoardEntry, 0, len(userIDs))
+	for i, userID := range userIDs {
+		rank, rankErr := ranks[i].Result()
+		score, scoreErr := scores[i].Result()
+		if rankErr == redis.Nil || scoreErr == redis.Nil {
+			continue
+		}
+		if rankErr != nil {
+			return rankErr, nil
+		}
+		if scoreErr != nil {
+			return scoreErr, nil
+		}
+		entries = append(entries, &LeaderBoardEntry{
+			UserID: userID,
+			Score:  decodeLeaderBoardScore(score),
+			Rank:   rank + 1,
+		})
+	}
+	return nil, entries
+}
+
+func (l *LeaderBoardImpl) UpdateScore(ctx context.Context, quiz *model.QuizTab, participant *model.QuizParticipantTab) error {
+	score := encodeLeaderBoardScore(participant.Score, participant.UpdatedTime, leaderBoardEpoch(quiz))
+	_, err := l.dep.Cache.Eval(
+		updateLeaderBoardScript,
+		[]string{leaderBoardKey(participant.QuizID), leaderBoardEmptyKey(participant.QuizID)},
+		[]string{strconv.FormatFloat(score, 'f', -1, 64), strconv.FormatInt(participant.UserID, 10)},
+	)
+	return err
+}
+
+// ApplyChanges applies the changes of the quiz atomically, a missing leader board is left to the next read
+// and the empty marker is removed
+func (l *LeaderBoardImpl) ApplyChanges(
+	ctx context.Context, quiz *model.QuizTab, changes []*LeaderBoardChange,
+) (error, bool) {
+	if len(changes) == 0 {
+		return nil, true
+	}
+	args := make([]string, 0, 1+4*len(changes))
+	args = append(args, strconv.FormatInt(1<<leaderBoardTieBits, 10))
+	for _, change := range changes {
+		score := encodeLeaderBoardScore(change.Score, change.UpdatedTime, leaderBoardEpoch(quiz))
+		args = append(args,
+			strconv.FormatInt(change.UserID, 10),
+			strconv.FormatInt(int64(change.Delta), 10),
+			strconv.FormatInt(int64(change.Score), 10),
+			strconv.FormatFloat(score, 'f', -1, 64),
+		)
+	}
+	keys := []string{leaderBoardKey(quiz.ID), leaderBoardEmptyKey(quiz.ID)}
+	result, err := l.dep.Cache.Eval(applyLeaderBoardChangesScript, keys, args)
+	if err != nil {
+		return err, false
+	}
+	return nil, result != leaderBoardChangesGap
+}
+
+// Rebuild loads all participants of the quiz from database, and swaps them into the cache atomically.
+// A quiz without participants gets the empty marker instead.
+func (l *LeaderBoardImpl) Rebuild(ctx context.Context, quizID int64) error {
+	err, quiz := l.dep.QuizDAO.FindQuizByIDFromMaster(ctx, quizID)
+	if err != nil {
+		return err
+	}
+	var participants []*model.QuizParticipantTab
+	if quiz != nil {
+		err, participants = l.dep.QuizDAO.ListQuizParticipants(ctx, quizID)
+		if err != nil {
+			return err
+		}
+	}
+
+	key := leaderBoardKey(quizID)
+	tempKey := fmt.Sprintf(leaderBoardTempKeyFormat, quizID)
+	pipeline := l.dep.Cache.Client().TxPipeline()
+	pipeline.Del(tempKey)
+	for start := 0; start < len(participants); start += leaderBoardBatchSize {
+		end := start + leaderBoardBatchSize
+		if end > len(participants) {
+			end = len(participants)
+		}
+		members := make([]redis.Z, 0, end-start)
+		for _, participant := range participants[start:end] {
+			members = append(members, redis.Z{
+				Score:  encodeLeaderBoardScore(participant.Score, participant.UpdatedTime, leaderBoardEpoch(quiz)),
+				Member: strconv.FormatInt(participant.UserID, 10),
+			})
+		}
+		pipeline.ZAdd(tempKey, members...)
+	}
+	if len(participants) > 0 {
+		pipeline.Expire(tempKey, leaderBoardExpire)
+		pipeline.Rename(tempKey, key)
+		pipeline.Del(leaderBoardEmptyKey(quizID))
+	} else {
+		pipeline.Del(key)
+		pipeline.Set(leaderBoardEmptyKey(quizID), "1", leaderBoardEmptyExpire)
+	}
+	_, err = pipeline.Exec()
+	return err
+}
+
+// isCached returns true when the leader board or the empty marker of the quiz is cached
+func (l *LeaderBoardImpl) isCached(quizID int64) (error, bool) {
+	exists, err := l.dep.Cache.Client().Exists(leaderBoardKey(quizID), leaderBoardEmptyKey(quizID)).Result()
+	if err != nil {
+		return err, false
+	}
+	return nil, exists > 0
+}
+
+// ensureCached rebuilds a missing leader board once per server at a time, and once for all servers
+// while the rebuild takes less than leaderBoardRebuildWait
+func (l *LeaderBoardImpl) ensureCached(ctx context.Context, quizID int64) error {
+	err, cached := l.isCached(quizID)
+	if err != nil || cached {
+		return err
+	}
+
+	l.mutex.Lock()
+	if l.rebuilds == nil {
+		l.rebuilds = map[int64]*leaderBoardRebuild{}
+	}
+	rebuild, ok := l.rebuilds[quizID]
+	if ok {
+		l.mutex.Unlock()
+		<-rebuild.done
+		return rebuild.err
+	}
+	rebuild = &leaderBoardRebuild{done: make(chan struct{})}
+	l.rebuilds[quizID] = rebuild
+	l.mutex.Unlock()
+
+	rebuild.err = l.rebuildLocked(ctx, quizID)
+	l.mutex.Lock()
+	delete(l.rebuilds, quizID)
+	l.mutex.Unlock()
+	close(rebuild.done)
+	return rebuild.err
+}
+
+// rebuildLocked rebuilds the leader board with the lock of the quiz, or waits for the server holding it
+func (l *LeaderBoardImpl) rebuildLocked(ctx context.Context, quizID int64) error {
+	lock := fmt.Sprintf(leaderBoardLockKeyFormat, quizID)
+	acquired, err := l.dep.Cache.SetNX(lock, "1", leaderBoardLockExpire)
+	if err != nil {
+		return err
+	}
+	if acquired {
+		defer l.dep.Cache.Del(lock)
+		return l.Rebuild(ctx, quizID)
+	}
+
+	for deadline := time.Now().Add(leaderBoardRebuildWait); time.Now().Before(deadline); {
+		time.Sleep(leaderBoardRebuildPolling)
+		err, cached := l.isCached(quizID)
+		if err != nil || cached {
+			return err
+		}
+	}
+	return l.Rebuild(ctx, quizID)
+}
@@ -0,0 +1,47 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/luulethe/quiz/go_common/log"
+)
+
+const wsNodeChannelFormat = "quiz_ws_node_channel_%s"
+
+// WSNodeChannel is the redis pub/sub channel a gateway node subscribes to,
+// a message is the id of a quiz whose leader board has changed
+func WSNodeChannel(nodeID string) string {
+	return fmt.Sprintf(wsNodeChannelFormat, nodeID)
+}
+
+// LeaderBoardNotifier tells the gateway nodes holding the clients of a quiz that its leader board has changed
+type LeaderBoardNotifier interface {
+	Notify(ctx context.Context, quizID int64) error
+}
+
+func NewLeaderBoardNotifier(dep *Dependency) LeaderBoardNotifier {
+	return &LeaderBoardNotifierImpl{dep: dep}
+}
+
+type LeaderBoardNotifierImpl struct {
+	dep *Dependency
+}
+
+func (n *LeaderBoardNotifierImpl) Notify(ctx context.Context, quizID int64) error {
+	err, nodes := n.dep.Connections.GetQuizNodes(ctx, quizID)
+	if err != nil {
+		return err
+	}
+
+	message := strconv.FormatInt(quizID, 10)
+	for _, nodeID := range nodes {
+		err := n.dep.Cache.Client().Publish(WSNodeChannel(nodeID), message).Err()
+		if err != nil {
+			log.Errorff(ctx, "LeaderBoardNotifier.Notify|quiz_id:%v|node_id:%v|err:%v", quizID, nodeID, err)
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis"
+	"github.com/luulethe/quiz/go_common/cache"
+	"github.com/luulethe/quiz/quiz_lib/db/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testQuizStart = int64(1617235200000)
+
+func newTestLeaderBoard(t *testing.T) (*LeaderBoardImpl, *redis.Client) {
+	server := miniredis.RunT(t)
+	redisCache, err := cache.NewRedisClient(server.Addr(), &cache.RedisOption{})
+	require.NoError(t, err)
+	t.Cleanup(func() { redisCache.Close() })
+	return &LeaderBoardImpl{dep: &Dependency{Cache: redisCache}}, redisCache.Client()
+}
+
+func testLeaderBoardScore(t *testing.T, client *redis.Client, quizID int64, userID string) int32 {
+	score, err := client.ZScore(leaderBoardKey(quizID), userID).Result()
+	require.NoError(t, err)
+	return decodeLeaderBoardScore(score)
+}
+
+func TestLeaderBoardScore_TieBreak(t *testing.T) {
+	early := encodeLeaderBoardScore(10, testQuizStart+1000, testQuizStart)
+	late := encodeLeaderBoardScore(10, testQuizStart+5000, testQuizStart)
+	lower := encodeLeaderBoardScore(9, testQuizStart, testQuizStart)
+
+	assert.Greater(t, early, late)
+	assert.Greater(t, late, lower)
+	assert.Equal(t, int32(10), decodeLeaderBoardScore(early))
+	assert.Equal(t, int32(10), decodeLeaderBoardScore(late))
+	assert.Equal(t, int32(1<<31-1), decodeLeaderBoardScore(encodeLeaderBoardScore(1<<31-1, testQuizStart, testQuizStart)))
+}
+
+func TestLeaderBoardApplyChanges(t *testing.T) {
+	ctx := context.Background()
+	leaderBoard, client := newTestLeaderBoard(t)
+	quiz := &model.QuizTab{ID: 7, StartTime: testQuizStart}
+
+	// a missing leader board is rebuilt on the next read
+	err, applied := leaderBoard.ApplyChanges(ctx, quiz, []*LeaderBoardChange{{UserID: 1, Delta: 0, Score: 0}})
+	require.NoError(t, err)
+	assert.True(t, applied)
+	assert.Equal(t, int64(0), client.Exists(leaderBoardKey(quiz.ID)).Val())
+
+	client.ZAdd(leaderBoardKey(quiz.ID), redis.Z{Score: encodeLeaderBoardScore(5, testQuizStart, testQuizStart), Member: "1"})
+	err, applied = leaderBoard.ApplyChanges(ctx, quiz, []*LeaderBoardChange{
+		{UserID: 1, Delta: 5, Score: 5, UpdatedTime: testQuizStart}, // already written by the request
+		{UserID: 1, Delta: 3, Score: 8, UpdatedTime: testQuizStart + 1000},
+		{UserID: 2, Delta: 0, Score: 0, UpdatedTime: testQuizStart + 1000},
+		{UserID: 2, Delta: 4, Score: 4, UpdatedTime: testQuizStart + 2000},
+	})
+	require.NoError(t, err)
+	assert.True(t, applied)
+	assert.Equal(t, int32(8), testLeaderBoardScore(t, client, quiz.ID, "1"))
+	assert.Equal(t, int32(4), testLeaderBoardScore(t, client, quiz.ID, "2"))
+
+	// a replayed change is stale
+	err, applied = leaderBoard.ApplyChanges(ctx, quiz, []*LeaderBoardChange{{UserID: 1, Delta: 3, Score: 8}})
+	require.NoError(t, err)
+	assert.True(t, applied)
+	assert.Equal(t, int32(8), testLeaderBoardScore(t, client, quiz.ID, "1"))
+}
+
+func TestLeaderBoardApplyChanges_Gap(t *testing.T) {
+	ctx := context.Background()
+	leaderBoard, client := newTestLeaderBoard(t)
+	quiz := &model.QuizTab{ID: 7, StartTime: testQuizStart}
+	client.ZAdd(leaderBoardKey(quiz.ID), redis.Z{Score: encodeLeaderBoardScore(5, testQuizStart, testQuizStart), Member: "1"})
+
+	// the change to 8 is missing
+	err, applied := leaderBoard.ApplyChanges(ctx, quiz, []*LeaderBoardChange{{UserID: 1, Delta: 2, Score: 10}})
+	require.NoError(t, err)
+	assert.False(t, applied)
+	assert.Equal(t, int32(5), testLeaderBoardScore(t, client, quiz.ID, "1"))
+
+	// the join of the participant is missing
+	err, applied = leaderBoard.ApplyChanges(ctx, quiz, []*LeaderBoardChange{{UserID: 2, Delta: 2, Score: 6}})
+	require.NoError(t, err)
+	assert.False(t, applied)
+}
+
+// rebuildDAO counts the reads of a leader board rebuild, the other methods aren't used
+type rebuildDAO struct {
+	QuizDAO
+	quiz         *model.QuizTab
+	participants []*model.QuizParticipantTab
+	delay        time.Duration
+	finds        int32
+}
+
+func (d *rebuildDAO) FindQuizByIDFromMaster(ctx context.Context, quizID int64) (error, *model.QuizTab) {
+	atomic.AddInt32(&d.finds, 1)
+	time.Sleep(d.delay)
+	if d.quiz == nil || d.quiz.ID != quizID {
+		return nil, nil
+	}
+	return nil, d.quiz
+}
+
+func (d *rebuildDAO) ListQuizParticipants(ctx context.Context, quizID int64) (error, []*model.QuizParticipantTab) {
+	return nil, d.participants
+}
+
+func TestLeaderBoardRebuild_EmptyMarker(t *testing.T) {
+	ctx := context.Background()
+	leaderBoard, client := newTestLeaderBoard(t)
+	quiz := &model.QuizTab{ID: 7, StartTime: testQuizStart}
+	dao := &rebuildDAO{quiz: quiz}
+	leaderBoard.dep.QuizDAO = dao
+
+	// an unknown quiz and a quiz without participants are read from database once
+	for _, quizID := range []int64{quiz.ID, 404} {
+		for i := 0; i < 3; i++ {
+			err, entries, total := leaderBoard.GetPage(ctx, quizID, 0, 10)
+			require.NoError(t, err)
+			assert.Empty(t, entries)
+			assert.Zero(t, total)
+		}
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&dao.finds))
+
+	// a score written removes the marker, the next read rebuilds the leader board
+	participant := &model.QuizParticipantTab{QuizID: quiz.ID, UserID: 1, Score: 5, UpdatedTime: testQuizStart}
+	dao.participants = []*model.QuizParticipantTab{participant}
+	require.NoError(t, leaderBoard.UpdateScore(ctx, quiz, participant))
+	assert.Equal(t, int64(0), client.Exists(leaderBoardEmptyKey(quiz.ID)).Val())
+	err, entry := leaderBoard.GetRank(ctx, quiz.ID, 1)
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	assert.Equal(t, int32(5), entry.Score)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&dao.finds))
+}
+
+func TestLeaderBoardRebuild_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	leaderBoard, _ := newTestLeaderBoard(t)
+	quiz := &model.QuizTab{ID: 7, StartTime: testQuizStart}
+	dao := &rebuildDAO{quiz: quiz, delay: 50 * time.Millisecond, participants: []*model.QuizParticipantTab{
+		{QuizID: quiz.ID, UserID: 1, Score: 5, UpdatedTime: testQuizStart},
+	}}
+	leaderBoard.dep.QuizDAO = dao
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err, entries, _ := leaderBoard.GetPage(ctx, quiz.ID, 0, 10)
+			assert.NoError(t, err)
+			assert.Len(t, entries, 1)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&dao.finds))
+}
+
+func TestLeaderBoardRebuild_WaitsForLockHolder(t *testing.T) {
+	ctx := context.Background()
+	leaderBoard, client := newTestLeaderBoard(t)
+	quiz := &model.QuizTab{ID: 7, StartTime: testQuizStart}
+	dao := &rebuildDAO{quiz: quiz}
+	leaderBoard.dep.QuizDAO = dao
+
+	// another server is rebuilding the leader board
+	client.Set(fmt.Sprintf(leaderBoardLockKeyFormat, quiz.ID), "1", time.Minute)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		client.ZAdd(leaderBoardKey(quiz.ID), redis.Z{Score: encodeLeaderBoardScore(5, testQuizStart, testQuizStart), Member: "1"})
+	}()
+	err, entries, total := leaderBoard.GetPage(ctx, quiz.ID, 0, 10)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, int64(1), total)
+	assert.Zero(t, atomic.LoadInt32(&dao.finds))
+}
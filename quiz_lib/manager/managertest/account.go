@@ -0,0 +1,50 @@
+package managertest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/luulethe/quiz/quiz_lib/manager"
+)
+
+// AccountClient is a manager.AccountClient knowing the users it was created with, every call fails while an error is set
+type AccountClient struct {
+	mu    sync.Mutex
+	users map[int64]bool
+	err   error
+	calls int
+}
+
+var _ manager.AccountClient = (*AccountClient)(nil)
+
+func NewAccountClient(userIDs ...int64) *AccountClient {
+	c := &AccountClient{users: make(map[int64]bool, len(userIDs))}
+	for _, userID := range userIDs {
+		c.users[userID] = true
+	}
+	return c
+}
+
+func (c *AccountClient) UserExisted(ctx context.Context, userID int64) (error, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	if c.err != nil {
+		return c.err, false
+	}
+	return nil, c.users[userID]
+}
+
+// SetErr makes the following calls fail with err, nil makes them succeed again
+func (c *AccountClient) SetErr(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.err = err
+}
+
+// Calls returns the number of calls received
+func (c *AccountClient) Calls() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls
+}
@@ -0,0 +1,461 @@
+// Package managertest provides in-memory implementations of the manager dependencies for tests. They keep the
+// guarantees the managers rely on, e.g. a participant joins a quiz once and its outbox messages are written with it.
+package managertest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/luulethe/quiz/quiz_lib/db/model"
+	"github.com/luulethe/quiz/quiz_lib/manager"
+	"gorm.io/gorm/schema"
+)
+
+type participantKey struct {
+	quizID int64
+	userID int64
+}
+
+type answerKey struct {
+	participantKey
+	questionID int64
+}
+
+// QuizDAO is a manager.QuizDAO in memory, every method runs as one transaction of a single database
+type QuizDAO struct {
+	mu           sync.Mutex
+	lastID       int64
+	quizzes      map[int64]*model.QuizTab
+	participants map[participantKey]*model.QuizParticipantTab
+	questions    map[int64]*model.QuizQuestionTab
+	options      map[int64]*model.QuizAnswerOptionTab
+	answers      map[answerKey]*model.QuizUserAnswerTab
+	outbox       []*model.QuizOutboxTab
+}
+
+var _ manager.QuizDAO = (*QuizDAO)(nil)
+
+func NewQuizDAO() *QuizDAO {
+	return &QuizDAO{
+		quizzes:      map[int64]*model.QuizTab{},
+		participants: map[participantKey]*model.QuizParticipantTab{},
+		questions:    map[int64]*model.QuizQuestionTab{},
+		options:      map[int64]*model.QuizAnswerOptionTab{},
+		answers:      map[answerKey]*model.QuizUserAnswerTab{},
+	}
+}
+
+func (d *QuizDAO) nextID() int64 {
+	d.lastID++
+	return d.lastID
+}
+
+// AddQuestion stores the question with its options whatever the status of the quiz, the options are given their ids
+func (d *QuizDAO) AddQuestion(question *model.QuizQuestionTab, options ...*model.QuizAnswerOptionTab) *model.QuizQuestionTab {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.addQuestion(question, options)
+	return question
+}
+
+func (d *QuizDAO) addQuestion(question *model.QuizQuestionTab, options []*model.QuizAnswerOptionTab) {
+	question.ID = d.nextID()
+	stored := *question
+	d.questions[question.ID] = &stored
+	for _, option := range options {
+		option.ID = d.nextID()
+		option.QuestionID = question.ID
+		stored := *option
+		d.options[option.ID] = &stored
+	}
+}
+
+// Participants returns the participants of the quiz by user id
+func (d *QuizDAO) Participants(quizID int64) []*model.QuizParticipantTab {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var participants []*model.QuizParticipantTab
+	for key, participant := range d.participants {
+		if key.quizID == quizID {
+			stored := *participant
+			participants = append(participants, &stored)
+		}
+	}
+	sort.Slice(participants, func(i, j int) bool { return participants[i].UserID < participants[j].UserID })
+	return participants
+}
+
+// OutboxMessages returns every outbox message in insertion order
+func (d *QuizDAO) OutboxMessages() []*model.QuizOutboxTab {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	messages := make([]*model.QuizOutboxTab, 0, len(d.outbox))
+	for _, message := range d.outbox {
+		stored := *message
+		messages = append(messages, &stored)
+	}
+	return messages
+}
+
+func (d *QuizDAO) FindQuizByID(ctx context.Context, quizID int64) (error, *model.QuizTab) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	quiz, ok := d.quizzes[quizID]
+	if !ok {
+		return nil, nil
+	}
+	stored := *quiz
+	return nil, &stored
+}
+
+func (d *QuizDAO) FindQuizByIDFromMaster(ctx context.Context, quizID int64) (error, *model.QuizTab) {
+	return d.FindQuizByID(ctx, quizID)
+}
+
+func (d *QuizDAO) ListQuizzesToStart(
+	ctx context.Context, status int32, startBefore int64, limit int,
+) (error, []*model.QuizTab) {
+	return nil, d.listQuizzes(status, limit, func(quiz *model.QuizTab) int64 { return quiz.StartTime }, startBefore)
+}
+
+func (d *QuizDAO) ListQuizzesToEnd(
+	ctx context.Context, status int32, endBefore int64, limit int,
+) (error, []*model.QuizTab) {
+	return nil, d.listQuizzes(status, limit, func(quiz *model.QuizTab) int64 { return quiz.EndTime }, endBefore)
+}
+
+func (d *QuizDAO) listQuizzes(status int32, limit int, timeOf func(*model.QuizTab) int64, before int64) []*model.QuizTab {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var quizzes []*model.QuizTab
+	for _, quiz := range d.quizzes {
+		if quiz.Status == status && timeOf(quiz) > 0 && timeOf(quiz) <= before {
+			stored := *quiz
+			quizzes = append(quizzes, &stored)
+		}
+	}
+	sort.Slice(quizzes, func(i, j int) bool { return timeOf(quizzes[i]) < timeOf(quizzes[j]) })
+	if len(quizzes) > limit {
+		quizzes = quizzes[:limit]
+	}
+	return quizzes
+}
+
+func (d *QuizDAO) CreateQuiz(ctx context.Context, quiz *model.QuizTab) (error, *model.QuizTab) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := time.Now().UnixMilli()
+	quiz.ID = d.nextID()
+	quiz.CreatedTime = now
+	quiz.UpdatedTime = now
+	stored := *quiz
+	d.quizzes[quiz.ID] = &stored
+	return nil, quiz
+}
+
+func (d *QuizDAO) UpdateQuiz(
+	ctx context.Context, quizID int64, status int32, updates map[string]interface{}, messages ...*model.QuizOutboxTab,
+) (error, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	quiz, ok := d.quizzes[quizID]
+	if !ok || quiz.Status != status {
+		return nil, false
+	}
+	updated := *quiz
+	if err := setColumns(&updated, updates); err != nil {
+		return err, false
+	}
+	d.quizzes[quizID] = &updated
+	d.addOutboxMessages(messages)
+	return nil, true
+}
+
+var schemaCache = &sync.Map{}
+
+// setColumns sets the fields of the model by their column names, as gorm does for an update
+func setColumns(row interface{}, updates map[string]interface{}) error {
+	rowSchema, err := schema.Parse(row, schemaCache, schema.NamingStrategy{})
+	if err != nil {
+		return err
+	}
+	value := reflect.ValueOf(row)
+	for column, update := range updates {
+		field := rowSchema.LookUpField(column)
+		if field == nil {
+			return fmt.Errorf("unknown column %s of %s", column, rowSchema.Table)
+		}
+		if err := field.Set(value, update); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *QuizDAO) DeleteQuiz(ctx context.Context, quizID int64, status int32) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	quiz, ok := d.quizzes[quizID]
+	if !ok || quiz.Status != status {
+		return manager.ErrQuizStatusChanged
+	}
+	for id, question := range d.questions {
+		if question.QuizID != quizID {
+			continue
+		}
+		for optionID, option := range d.options {
+			if option.QuestionID == id {
+				delete(d.options, optionID)
+			}
+		}
+		delete(d.questions, id)
+	}
+	delete(d.quizzes, quizID)
+	return nil
+}
+
+func (d *QuizDAO) CreateQuizParticipant(
+	ctx context.Context, quizID int64, userID int64, messages manager.ParticipantMessages,
+) (error, *model.QuizParticipantTab) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	key := participantKey{quizID: quizID, userID: userID}
+	if _, ok := d.participants[key]; ok {
+		return manager.ErrParticipantJoined, nil
+	}
+	now := time.Now().UnixMilli()
+	participant := &model.QuizParticipantTab{
+		ID:          d.nextID(),
+		QuizID:      quizID,
+		UserID:      userID,
+		CreatedTime: now,
+		UpdatedTime: now,
+	}
+	if err := d.addParticipantMessages(participant, messages); err != nil {
+		return err, nil
+	}
+	stored := *participant
+	d.participants[key] = &stored
+	return nil, participant
+}
+
+func (d *QuizDAO) FindQuizParticipant(ctx context.Context, quizID int64, userID int64) (error, *model.QuizParticipantTab) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	participant, ok := d.participants[participantKey{quizID: quizID, userID: userID}]
+	if !ok {
+		return nil, nil
+	}
+	stored := *participant
+	return nil, &stored
+}
+
+func (d *QuizDAO) ListQuizParticipants(ctx context.Context, quizID int64) (error, []*model.QuizParticipantTab) {
+	return nil, d.Participants(quizID)
+}
+
+func (d *QuizDAO) FindQuestionByID(ctx context.Context, questionID int64) (error, *model.QuizQuestionTab) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	question, ok := d.questions[questionID]
+	if !ok {
+		return nil, nil
+	}
+	stored := *question
+	return nil, &stored
+}
+
+func (d *QuizDAO) ListQuizQuestions(ctx context.Context, quizID int64) (error, []*model.QuizQuestionTab) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var questions []*model.QuizQuestionTab
+	for _, question := range d.questions {
+		if question.QuizID == quizID {
+			stored := *question
+			questions = append(questions, &stored)
+		}
+	}
+	sort.Slice(questions, func(i, j int) bool {
+		if questions[i].Seq != questions[j].Seq {
+			return questions[i].Seq < questions[j].Seq
+		}
+		return questions[i].ID < questions[j].ID
+	})
+	return nil, questions
+}
+
+func (d *QuizDAO) ListQuizQuestionsFromMaster(ctx context.Context, quizID int64) (error, []*model.QuizQuestionTab) {
+	return d.ListQuizQuestions(ctx, quizID)
+}
+
+func (d *QuizDAO) CreateQuestion(
+	ctx context.Context, status int32, question *model.QuizQuestionTab, options []*model.QuizAnswerOptionTab,
+) (error, *model.QuizQuestionTab) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	quiz, ok := d.quizzes[question.QuizID]
+	if !ok || quiz.Status != status {
+		return manager.ErrQuizStatusChanged, nil
+	}
+	maxSeq := int32(0)
+	for _, existing := range d.questions {
+		if existing.QuizID == question.QuizID && existing.Seq > maxSeq {
+			maxSeq = existing.Seq
+		}
+	}
+	now := time.Now().UnixMilli()
+	question.Seq = maxSeq + 1
+	question.CreatedTime = now
+	question.UpdatedTime = now
+	for _, option := range options {
+		option.CreatedTime = now
+	}
+	d.addQuestion(question, options)
+	return nil, question
+}
+
+func (d *QuizDAO) ReorderQuestions(ctx context.Context, quizID int64, status int32, questionIDs []int64) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	quiz, ok := d.quizzes[quizID]
+	if !ok || quiz.Status != status {
+		return manager.ErrQuizStatusChanged
+	}
+	existing := map[int64]bool{}
+	for id, question := range d.questions {
+		if question.QuizID == quizID {
+			existing[id] = true
+		}
+	}
+	if len(questionIDs) != len(existing) {
+		return manager.ErrQuestionsMismatch
+	}
+	for _, questionID := range questionIDs {
+		if !existing[questionID] {
+			return manager.ErrQuestionsMismatch
+		}
+		delete(existing, questionID)
+	}
+	now := time.Now().UnixMilli()
+	for i, questionID := range questionIDs {
+		d.questions[questionID].Seq = int32(i + 1)
+		d.questions[questionID].UpdatedTime = now
+	}
+	return nil
+}
+
+func (d *QuizDAO) FindAnswerOptionByID(ctx context.Context, answerID int64) (error, *model.QuizAnswerOptionTab) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	option, ok := d.options[answerID]
+	if !ok {
+		return nil, nil
+	}
+	stored := *option
+	return nil, &stored
+}
+
+func (d *QuizDAO) SubmitAnswer(
+	ctx context.Context, answer *model.QuizUserAnswerTab, messages manager.ParticipantMessages,
+) (error, *model.QuizParticipantTab) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	key := participantKey{quizID: answer.QuizID, userID: answer.UserID}
+	stored, ok := d.participants[key]
+	if !ok {
+		return manager.ErrParticipantNotFound, nil
+	}
+	answered := answerKey{participantKey: key, questionID: answer.QuestionID}
+	if _, ok := d.answers[answered]; ok {
+		return manager.ErrAnswerSubmitted, nil
+	}
+
+	now := time.Now().UnixMilli()
+	participant := *stored
+	participant.Score += answer.Score
+	participant.UpdatedTime = now
+	if err := d.addParticipantMessages(&participant, messages); err != nil {
+		return err, nil
+	}
+	answer.ID = d.nextID()
+	answer.CreatedTime = now
+	storedAnswer := *answer
+	d.answers[answered] = &storedAnswer
+	updated := participant
+	d.participants[key] = &updated
+	return nil, &participant
+}
+
+func (d *QuizDAO) addParticipantMessages(participant *model.QuizParticipantTab, messages manager.ParticipantMessages) error {
+	if messages == nil {
+		return nil
+	}
+	outboxMessages, err := messages(participant)
+	if err != nil {
+		return err
+	}
+	d.addOutboxMessages(outboxMessages)
+	return nil
+}
+
+func (d *QuizDAO) addOutboxMessages(messages []*model.QuizOutboxTab) {
+	now := time.Now().UnixMilli()
+	for _, message := range messages {
+		message.ID = d.nextID()
+		message.Status = model.OutboxStatusPending
+		message.CreatedTime = now
+		stored := *message
+		d.outbox = append(d.outbox, &stored)
+	}
+}
+
+// ListPendingOutboxMessages ignores database, the messages of every database are kept together
+func (d *QuizDAO) ListPendingOutboxMessages(ctx context.Context, database int, limit int) (error, []*model.QuizOutboxTab) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var messages []*model.QuizOutboxTab
+	for _, message := range d.outbox {
+		if message.Status == model.OutboxStatusPending && len(messages) < limit {
+			stored := *message
+			messages = append(messages, &stored)
+		}
+	}
+	return nil, messages
+}
+
+func (d *QuizDAO) MarkOutboxMessagesSent(ctx context.Context, database int, ids []int64) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	sent := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		sent[id] = true
+	}
+	now := time.Now().UnixMilli()
+	for _, message := range d.outbox {
+		if sent[message.ID] {
+			message.Status = model.OutboxStatusSent
+			message.SentTime = now
+		}
+	}
+	return nil
+}
+
+func (d *QuizDAO) DeleteSentOutboxMessages(ctx context.Context, database int, sentBefore int64, limit int) (error, int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	kept := d.outbox[:0]
+	deleted := int64(0)
+	for _, message := range d.outbox {
+		if message.Status == model.OutboxStatusSent && message.SentTime < sentBefore && deleted < int64(limit) {
+			deleted++
+			continue
+		}
+		kept = append(kept, message)
+	}
+	d.outbox = kept
+	return nil, deleted
+}
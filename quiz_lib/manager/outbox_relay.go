@@ -0,0 +1,222 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/luulethe/quiz/go_common/log"
+	"github.com/luulethe/quiz/go_common/metrics"
+)
+
+const (
+	outboxRelayAction     = "OutboxRelay"
+	outboxRelayLockKey    = "quiz_outbox_relay_lock"
+	outboxRelayLockExpire = 30 * time.Second
+	// outboxSendTimeout is the interval the lock is renewed at while a batch waits for kafka, well below
+	// the lock expire so that another relay never sends the same rows concurrently
+	outboxSendTimeout            = outboxRelayLockExpire / 3
+	outboxCleanupBatchSize       = 1000
+	defaultOutboxPollInterval    = 200 * time.Millisecond
+	defaultOutboxBatchSize       = 500
+	defaultOutboxRetention       = 24 * time.Hour
+	defaultOutboxCleanupInterval = 10 * time.Minute
+)
+
+var errOutboxLockLost = errors.New("outbox relay lock lost while waiting for kafka")
+
+// OutboxRelay publishes pending rows of quiz_outbox_tab to kafka and marks them sent.
+// A row is only marked after kafka acknowledges it, so delivery is at least once.
+// Only the instance holding the redis lock relays, and the producer keeps the order of a key, so that the rows
+// of a quiz are published in order. After a failed row, the following rows of its quiz stay pending and are sent
+// again after it, so that the last delivery of each row follows the one of the row before it. A batch waits for
+// all its sends before the next one lists the pending rows, the rows in flight are never sent twice at once.
+type OutboxRelay struct {
+	dep             *Dependency
+	lock            *redisLock
+	sendTimeout     time.Duration
+	pollInterval    time.Duration
+	batchSize       int
+	retention       time.Duration
+	cleanupInterval time.Duration
+}
+
+func NewOutboxRelay(dep *Dependency) *OutboxRelay {
+	relay := &OutboxRelay{
+		dep:             dep,
+		lock:            newRedisLock(dep.Cache, outboxRelayLockKey, outboxRelayLockExpire),
+		sendTimeout:     outboxSendTimeout,
+		pollInterval:    defaultOutboxPollInterval,
+		batchSize:       defaultOutboxBatchSize,
+		retention:       defaultOutboxRetention,
+		cleanupInterval: defaultOutboxCleanupInterval,
+	}
+	if dep.Conf != nil {
+		conf := dep.Conf.Outbox
+		if conf.PollInterval > 0 {
+			relay.pollInterval = conf.PollInterval
+		}
+		if conf.BatchSize > 0 {
+			relay.batchSize = conf.BatchSize
+		}
+		if conf.Retention > 0 {
+			relay.retention = conf.Retention
+		}
+		if conf.CleanupInterval > 0 {
+			relay.cleanupInterval = conf.CleanupInterval
+		}
+	}
+	return relay
+}
+
+// Run starts the relay and the cleanup of sent rows until ctx is cancelled
+func (r *OutboxRelay) Run(ctx context.Context, wg *sync.WaitGroup) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		pollTicker := time.NewTicker(r.pollInterval)
+		defer pollTicker.Stop()
+		cleanupTicker := time.NewTicker(r.cleanupInterval)
+		defer cleanupTicker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-cleanupTicker.C:
+				r.cleanup(ctx)
+			case <-pollTicker.C:
+				r.relay(ctx)
+			}
+		}
+	}()
+}
+
+// relay drains the outbox of every database, the events of a quiz are in the outbox of its shard
+// except the status changes which are in the main database
+func (r *OutboxRelay) relay(ctx context.Context) {
+	for database := range r.dep.DB.Databases() {
+		r.relayDatabase(ctx, database)
+	}
+}
+
+func (r *OutboxRelay) relayDatabase(ctx context.Context, database int) {
+	for ctx.Err() == nil && r.lock.hold() {
+		count, err := r.relayOnce(ctx, database)
+		if err != nil {
+			log.Errorff(ctx, "OutboxRelay.relayOnce|database:%v|err:%v", database, err)
+			return
+		}
+		if count < r.batchSize {
+			return
+		}
+	}
+}
+
+func (r *OutboxRelay) relayOnce(ctx context.Context, database int) (int, error) {
+	err, messages := r.dep.QuizDAO.ListPendingOutboxMessages(ctx, database, r.batchSize)
+	if err != nil {
+		return 0, err
+	}
+	if len(messages) == 0 {
+		return 0, nil
+	}
+
+	results := make([]error, len(messages))
+	wg := &sync.WaitGroup{}
+	wg.Add(len(messages))
+	for i, message := range messages {
+		i := i
+		r.dep.Producer.Send(ctx, &sarama.ProducerMessage{
+			Topic: message.Topic,
+			Key:   sarama.StringEncoder(message.MsgKey),
+			Value: sarama.ByteEncoder(message.Payload),
+		}, func(err error) {
+			results[i] = err
+			wg.Done()
+		})
+	}
+
+	// the sends can't be taken back, the next batch would send the pending rows again before kafka answers them
+	// so it waits for every callback with the lock renewed
+	lockLost := r.waitSends(ctx, database, wg, len(messages))
+
+	var sentIDs []int64
+	failedKeys := map[string]bool{}
+	for i, message := range messages {
+		if results[i] != nil {
+			failedKeys[message.MsgKey] = true
+		}
+		if !failedKeys[message.MsgKey] {
+			sentIDs = append(sentIDs, message.ID)
+		}
+	}
+	r.reportCount(len(sentIDs), metrics.ResultSuccess)
+	r.reportCount(len(messages)-len(sentIDs), metrics.ResultError)
+
+	err = r.dep.QuizDAO.MarkOutboxMessagesSent(ctx, database, sentIDs)
+	if err != nil {
+		return 0, err
+	}
+	if len(sentIDs) < len(messages) {
+		return len(sentIDs), fmt.Errorf("%d outbox messages failed to send", len(messages)-len(sentIDs))
+	}
+	if lockLost {
+		return len(messages), errOutboxLockLost
+	}
+
+	return len(messages), nil
+}
+
+// waitSends returns once wg is done, it returns true when the lock was lost meanwhile
+func (r *OutboxRelay) waitSends(ctx context.Context, database int, wg *sync.WaitGroup, count int) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	ticker := time.NewTicker(r.sendTimeout)
+	defer ticker.Stop()
+	lockLost := false
+	for {
+		select {
+		case <-done:
+			return lockLost
+		case <-ticker.C:
+			log.Warnff(ctx, "OutboxRelay.waitSends|database:%v|messages:%v|waiting for kafka", database, count)
+			if !r.lock.hold() {
+				lockLost = true
+			}
+		}
+	}
+}
+
+func (r *OutboxRelay) cleanup(ctx context.Context) {
+	sentBefore := time.Now().Add(-r.retention).UnixMilli()
+	for database := range r.dep.DB.Databases() {
+		r.cleanupDatabase(ctx, database, sentBefore)
+	}
+}
+
+func (r *OutboxRelay) cleanupDatabase(ctx context.Context, database int, sentBefore int64) {
+	for ctx.Err() == nil {
+		err, count := r.dep.QuizDAO.DeleteSentOutboxMessages(ctx, database, sentBefore, outboxCleanupBatchSize)
+		if err != nil {
+			log.Errorff(ctx, "OutboxRelay.cleanup|database:%v|err:%v", database, err)
+			return
+		}
+		if count < outboxCleanupBatchSize {
+			return
+		}
+	}
+}
+
+func (r *OutboxRelay) reportCount(count int, result metrics.ResultType) {
+	if r.dep.Stats != nil && count > 0 {
+		r.dep.Stats.ReportCount(float64(count), outboxRelayAction, string(result))
+	}
+}
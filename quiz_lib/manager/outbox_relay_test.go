@@ -0,0 +1,227 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/luulethe/quiz/go_common/cache"
+	"github.com/luulethe/quiz/quiz_lib/db"
+	"github.com/luulethe/quiz/quiz_lib/db/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProducer acknowledges the messages after delay, the first fails[payload] sends of a payload fail
+type fakeProducer struct {
+	mutex sync.Mutex
+	delay time.Duration
+	fails map[string]int
+	sent  []string // the payloads delivered, in order
+}
+
+func (p *fakeProducer) Send(ctx context.Context, message *sarama.ProducerMessage, callback func(error)) {
+	payload := string(message.Value.(sarama.ByteEncoder))
+	p.mutex.Lock()
+	var err error
+	if p.fails[payload] > 0 {
+		p.fails[payload]--
+		err = errors.New("kafka unavailable")
+	} else {
+		p.sent = append(p.sent, payload)
+	}
+	p.mutex.Unlock()
+
+	go func() {
+		time.Sleep(p.delay)
+		callback(err)
+	}()
+}
+
+func (p *fakeProducer) Close() error {
+	return nil
+}
+
+func (p *fakeProducer) delivered() []string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return append([]string(nil), p.sent...)
+}
+
+// outboxDAO keeps the outbox rows per database, the other methods aren't used
+type outboxDAO struct {
+	QuizDAO
+	mutex     sync.Mutex
+	lastID    int64
+	outboxes  map[int][]*model.QuizOutboxTab
+	databases []int // the databases listed, in order
+}
+
+func (d *outboxDAO) add(database int, key string, payloads ...string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	for _, payload := range payloads {
+		d.lastID++
+		d.outboxes[database] = append(d.outboxes[database], &model.QuizOutboxTab{
+			ID: d.lastID, Topic: "quiz_event", MsgKey: key, Payload: []byte(payload), Status: model.OutboxStatusPending,
+		})
+	}
+}
+
+func (d *outboxDAO) count(database int, status int32) int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	count := 0
+	for _, message := range d.outboxes[database] {
+		if message.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+func (d *outboxDAO) ListPendingOutboxMessages(ctx context.Context, database int, limit int) (error, []*model.QuizOutboxTab) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.databases = append(d.databases, database)
+	var messages []*model.QuizOutboxTab
+	for _, message := range d.outboxes[database] {
+		if message.Status == model.OutboxStatusPending && len(messages) < limit {
+			stored := *message
+			messages = append(messages, &stored)
+		}
+	}
+	return nil, messages
+}
+
+func (d *outboxDAO) MarkOutboxMessagesSent(ctx context.Context, database int, ids []int64) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	sent := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		sent[id] = true
+	}
+	for _, message := range d.outboxes[database] {
+		if sent[message.ID] {
+			message.Status = model.OutboxStatusSent
+			message.SentTime = time.Now().UnixMilli()
+		}
+	}
+	return nil
+}
+
+func (d *outboxDAO) DeleteSentOutboxMessages(ctx context.Context, database int, sentBefore int64, limit int) (error, int64) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	kept := d.outboxes[database][:0]
+	deleted := int64(0)
+	for _, message := range d.outboxes[database] {
+		if message.Status == model.OutboxStatusSent && message.SentTime < sentBefore && deleted < int64(limit) {
+			deleted++
+			continue
+		}
+		kept = append(kept, message)
+	}
+	d.outboxes[database] = kept
+	return nil, deleted
+}
+
+// newTestOutboxRelay relays the outbox of databases databases
+func newTestOutboxRelay(t *testing.T, databases int) (*OutboxRelay, *outboxDAO, *fakeProducer) {
+	server := miniredis.RunT(t)
+	redisCache, err := cache.NewRedisClient(server.Addr(), &cache.RedisOption{})
+	require.NoError(t, err)
+	t.Cleanup(func() { redisCache.Close() })
+
+	quizDBs := make([]db.QuizDB, 0, databases)
+	for i := 0; i < databases; i++ {
+		quizDBs = append(quizDBs, db.NewNoteDBForTest(nil))
+	}
+	sharded, err := db.NewShardedQuizDB(quizDBs, nil)
+	require.NoError(t, err)
+
+	dao := &outboxDAO{outboxes: map[int][]*model.QuizOutboxTab{}}
+	producer := &fakeProducer{fails: map[string]int{}}
+	relay := NewOutboxRelay(&Dependency{Cache: redisCache, DB: sharded, QuizDAO: dao, Producer: producer})
+	return relay, dao, producer
+}
+
+func TestOutboxRelay_SlowSends(t *testing.T) {
+	ctx := context.Background()
+	relay, dao, producer := newTestOutboxRelay(t, 1)
+	relay.sendTimeout = 5 * time.Millisecond
+	producer.delay = 50 * time.Millisecond
+	dao.add(0, "1", "q1 v1", "q1 v2")
+
+	// the batch waits for kafka past the send timeout, the rows in flight aren't listed again
+	relay.relay(ctx)
+	relay.relay(ctx)
+	assert.Equal(t, []string{"q1 v1", "q1 v2"}, producer.delivered())
+	assert.Equal(t, 2, dao.count(0, model.OutboxStatusSent))
+	assert.Equal(t, []int{0, 0}, dao.databases)
+}
+
+func TestOutboxRelay_PartialFailure(t *testing.T) {
+	ctx := context.Background()
+	relay, dao, producer := newTestOutboxRelay(t, 1)
+	producer.fails["q1 v1"] = 1
+	dao.add(0, "1", "q1 v1")
+	dao.add(0, "2", "q2 v1")
+	dao.add(0, "1", "q1 v2")
+
+	// the rows of quiz 1 stay pending from its failed row, quiz 2 is not held back
+	relay.relay(ctx)
+	assert.Equal(t, 1, dao.count(0, model.OutboxStatusSent))
+	assert.Equal(t, 2, dao.count(0, model.OutboxStatusPending))
+
+	// at least once: quiz 1 is sent again from its failed row, in order
+	relay.relay(ctx)
+	assert.Equal(t, 3, dao.count(0, model.OutboxStatusSent))
+	assert.Equal(t, []string{"q2 v1", "q1 v2", "q1 v1", "q1 v2"}, producer.delivered())
+}
+
+func TestOutboxRelay_Cleanup(t *testing.T) {
+	ctx := context.Background()
+	relay, dao, _ := newTestOutboxRelay(t, 2)
+	dao.add(0, "1", "q1 v1", "q1 v2")
+	dao.add(1, "2", "q2 v1")
+	relay.relay(ctx)
+	dao.add(1, "2", "q2 v2")
+
+	// the rows sent before the retention are deleted, the pending ones are kept
+	relay.retention = -time.Minute
+	relay.cleanup(ctx)
+	assert.Equal(t, 0, dao.count(0, model.OutboxStatusSent))
+	assert.Equal(t, 0, dao.count(1, model.OutboxStatusSent))
+	assert.Equal(t, 1, dao.count(1, model.OutboxStatusPending))
+
+	relay.retention = time.Hour
+	relay.relay(ctx)
+	relay.cleanup(ctx)
+	assert.Equal(t, 1, dao.count(1, model.OutboxStatusSent))
+}
+
+func TestOutboxRelay_EveryDatabase(t *testing.T) {
+	ctx := context.Background()
+	relay, dao, producer := newTestOutboxRelay(t, 3)
+	dao.add(0, "3", "q3 v1")
+	dao.add(1, "1", "q1 v1", "q1 v2")
+	dao.add(2, "2", "q2 v1")
+
+	// each database is listed once per run, in order
+	relay.relay(ctx)
+	assert.Equal(t, []int{0, 1, 2}, dao.databases)
+	assert.Equal(t, []string{"q3 v1", "q1 v1", "q1 v2", "q2 v1"}, producer.delivered())
+	for database := 0; database < 3; database++ {
+		assert.Zero(t, dao.count(database, model.OutboxStatusPending), "database: %d", database)
+	}
+
+	// the databases without pending rows are still listed
+	relay.relay(ctx)
+	assert.Equal(t, []int{0, 1, 2, 0, 1, 2}, dao.databases)
+	assert.Len(t, producer.delivered(), 4)
+}
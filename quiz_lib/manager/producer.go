@@ -0,0 +1,83 @@
+package manager
+
+import (
+	"context"
+
+	"github.com/Shopify/sarama"
+	"github.com/luulethe/quiz/config"
+	"github.com/luulethe/quiz/go_common/kafka"
+	"github.com/luulethe/quiz/go_common/log"
+	"github.com/luulethe/quiz/go_common/metrics"
+	"github.com/luulethe/quiz/go_common/sentry"
+)
+
+const produceAction = "KafkaProduce"
+
+// Producer publishes messages to kafka asynchronously
+type Producer interface {
+	// Send invokes callback with the delivery result once kafka acknowledges the message, callback can be nil
+	Send(ctx context.Context, message *sarama.ProducerMessage, callback func(error))
+	Close() error
+}
+
+// NewProducer returns a producer which drops all messages if no kafka broker is configured
+func NewProducer(ctx context.Context, conf *config.KafkaConfig, stats *metrics.StatsCollector) (Producer, error) {
+	if conf == nil || conf.Brokers == "" {
+		log.Info(ctx, "no kafka broker configured, quiz events are dropped")
+		return &noopProducer{}, nil
+	}
+
+	onSuccess := func(message *sarama.ProducerMessage) {
+		if stats != nil {
+			stats.ReportCount(1, produceAction, string(metrics.ResultSuccess))
+		}
+		if callback, ok := message.Metadata.(func(error)); ok {
+			callback(nil)
+		}
+	}
+	onError := func(err error) {
+		if stats != nil {
+			stats.ReportCount(1, produceAction, string(metrics.ResultError))
+		}
+		sentry.CaptureError(ctx, err, 0)
+		if producerErr, ok := err.(*sarama.ProducerError); ok {
+			if callback, ok := producerErr.Msg.Metadata.(func(error)); ok {
+				callback(producerErr.Err)
+			}
+		}
+	}
+	producer, err := kafka.NewAsyncKafkaProducer(ctx, conf.Brokers, onSuccess, onError)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kafkaProducer{producer: producer}, nil
+}
+
+type kafkaProducer struct {
+	producer sarama.AsyncProducer
+}
+
+func (p *kafkaProducer) Send(ctx context.Context, message *sarama.ProducerMessage, callback func(error)) {
+	if callback != nil {
+		message.Metadata = callback
+	}
+	p.producer.Input() <- message
+}
+
+func (p *kafkaProducer) Close() error {
+	return p.producer.Close()
+}
+
+type noopProducer struct{}
+
+func (p *noopProducer) Send(ctx context.Context, message *sarama.ProducerMessage, callback func(error)) {
+	log.Debugff(ctx, "Producer.Send|dropped|topic:%v|key:%v", message.Topic, message.Key)
+	if callback != nil {
+		callback(nil)
+	}
+}
+
+func (p *noopProducer) Close() error {
+	return nil
+}
@@ -0,0 +1,278 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/luulethe/quiz/quiz_lib/db/model"
+	pb "github.com/luulethe/quiz/quiz_lib/pb/gen"
+	"github.com/luulethe/quiz/quiz_lib/quiz_error"
+)
+
+const (
+	maxQuizNameLength     = 1024
+	maxQuestionLength     = 4096
+	maxAnswerOptionLength = 1024
+	minAnswerOptions      = 2
+	maxAnswerOptions      = 10
+)
+
+// QuizAdmin holds the admin operations of quizzes, only draft quizzes have their content edited
+type QuizAdmin interface {
+	CreateQuiz(ctx context.Context, name string, startTime int64, endTime int64) (int64, error)
+	UpdateQuiz(ctx context.Context, quizID int64, name string, startTime int64, endTime int64) error
+	AddQuestion(
+		ctx context.Context, question *model.QuizQuestionTab, options []*model.QuizAnswerOptionTab,
+	) (int64, error)
+	ReorderQuestions(ctx context.Context, quizID int64, questionIDs []int64) error
+	PublishQuiz(ctx context.Context, quizID int64) error
+	CloseQuiz(ctx context.Context, quizID int64) error
+	PauseQuiz(ctx context.Context, quizID int64) error
+	ResumeQuiz(ctx context.Context, quizID int64) error
+	DeleteQuiz(ctx context.Context, quizID int64) error
+}
+
+// validateQuiz rejects a quiz without name or with invalid times
+func validateQuiz(name string, startTime int64, endTime int64) error {
+	if name == "" || len(name) > maxQuizNameLength {
+		return quiz_error.Newf(pb.Error_ERROR_INVALID_PARAMETER, "name must have 1 to %d bytes", maxQuizNameLength)
+	}
+	if !isValidQuizTime(startTime, endTime) {
+		return quiz_error.Newf(pb.Error_ERROR_INVALID_PARAMETER, "end_time must be after start_time")
+	}
+	return nil
+}
+
+// isValidQuizTime accepts a quiz without times, or one ending after it starts
+func isValidQuizTime(startTime int64, endTime int64) bool {
+	if startTime == 0 && endTime == 0 {
+		return true
+	}
+	return startTime > 0 && endTime > startTime
+}
+
+func (q *QuizManagerImpl) CreateQuiz(
+	ctx context.Context, name string, startTime int64, endTime int64,
+) (int64, error) {
+	if err := validateQuiz(name, startTime, endTime); err != nil {
+		return 0, err
+	}
+
+	err, quiz := q.dep.QuizDAO.CreateQuiz(ctx, &model.QuizTab{
+		Status:    model.QuizStatusDraft,
+		Name:      name,
+		StartTime: startTime,
+		EndTime:   endTime,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return quiz.ID, nil
+}
+
+// UpdateQuiz edits a draft quiz, or a scheduled one as long as it stays scheduled in the future
+func (q *QuizManagerImpl) UpdateQuiz(
+	ctx context.Context, quizID int64, name string, startTime int64, endTime int64,
+) error {
+	if err := validateQuiz(name, startTime, endTime); err != nil {
+		return err
+	}
+
+	err, quiz := q.dep.QuizDAO.FindQuizByIDFromMaster(ctx, quizID)
+	if err != nil {
+		return err
+	}
+
+	if quiz == nil {
+		return quiz_error.ErrQuizNotExisted
+	}
+
+	now := time.Now().UnixMilli()
+	switch quiz.Status {
+	case model.QuizStatusDraft:
+	case model.QuizStatusScheduled:
+		if startTime <= now {
+			return quiz_error.Newf(pb.Error_ERROR_INVALID_PARAMETER, "start_time of a scheduled quiz must be in the future")
+		}
+	default:
+		return quiz_error.ErrInvalidQuizStatus
+	}
+
+	err, updated := q.dep.QuizDAO.UpdateQuiz(ctx, quizID, quiz.Status, map[string]interface{}{
+		"name":         name,
+		"start_time":   startTime,
+		"end_time":     endTime,
+		"updated_time": now,
+	})
+	if err != nil {
+		return err
+	}
+
+	if !updated {
+		return quiz_error.ErrInvalidQuizStatus
+	}
+
+	return nil
+}
+
+// AddQuestion appends a question with at least one correct answer option to a draft quiz
+func (q *QuizManagerImpl) AddQuestion(
+	ctx context.Context, question *model.QuizQuestionTab, options []*model.QuizAnswerOptionTab,
+) (int64, error) {
+	if !isValidQuestion(question, options) {
+		return 0, quiz_error.Newf(pb.Error_ERROR_INVALID_PARAMETER,
+			"a question needs a content, %d to %d answer options and a correct one", minAnswerOptions, maxAnswerOptions)
+	}
+
+	err, quiz := q.dep.QuizDAO.FindQuizByIDFromMaster(ctx, question.QuizID)
+	if err != nil {
+		return 0, err
+	}
+
+	if quiz == nil {
+		return 0, quiz_error.ErrQuizNotExisted
+	}
+
+	if quiz.Status != model.QuizStatusDraft {
+		return 0, quiz_error.ErrInvalidQuizStatus
+	}
+
+	err, question = q.dep.QuizDAO.CreateQuestion(ctx, model.QuizStatusDraft, question, options)
+	if errors.Is(err, ErrQuizStatusChanged) {
+		return 0, quiz_error.ErrInvalidQuizStatus
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return question.ID, nil
+}
+
+func isValidQuestion(question *model.QuizQuestionTab, options []*model.QuizAnswerOptionTab) bool {
+	if question.Content == "" || len(question.Content) > maxQuestionLength || question.Score < 0 || question.Duration < 0 {
+		return false
+	}
+	if len(options) < minAnswerOptions || len(options) > maxAnswerOptions {
+		return false
+	}
+
+	hasCorrect := false
+	for _, option := range options {
+		if option.Content == "" || len(option.Content) > maxAnswerOptionLength {
+			return false
+		}
+		hasCorrect = hasCorrect || option.IsCorrect
+	}
+	return hasCorrect
+}
+
+// ReorderQuestions sets the order of all questions of a draft quiz
+func (q *QuizManagerImpl) ReorderQuestions(ctx context.Context, quizID int64, questionIDs []int64) error {
+	err, quiz := q.dep.QuizDAO.FindQuizByIDFromMaster(ctx, quizID)
+	if err != nil {
+		return err
+	}
+
+	if quiz == nil {
+		return quiz_error.ErrQuizNotExisted
+	}
+
+	if quiz.Status != model.QuizStatusDraft {
+		return quiz_error.ErrInvalidQuizStatus
+	}
+
+	err = q.dep.QuizDAO.ReorderQuestions(ctx, quizID, model.QuizStatusDraft, questionIDs)
+	if errors.Is(err, ErrQuestionsMismatch) {
+		return quiz_error.Newf(pb.Error_ERROR_INVALID_PARAMETER, "question_ids must be every question of the quiz")
+	}
+	if errors.Is(err, ErrQuizStatusChanged) {
+		return quiz_error.ErrInvalidQuizStatus
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// PublishQuiz schedules a draft quiz which has questions
+func (q *QuizManagerImpl) PublishQuiz(ctx context.Context, quizID int64) error {
+	// from master, the question may have been added right before the publish
+	err, questions := q.dep.QuizDAO.ListQuizQuestionsFromMaster(ctx, quizID)
+	if err != nil {
+		return err
+	}
+
+	if len(questions) == 0 {
+		return quiz_error.ErrQuestionNotExisted
+	}
+
+	return q.TransitQuiz(ctx, quizID, model.QuizStatusScheduled)
+}
+
+// CloseQuiz finishes a quiz before its end_time
+func (q *QuizManagerImpl) CloseQuiz(ctx context.Context, quizID int64) error {
+	return q.TransitQuiz(ctx, quizID, model.QuizStatusFinished)
+}
+
+// PauseQuiz pauses a quiz in progress, its answers are rejected with ERROR_QUIZ_PAUSED until it resumes
+func (q *QuizManagerImpl) PauseQuiz(ctx context.Context, quizID int64) error {
+	return q.TransitQuiz(ctx, quizID, model.QuizStatusPaused)
+}
+
+// ResumeQuiz moves a paused quiz back in progress, its end_time and the windows of its questions
+// are pushed back by the pause
+func (q *QuizManagerImpl) ResumeQuiz(ctx context.Context, quizID int64) error {
+	err, quiz := q.dep.QuizDAO.FindQuizByIDFromMaster(ctx, quizID)
+	if err != nil {
+		return err
+	}
+
+	if quiz == nil {
+		return quiz_error.ErrQuizNotExisted
+	}
+
+	// a quiz in the lobby is started by the scheduler, not resumed
+	if quiz.Status != model.QuizStatusPaused {
+		return quiz_error.ErrInvalidQuizStatus
+	}
+
+	err, updated := transitQuiz(ctx, q.dep, quiz, model.QuizStatusInProgress)
+	if err != nil {
+		return err
+	}
+
+	if !updated {
+		return quiz_error.ErrInvalidQuizStatus
+	}
+
+	return nil
+}
+
+// DeleteQuiz deletes a draft quiz with its questions
+func (q *QuizManagerImpl) DeleteQuiz(ctx context.Context, quizID int64) error {
+	err, quiz := q.dep.QuizDAO.FindQuizByIDFromMaster(ctx, quizID)
+	if err != nil {
+		return err
+	}
+
+	if quiz == nil {
+		return quiz_error.ErrQuizNotExisted
+	}
+
+	if quiz.Status != model.QuizStatusDraft {
+		return quiz_error.ErrInvalidQuizStatus
+	}
+
+	err = q.dep.QuizDAO.DeleteQuiz(ctx, quizID, model.QuizStatusDraft)
+	if errors.Is(err, ErrQuizStatusChanged) {
+		return quiz_error.ErrInvalidQuizStatus
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,241 @@
+package manager_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/luulethe/quiz/quiz_lib/db/model"
+	"github.com/luulethe/quiz/quiz_lib/manager/managertest"
+	"github.com/luulethe/quiz/quiz_lib/quiz_error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// laggingDAO is a database whose replica hasn't received the questions yet
+type laggingDAO struct {
+	*managertest.QuizDAO
+}
+
+func (d *laggingDAO) ListQuizQuestions(ctx context.Context, quizID int64) (error, []*model.QuizQuestionTab) {
+	return nil, nil
+}
+
+func TestPublishQuiz_ReadsQuestionsFromMaster(t *testing.T) {
+	ctx := context.Background()
+	dao := &laggingDAO{QuizDAO: managertest.NewQuizDAO()}
+	dep := newTestDependency(t, dao)
+	now := time.Now().UnixMilli()
+	err, quiz := dao.CreateQuiz(ctx, &model.QuizTab{
+		Status:    model.QuizStatusDraft,
+		Name:      "quiz",
+		StartTime: now + int64(time.Hour/time.Millisecond),
+		EndTime:   now + int64(2*time.Hour/time.Millisecond),
+	})
+	require.NoError(t, err)
+
+	assert.True(t, errors.Is(dep.QuizManager.PublishQuiz(ctx, quiz.ID), quiz_error.ErrQuestionNotExisted))
+
+	dao.AddQuestion(
+		&model.QuizQuestionTab{QuizID: quiz.ID, Content: "question", Score: 10, Seq: 1},
+		&model.QuizAnswerOptionTab{Content: "right", IsCorrect: true},
+	)
+	require.NoError(t, dep.QuizManager.PublishQuiz(ctx, quiz.ID))
+	_, published := dao.FindQuizByID(ctx, quiz.ID)
+	assert.Equal(t, int32(model.QuizStatusScheduled), published.Status)
+}
+
+func TestCreateQuiz_Validation(t *testing.T) {
+	ctx := context.Background()
+	dep := newTestDependency(t, managertest.NewQuizDAO())
+	now := time.Now().UnixMilli()
+	tests := []struct {
+		name      string
+		quizName  string
+		startTime int64
+		endTime   int64
+		valid     bool
+	}{
+		{"without times", "quiz", 0, 0, true},
+		{"with times", "quiz", now, now + 1, true},
+		{"longest name", strings.Repeat("a", 1024), 0, 0, true},
+		{"empty name", "", 0, 0, false},
+		{"name too long", strings.Repeat("a", 1025), 0, 0, false},
+		{"start_time only", "quiz", now, 0, false},
+		{"end_time only", "quiz", 0, now, false},
+		{"ending when it starts", "quiz", now, now, false},
+		{"ending before it starts", "quiz", now, now - 1, false},
+		{"negative start_time", "quiz", -1, now, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			quizID, err := dep.QuizManager.CreateQuiz(ctx, test.quizName, test.startTime, test.endTime)
+			if test.valid {
+				require.NoError(t, err)
+				assert.NotZero(t, quizID)
+				return
+			}
+			assert.True(t, errors.Is(err, quiz_error.ErrInvalidParameter), "err: %v", err)
+			assert.True(t, errors.Is(dep.QuizManager.UpdateQuiz(ctx, 1, test.quizName, test.startTime, test.endTime),
+				quiz_error.ErrInvalidParameter))
+		})
+	}
+}
+
+func TestAddQuestion_Validation(t *testing.T) {
+	ctx := context.Background()
+	dao := managertest.NewQuizDAO()
+	dep := newTestDependency(t, dao)
+	err, quiz := dao.CreateQuiz(ctx, &model.QuizTab{Status: model.QuizStatusDraft, Name: "quiz"})
+	require.NoError(t, err)
+
+	options := func(count int, correct bool) []*model.QuizAnswerOptionTab {
+		result := make([]*model.QuizAnswerOptionTab, 0, count)
+		for i := 0; i < count; i++ {
+			result = append(result, &model.QuizAnswerOptionTab{Content: "option", IsCorrect: correct && i == 0})
+		}
+		return result
+	}
+	tests := []struct {
+		name     string
+		question model.QuizQuestionTab
+		options  []*model.QuizAnswerOptionTab
+		valid    bool
+	}{
+		{"minimum options", model.QuizQuestionTab{Content: "question"}, options(2, true), true},
+		{"maximum options", model.QuizQuestionTab{Content: "question", Score: 10, Duration: 30}, options(10, true), true},
+		{"longest content", model.QuizQuestionTab{Content: strings.Repeat("q", 4096)}, options(2, true), true},
+		{"empty content", model.QuizQuestionTab{}, options(2, true), false},
+		{"content too long", model.QuizQuestionTab{Content: strings.Repeat("q", 4097)}, options(2, true), false},
+		{"negative score", model.QuizQuestionTab{Content: "question", Score: -1}, options(2, true), false},
+		{"negative duration", model.QuizQuestionTab{Content: "question", Duration: -1}, options(2, true), false},
+		{"one option", model.QuizQuestionTab{Content: "question"}, options(1, true), false},
+		{"too many options", model.QuizQuestionTab{Content: "question"}, options(11, true), false},
+		{"no correct option", model.QuizQuestionTab{Content: "question"}, options(2, false), false},
+		{"empty option", model.QuizQuestionTab{Content: "question"},
+			append(options(1, true), &model.QuizAnswerOptionTab{}), false},
+		{"option too long", model.QuizQuestionTab{Content: "question"},
+			append(options(1, true), &model.QuizAnswerOptionTab{Content: strings.Repeat("o", 1025)}), false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			question := test.question
+			question.QuizID = quiz.ID
+			questionID, err := dep.QuizManager.AddQuestion(ctx, &question, test.options)
+			if test.valid {
+				require.NoError(t, err)
+				assert.NotZero(t, questionID)
+				return
+			}
+			assert.True(t, errors.Is(err, quiz_error.ErrInvalidParameter), "err: %v", err)
+		})
+	}
+}
+
+func TestQuizAdmin_StatusGuards(t *testing.T) {
+	ctx := context.Background()
+	dao := managertest.NewQuizDAO()
+	dep := newTestDependency(t, dao)
+	future := time.Now().UnixMilli() + int64(time.Hour/time.Millisecond)
+
+	statuses := []int32{
+		model.QuizStatusDraft, model.QuizStatusScheduled, model.QuizStatusLobby, model.QuizStatusInProgress,
+		model.QuizStatusPaused, model.QuizStatusFinished, model.QuizStatusArchived,
+	}
+	operations := []struct {
+		name string
+		// allowed are the statuses the operation accepts
+		allowed map[int32]bool
+		run     func(quiz *model.QuizTab, question *model.QuizQuestionTab) error
+	}{
+		{"update", map[int32]bool{model.QuizStatusDraft: true, model.QuizStatusScheduled: true},
+			func(quiz *model.QuizTab, question *model.QuizQuestionTab) error {
+				return dep.QuizManager.UpdateQuiz(ctx, quiz.ID, "renamed", future, future+1)
+			}},
+		{"add question", map[int32]bool{model.QuizStatusDraft: true},
+			func(quiz *model.QuizTab, question *model.QuizQuestionTab) error {
+				_, err := dep.QuizManager.AddQuestion(ctx, &model.QuizQuestionTab{QuizID: quiz.ID, Content: "question"},
+					[]*model.QuizAnswerOptionTab{{Content: "right", IsCorrect: true}, {Content: "wrong"}})
+				return err
+			}},
+		{"reorder", map[int32]bool{model.QuizStatusDraft: true},
+			func(quiz *model.QuizTab, question *model.QuizQuestionTab) error {
+				return dep.QuizManager.ReorderQuestions(ctx, quiz.ID, []int64{question.ID})
+			}},
+		{"delete", map[int32]bool{model.QuizStatusDraft: true},
+			func(quiz *model.QuizTab, question *model.QuizQuestionTab) error {
+				return dep.QuizManager.DeleteQuiz(ctx, quiz.ID)
+			}},
+	}
+	for _, operation := range operations {
+		for _, status := range statuses {
+			t.Run(fmt.Sprintf("%s %d", operation.name, status), func(t *testing.T) {
+				err, quiz := dao.CreateQuiz(ctx, &model.QuizTab{Status: status, Name: "quiz", StartTime: future, EndTime: future + 1})
+				require.NoError(t, err)
+				question := dao.AddQuestion(&model.QuizQuestionTab{QuizID: quiz.ID, Content: "question", Seq: 1},
+					&model.QuizAnswerOptionTab{Content: "right", IsCorrect: true})
+
+				err = operation.run(quiz, question)
+				if operation.allowed[status] {
+					assert.NoError(t, err)
+					return
+				}
+				assert.True(t, errors.Is(err, quiz_error.ErrInvalidQuizStatus), "err: %v", err)
+				_, unchanged := dao.FindQuizByID(ctx, quiz.ID)
+				require.NotNil(t, unchanged)
+				assert.Equal(t, "quiz", unchanged.Name)
+			})
+		}
+	}
+
+	// a scheduled quiz stays scheduled in the future
+	err, scheduled := dao.CreateQuiz(ctx, &model.QuizTab{Status: model.QuizStatusScheduled, Name: "quiz", StartTime: future, EndTime: future + 1})
+	require.NoError(t, err)
+	past := time.Now().UnixMilli() - 1000
+	assert.True(t, errors.Is(dep.QuizManager.UpdateQuiz(ctx, scheduled.ID, "quiz", past, future), quiz_error.ErrInvalidParameter))
+	assert.True(t, errors.Is(dep.QuizManager.UpdateQuiz(ctx, scheduled.ID+100, "quiz", 0, 0), quiz_error.ErrQuizNotExisted))
+}
+
+func TestReorderQuestions_Mismatch(t *testing.T) {
+	ctx := context.Background()
+	dao := managertest.NewQuizDAO()
+	dep := newTestDependency(t, dao)
+	err, quiz := dao.CreateQuiz(ctx, &model.QuizTab{Status: model.QuizStatusDraft, Name: "quiz"})
+	require.NoError(t, err)
+	var questionIDs []int64
+	for seq := int32(1); seq <= 3; seq++ {
+		question := dao.AddQuestion(&model.QuizQuestionTab{QuizID: quiz.ID, Content: "question", Seq: seq},
+			&model.QuizAnswerOptionTab{Content: "right", IsCorrect: true})
+		questionIDs = append(questionIDs, question.ID)
+	}
+	err, other := dao.CreateQuiz(ctx, &model.QuizTab{Status: model.QuizStatusDraft, Name: "other"})
+	require.NoError(t, err)
+	otherQuestion := dao.AddQuestion(&model.QuizQuestionTab{QuizID: other.ID, Content: "question", Seq: 1},
+		&model.QuizAnswerOptionTab{Content: "right", IsCorrect: true})
+
+	mismatches := map[string][]int64{
+		"missing question":       questionIDs[:2],
+		"duplicated question":    {questionIDs[0], questionIDs[1], questionIDs[1]},
+		"question of other quiz": {questionIDs[0], questionIDs[1], otherQuestion.ID},
+		"extra question":         append([]int64{otherQuestion.ID}, questionIDs...),
+		"no question":            nil,
+	}
+	for name, ids := range mismatches {
+		t.Run(name, func(t *testing.T) {
+			err := dep.QuizManager.ReorderQuestions(ctx, quiz.ID, ids)
+			assert.True(t, errors.Is(err, quiz_error.ErrInvalidParameter), "err: %v", err)
+		})
+	}
+
+	require.NoError(t, dep.QuizManager.ReorderQuestions(ctx, quiz.ID, []int64{questionIDs[2], questionIDs[0], questionIDs[1]}))
+	err, questions := dao.ListQuizQuestions(ctx, quiz.ID)
+	require.NoError(t, err)
+	seqs := map[int64]int32{}
+	for _, question := range questions {
+		seqs[question.ID] = question.Seq
+	}
+	assert.Equal(t, map[int64]int32{questionIDs[2]: 1, questionIDs[0]: 2, questionIDs[1]: 3}, seqs)
+}
@@ -0,0 +1,47 @@
+package manager
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/luulethe/quiz/quiz_lib/db/model"
+	pb "github.com/luulethe/quiz/quiz_lib/pb/gen"
+	"google.golang.org/protobuf/proto"
+)
+
+// QuizEventVersion is bumped on every incompatible change of pb.QuizEvent
+const QuizEventVersion = 1
+
+func NewQuizEvent(eventType pb.QuizEventType, quizID int64, userID int64, delta int32) *pb.QuizEvent {
+	return &pb.QuizEvent{
+		Version:     QuizEventVersion,
+		Type:        eventType,
+		QuizId:      quizID,
+		UserId:      userID,
+		Delta:       delta,
+		CreatedTime: time.Now().UnixMilli(),
+	}
+}
+
+// NewParticipantEvent is the event of a change of the participant, with the participant written by the change
+func NewParticipantEvent(eventType pb.QuizEventType, participant *model.QuizParticipantTab, delta int32) *pb.QuizEvent {
+	event := NewQuizEvent(eventType, participant.QuizID, participant.UserID, delta)
+	event.State = &pb.QuizEvent_Participant{Participant: &pb.ParticipantState{
+		Score:       participant.Score,
+		UpdatedTime: participant.UpdatedTime,
+	}}
+	return event
+}
+
+// NewOutboxMessage keys the message by quiz id, so that events of a quiz are kept in order
+func NewOutboxMessage(topic string, event *pb.QuizEvent) (*model.QuizOutboxTab, error) {
+	payload, err := proto.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	return &model.QuizOutboxTab{
+		Topic:   topic,
+		MsgKey:  strconv.FormatInt(event.QuizId, 10),
+		Payload: payload,
+	}, nil
+}
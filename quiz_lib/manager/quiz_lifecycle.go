@@ -0,0 +1,120 @@
+package manager
+
+import (
+	"context"
+	"time"
+
+	"github.com/luulethe/quiz/quiz_lib/db/model"
+	pb "github.com/luulethe/quiz/quiz_lib/pb/gen"
+	"github.com/luulethe/quiz/quiz_lib/quiz_error"
+)
+
+// quizTransitions lists the statuses a quiz can move to from each status
+var quizTransitions = map[int32][]int32{
+	model.QuizStatusDraft:      {model.QuizStatusScheduled},
+	model.QuizStatusScheduled:  {model.QuizStatusDraft, model.QuizStatusLobby},
+	model.QuizStatusLobby:      {model.QuizStatusInProgress, model.QuizStatusFinished},
+	model.QuizStatusInProgress: {model.QuizStatusPaused, model.QuizStatusFinished},
+	model.QuizStatusPaused:     {model.QuizStatusInProgress, model.QuizStatusFinished},
+	model.QuizStatusFinished:   {model.QuizStatusArchived},
+}
+
+func CanTransitQuiz(fromStatus int32, toStatus int32) bool {
+	for _, status := range quizTransitions[fromStatus] {
+		if status == toStatus {
+			return true
+		}
+	}
+	return false
+}
+
+// canJoinQuiz tells whether a quiz of the status can be joined
+func canJoinQuiz(status int32) error {
+	switch status {
+	case model.QuizStatusLobby, model.QuizStatusInProgress, model.QuizStatusPaused:
+		return nil
+	case model.QuizStatusDraft, model.QuizStatusScheduled:
+		return quiz_error.ErrQuizNotStarted
+	case model.QuizStatusFinished, model.QuizStatusArchived:
+		return quiz_error.ErrQuizFinished
+	}
+	return quiz_error.ErrInvalidQuizStatus
+}
+
+// canSubmitAnswer tells whether the questions of a quiz of the status can be answered
+func canSubmitAnswer(status int32) error {
+	switch status {
+	case model.QuizStatusInProgress:
+		return nil
+	case model.QuizStatusPaused:
+		return quiz_error.ErrQuizPaused
+	case model.QuizStatusDraft, model.QuizStatusScheduled, model.QuizStatusLobby:
+		return quiz_error.ErrQuizNotStarted
+	case model.QuizStatusFinished, model.QuizStatusArchived:
+		return quiz_error.ErrQuizFinished
+	}
+	return quiz_error.ErrInvalidQuizStatus
+}
+
+// isQuestionOpen tells whether the question can be answered at now. The questions are asked one after
+// another in seq order from the start of the quiz, each for its duration, the time paused excluded.
+// A question without duration stays open until the end of the quiz.
+func isQuestionOpen(quiz *model.QuizTab, questions []*model.QuizQuestionTab, questionID int64, now int64) bool {
+	if quiz.StartTime == 0 {
+		return true
+	}
+	elapsed := now - quiz.StartTime - quiz.PausedDuration
+	offset := int64(0)
+	for _, question := range questions {
+		duration := int64(time.Duration(question.Duration) * time.Second / time.Millisecond)
+		if question.ID == questionID {
+			return elapsed >= offset && (question.Duration == 0 || elapsed < offset+duration)
+		}
+		offset += duration
+	}
+	return false
+}
+
+// quizTransitionUpdates returns the columns to update when the quiz moves to toStatus at now
+func quizTransitionUpdates(quiz *model.QuizTab, toStatus int32, now int64) map[string]interface{} {
+	updates := map[string]interface{}{
+		"status":       toStatus,
+		"updated_time": now,
+	}
+	switch {
+	case quiz.Status == model.QuizStatusLobby && toStatus == model.QuizStatusInProgress && now < quiz.StartTime:
+		// started early, the quiz keeps its length
+		updates["start_time"] = now
+		updates["end_time"] = quiz.EndTime - (quiz.StartTime - now)
+	case toStatus == model.QuizStatusPaused:
+		updates["paused_time"] = now
+	case quiz.Status == model.QuizStatusPaused && toStatus == model.QuizStatusInProgress:
+		paused := now - quiz.PausedTime
+		updates["paused_time"] = 0
+		updates["paused_duration"] = quiz.PausedDuration + paused
+		if quiz.EndTime > 0 {
+			updates["end_time"] = quiz.EndTime + paused
+		}
+	case toStatus == model.QuizStatusFinished:
+		updates["paused_time"] = 0
+		if quiz.EndTime == 0 || now < quiz.EndTime {
+			updates["end_time"] = now
+		}
+	}
+	return updates
+}
+
+// transitQuiz moves the quiz to toStatus and emits a QUIZ_EVENT_STATUS_CHANGED event.
+// It returns false if the quiz has left its status meanwhile.
+func transitQuiz(ctx context.Context, dep *Dependency, quiz *model.QuizTab, toStatus int32) (error, bool) {
+	event := NewQuizEvent(pb.QuizEventType_QUIZ_EVENT_STATUS_CHANGED, quiz.ID, 0, 0)
+	event.FromStatus = quiz.Status
+	event.ToStatus = toStatus
+	message, err := NewOutboxMessage(dep.QuizEventTopic(), event)
+	if err != nil {
+		return err, false
+	}
+
+	updates := quizTransitionUpdates(quiz, toStatus, time.Now().UnixMilli())
+	return dep.QuizDAO.UpdateQuiz(ctx, quiz.ID, quiz.Status, updates, message)
+}
@@ -2,70 +2,387 @@ package manager
 
 import (
 	"context"
+	"errors"
+	"github.com/luulethe/quiz/go_common/cache"
+	"github.com/luulethe/quiz/go_common/cache/cache_wrapper"
+	"github.com/luulethe/quiz/go_common/log"
 	"github.com/luulethe/quiz/quiz_lib/db/model"
 	pb "github.com/luulethe/quiz/quiz_lib/pb/gen"
+	"github.com/luulethe/quiz/quiz_lib/quiz_error"
+	"time"
 )
 
+const (
+	defaultLeaderBoardPageSize = 20
+	maxLeaderBoardPageSize     = 100
+	defaultLeaderBoardTopN     = 10
+	maxLeaderBoardRankUsers    = 1000
+
+	quizQuestionsCacheType      = "quiz_questions"
+	quizQuestionsCacheKeyFormat = "quiz_questions_%d"
+	quizQuestionsCacheExpire    = time.Hour
+)
+
+// QuizManager returns the business rejections of a request as quiz_error errors, any other error is a fault
 type QuizManager interface {
-	JoinQuiz(ctx context.Context, quizID int64, userID int64) (error, pb.Error)
-	HandleNewScoreChange(ctx context.Context, quizID int64) error
+	JoinQuiz(ctx context.Context, quizID int64, userID int64) error
+	SubmitAnswer(ctx context.Context, quizID int64, userID int64, questionID int64, answerID int64) (*SubmitAnswerResult, error)
+	GetLeaderBoard(ctx context.Context, quizID int64, pageIndex int32, pageSize int32) ([]*LeaderBoardEntry, int64, error)
+	GetLeaderBoardRanks(ctx context.Context, quizID int64, topN int32, userIDs []int64, withoutTop bool) (*LeaderBoardRanks, error)
+	TransitQuiz(ctx context.Context, quizID int64, toStatus int32) error
+	HandleParticipantEvents(ctx context.Context, quizID int64, events []*pb.QuizEvent) error
+	HandleStatusChanged(ctx context.Context, event *pb.QuizEvent) error
+	QuizAdmin
+}
+
+type SubmitAnswerResult struct {
+	Correct    bool
+	Score      int32
+	TotalScore int32
+}
+
+// LeaderBoardRanks is the top of a leader board with the ranks of some users
+type LeaderBoardRanks struct {
+	Top   []*LeaderBoardEntry
+	Ranks []*LeaderBoardEntry
+	Total int64
 }
 
 func NewQuizManager(dep *Dependency) QuizManager {
-	return &QuizManagerImpl{dep: dep}
+	q := &QuizManagerImpl{dep: dep}
+	if simpleCache, ok := dep.Cache.(cache.SimpleCache); ok {
+		cache_wrapper.RegisterCacheType(quizQuestionsCacheType, simpleCache)
+		q.questionsCache = &cache_wrapper.WrapperConfig{
+			KeyFormat: quizQuestionsCacheKeyFormat,
+			Expire:    quizQuestionsCacheExpire,
+			DataType:  &quizQuestions{},
+			CacheType: quizQuestionsCacheType,
+		}
+	}
+	return q
 }
 
 type QuizManagerImpl struct {
-	dep *Dependency
+	dep            *Dependency
+	questionsCache *cache_wrapper.WrapperConfig // nil without cache
+}
+
+// quizQuestions is the cached question set of a started quiz
+type quizQuestions struct {
+	Questions []*model.QuizQuestionTab
+}
+
+func (q *QuizManagerImpl) JoinQuiz(ctx context.Context, quizID int64, userID int64) error {
+	err, existed := q.checkUserExited(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !existed {
+		return quiz_error.ErrUserNotExisted
+	}
+
+	err, quiz := q.dep.QuizDAO.FindQuizByID(ctx, quizID)
+	if err != nil {
+		return err
+	}
+
+	if quiz == nil {
+		return quiz_error.ErrQuizNotExisted
+	}
+
+	if err := canJoinQuiz(quiz.Status); err != nil {
+		return err
+	}
+
+	// the unique index rejects a concurrent or repeated join, whatever the replicas have seen
+	messages := q.participantMessages(pb.QuizEventType_QUIZ_EVENT_PARTICIPANT_JOINED, 0)
+	err, quizParticipant := q.dep.QuizDAO.CreateQuizParticipant(ctx, quizID, userID, messages)
+	if errors.Is(err, ErrParticipantJoined) {
+		return quiz_error.ErrUserJoined
+	}
+	if err != nil {
+		return err
+	}
+	q.updateLeaderBoard(ctx, quiz, quizParticipant)
+
+	return nil
+}
+
+func (q *QuizManagerImpl) SubmitAnswer(
+	ctx context.Context, quizID int64, userID int64, questionID int64, answerID int64,
+) (*SubmitAnswerResult, error) {
+	err, quiz := q.dep.QuizDAO.FindQuizByID(ctx, quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	if quiz == nil {
+		return nil, quiz_error.ErrQuizNotExisted
+	}
+
+	if err := canSubmitAnswer(quiz.Status); err != nil {
+		return nil, err
+	}
+
+	err, questions := q.listStartedQuizQuestions(ctx, quiz)
+	if err != nil {
+		return nil, err
+	}
+
+	question := findQuestion(questions, questionID)
+	if question == nil {
+		return nil, quiz_error.ErrQuestionNotExisted
+	}
+
+	if !isQuestionOpen(quiz, questions, questionID, time.Now().UnixMilli()) {
+		return nil, quiz_error.ErrQuestionClosed
+	}
+
+	err, option := q.dep.QuizDAO.FindAnswerOptionByID(ctx, answerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if option == nil || option.QuestionID != questionID {
+		return nil, quiz_error.ErrAnswerNotExisted
+	}
+
+	answer := &model.QuizUserAnswerTab{
+		QuizID:     quizID,
+		QuestionID: questionID,
+		UserID:     userID,
+		AnswerID:   answerID,
+		IsCorrect:  option.IsCorrect,
+	}
+	if option.IsCorrect {
+		answer.Score = question.Score
+	}
+
+	var messages ParticipantMessages
+	if answer.Score != 0 {
+		messages = q.participantMessages(pb.QuizEventType_QUIZ_EVENT_SCORE_CHANGED, answer.Score)
+	}
+
+	err, participant := q.dep.QuizDAO.SubmitAnswer(ctx, answer, messages)
+	if errors.Is(err, ErrParticipantNotFound) {
+		return nil, quiz_error.ErrUserNotJoined
+	}
+	if errors.Is(err, ErrAnswerSubmitted) {
+		return nil, quiz_error.ErrAnswerSubmitted
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if answer.Score != 0 {
+		q.updateLeaderBoard(ctx, quiz, participant)
+	}
+
+	return &SubmitAnswerResult{
+		Correct:    answer.IsCorrect,
+		Score:      answer.Score,
+		TotalScore: participant.Score,
+	}, nil
 }
 
-func (q *QuizManagerImpl) JoinQuiz(ctx context.Context, quizID int64, userID int64) (error, pb.Error) {
-	if !q.checkUserExited(userID) {
-		return nil, pb.Error_ERROR_USER_NOT_EXISTED
+func (q *QuizManagerImpl) GetLeaderBoard(
+	ctx context.Context, quizID int64, pageIndex int32, pageSize int32,
+) ([]*LeaderBoardEntry, int64, error) {
+	if pageSize == 0 {
+		pageSize = defaultLeaderBoardPageSize
+	}
+	if pageIndex < 0 || pageSize < 0 || pageSize > maxLeaderBoardPageSize {
+		return nil, 0, quiz_error.Newf(pb.Error_ERROR_INVALID_PARAMETER,
+			"page_index must be positive and page_size at most %d", maxLeaderBoardPageSize)
 	}
 
 	err, quiz := q.dep.QuizDAO.FindQuizByID(ctx, quizID)
 	if err != nil {
-		return err, 0
+		return nil, 0, err
 	}
 
 	if quiz == nil {
-		return nil, pb.Error_ERROR_QUIZ_NOT_EXITED
+		return nil, 0, quiz_error.ErrQuizNotExisted
+	}
+
+	err, entries, total := q.dep.LeaderBoard.GetPage(ctx, quizID, pageIndex, pageSize)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	if quiz.Status == model.QuizStatusFinished {
-		return nil, pb.Error_ERROR_QUIZ_FINISHED
+	return entries, total, nil
+}
+
+// GetLeaderBoardRanks is used by the websocket gateways to push the leader board to their clients.
+// withoutTop only reads the ranks of userIDs, the top and the total are left empty.
+func (q *QuizManagerImpl) GetLeaderBoardRanks(
+	ctx context.Context, quizID int64, topN int32, userIDs []int64, withoutTop bool,
+) (*LeaderBoardRanks, error) {
+	if topN == 0 {
+		topN = defaultLeaderBoardTopN
+	}
+	if topN < 0 || topN > maxLeaderBoardPageSize || len(userIDs) > maxLeaderBoardRankUsers {
+		return nil, quiz_error.Newf(pb.Error_ERROR_INVALID_PARAMETER,
+			"top_n must be at most %d and user_ids at most %d", maxLeaderBoardPageSize, maxLeaderBoardRankUsers)
 	}
 
-	err, quizParticipant := q.dep.QuizDAO.FindQuizParticipant(ctx, quizID, userID)
+	err, quiz := q.dep.QuizDAO.FindQuizByID(ctx, quizID)
 	if err != nil {
-		return err, 0
+		return nil, err
 	}
 
-	if quizParticipant != nil {
-		return nil, pb.Error_ERROR_USER_JOINED
+	if quiz == nil {
+		return nil, quiz_error.ErrQuizNotExisted
 	}
 
-	err, _ = q.dep.QuizDAO.CreateQuizParticipant(ctx, quizID, userID)
+	result := &LeaderBoardRanks{}
+	if !withoutTop {
+		err, result.Top, result.Total = q.dep.LeaderBoard.GetPage(ctx, quizID, 0, topN)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err, result.Ranks = q.dep.LeaderBoard.GetRanks(ctx, quizID, userIDs)
 	if err != nil {
-		return err, 0
+		return nil, err
 	}
 
-	q.sendLeaderBoardChangedMessage(ctx, quizID)
+	return result, nil
+}
 
-	return nil, pb.Error_ERROR_OK
+// TransitQuiz moves a quiz to another status of its lifecycle, a quiz is only scheduled with
+// a start_time in the future and an end_time after it
+func (q *QuizManagerImpl) TransitQuiz(ctx context.Context, quizID int64, toStatus int32) error {
+	err, quiz := q.dep.QuizDAO.FindQuizByIDFromMaster(ctx, quizID)
+	if err != nil {
+		return err
+	}
+
+	if quiz == nil {
+		return quiz_error.ErrQuizNotExisted
+	}
+
+	if !CanTransitQuiz(quiz.Status, toStatus) {
+		return quiz_error.ErrInvalidQuizStatus
+	}
+
+	if toStatus == model.QuizStatusScheduled &&
+		(quiz.StartTime <= time.Now().UnixMilli() || quiz.EndTime <= quiz.StartTime) {
+		return quiz_error.Newf(pb.Error_ERROR_INVALID_PARAMETER, "start_time must be in the future and before end_time")
+	}
+
+	err, updated := transitQuiz(ctx, q.dep, quiz, toStatus)
+	if err != nil {
+		return err
+	}
+
+	if !updated {
+		return quiz_error.ErrInvalidQuizStatus
+	}
+
+	return nil
 }
 
-func (n *QuizManagerImpl) HandleNewScoreChange(ctx context.Context, quizID int64) error {
-	//implement here
+// HandleParticipantEvents applies the joined and score changed events of a quiz, in their order, to the
+// cached leader board. The leader board is recomputed from database when an event has no participant state,
+// e.g. it was published by an older server, or when an earlier change is missing from the cache.
+func (q *QuizManagerImpl) HandleParticipantEvents(ctx context.Context, quizID int64, events []*pb.QuizEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	changes := make([]*LeaderBoardChange, 0, len(events))
+	for _, event := range events {
+		state := event.GetParticipant()
+		if state == nil {
+			return q.dep.LeaderBoard.Rebuild(ctx, quizID)
+		}
+		changes = append(changes, &LeaderBoardChange{
+			UserID:      event.UserId,
+			Delta:       event.Delta,
+			Score:       state.Score,
+			UpdatedTime: state.UpdatedTime,
+		})
+	}
+
+	err, quiz := q.dep.QuizDAO.FindQuizByID(ctx, quizID)
+	if err != nil {
+		return err
+	}
+	if quiz == nil {
+		return nil
+	}
+
+	err, applied := q.dep.LeaderBoard.ApplyChanges(ctx, quiz, changes)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		log.Infof(ctx, "HandleParticipantEvents|quiz_id:%v|events:%d|gap, rebuilding", quizID, len(events))
+		return q.dep.LeaderBoard.Rebuild(ctx, quizID)
+	}
+	return nil
+}
+
+// HandleStatusChanged recomputes the leader board from database when the quiz starts, an early start moves
+// the start_time of the tie-break, and the final leader board when the quiz finishes
+func (q *QuizManagerImpl) HandleStatusChanged(ctx context.Context, event *pb.QuizEvent) error {
+	if event.ToStatus != model.QuizStatusFinished &&
+		!(event.FromStatus == model.QuizStatusLobby && event.ToStatus == model.QuizStatusInProgress) {
+		return nil
+	}
+	return q.dep.LeaderBoard.Rebuild(ctx, event.QuizId)
+}
+
+// updateLeaderBoard is best effort, the leader board is rebuilt by the score changed consumer anyway
+func (q *QuizManagerImpl) updateLeaderBoard(ctx context.Context, quiz *model.QuizTab, participant *model.QuizParticipantTab) {
+	err := q.dep.LeaderBoard.UpdateScore(ctx, quiz, participant)
+	if err != nil {
+		log.Errorff(ctx, "updateLeaderBoard|quiz_id:%v|user_id:%v|err:%v", participant.QuizID, participant.UserID, err)
+	}
+}
+
+// listStartedQuizQuestions reads the questions of a quiz in progress through the cache, the questions of
+// a quiz are only edited while it is a draft, and a started quiz never goes back to draft
+func (q *QuizManagerImpl) listStartedQuizQuestions(ctx context.Context, quiz *model.QuizTab) (error, []*model.QuizQuestionTab) {
+	if q.questionsCache == nil || quiz.Status != model.QuizStatusInProgress {
+		return q.dep.QuizDAO.ListQuizQuestions(ctx, quiz.ID)
+	}
+	result, err := cache_wrapper.WithCache(q.questionsCache, quiz.ID, func(input interface{}) (interface{}, error) {
+		err, questions := q.dep.QuizDAO.ListQuizQuestions(ctx, input.(int64))
+		if err != nil {
+			return nil, err
+		}
+		return &quizQuestions{Questions: questions}, nil
+	})
+	if err != nil {
+		return err, nil
+	}
+	return nil, result.(*quizQuestions).Questions
+}
+
+func findQuestion(questions []*model.QuizQuestionTab, questionID int64) *model.QuizQuestionTab {
+	for _, question := range questions {
+		if question.ID == questionID {
+			return question
+		}
+	}
 	return nil
 }
 
-func (q *QuizManagerImpl) sendLeaderBoardChangedMessage(ctx context.Context, quizID int64) {
-	//todo send message to kafka then notify new members join
+// participantMessages publishes the event of a participant change with the participant written by the change
+func (q *QuizManagerImpl) participantMessages(eventType pb.QuizEventType, delta int32) ParticipantMessages {
+	return func(participant *model.QuizParticipantTab) ([]*model.QuizOutboxTab, error) {
+		message, err := NewOutboxMessage(q.dep.QuizEventTopic(), NewParticipantEvent(eventType, participant, delta))
+		if err != nil {
+			return nil, err
+		}
+		return []*model.QuizOutboxTab{message}, nil
+	}
 }
 
-func (q *QuizManagerImpl) checkUserExited(userID int64) bool {
-	//todo check user existed by calling account service
-	return true
+func (q *QuizManagerImpl) checkUserExited(ctx context.Context, userID int64) (error, bool) {
+	if q.dep.Accounts == nil {
+		return nil, true
+	}
+	return q.dep.Accounts.UserExisted(ctx, userID)
 }
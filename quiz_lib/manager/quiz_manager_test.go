@@ -0,0 +1,154 @@
+package manager_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/luulethe/quiz/go_common/cache"
+	"github.com/luulethe/quiz/quiz_lib/db/model"
+	"github.com/luulethe/quiz/quiz_lib/manager"
+	"github.com/luulethe/quiz/quiz_lib/manager/managertest"
+	"github.com/luulethe/quiz/quiz_lib/quiz_error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingDAO counts the reads of the question sets
+type countingDAO struct {
+	*managertest.QuizDAO
+	listQuestions int32
+}
+
+func (d *countingDAO) ListQuizQuestions(ctx context.Context, quizID int64) (error, []*model.QuizQuestionTab) {
+	atomic.AddInt32(&d.listQuestions, 1)
+	return d.QuizDAO.ListQuizQuestions(ctx, quizID)
+}
+
+func newTestDependency(t *testing.T, dao manager.QuizDAO) *manager.Dependency {
+	server := miniredis.RunT(t)
+	redisCache, err := cache.NewRedisClient(server.Addr(), &cache.RedisOption{})
+	require.NoError(t, err)
+	t.Cleanup(func() { redisCache.Close() })
+
+	dep := &manager.Dependency{Cache: redisCache, QuizDAO: dao}
+	dep.QuizManager = manager.NewQuizManager(dep)
+	dep.LeaderBoard = manager.NewLeaderBoard(dep)
+	return dep
+}
+
+// createQuiz creates a quiz in progress with a question of 10 points, it returns the quiz, the question and its correct answer
+func createQuiz(t *testing.T, dao *managertest.QuizDAO) (*model.QuizTab, *model.QuizQuestionTab, *model.QuizAnswerOptionTab) {
+	now := time.Now().UnixMilli()
+	err, quiz := dao.CreateQuiz(context.Background(), &model.QuizTab{
+		Status:    model.QuizStatusInProgress,
+		Name:      "quiz",
+		StartTime: now - 1000,
+		EndTime:   now + int64(time.Hour/time.Millisecond),
+	})
+	require.NoError(t, err)
+	correct := &model.QuizAnswerOptionTab{Content: "right", IsCorrect: true}
+	question := dao.AddQuestion(
+		&model.QuizQuestionTab{QuizID: quiz.ID, Content: "question", Score: 10, Seq: 1},
+		correct, &model.QuizAnswerOptionTab{Content: "wrong"},
+	)
+	return quiz, question, correct
+}
+
+func TestJoinQuiz_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	dao := managertest.NewQuizDAO()
+	dep := newTestDependency(t, dao)
+	quiz, _, _ := createQuiz(t, dao)
+
+	const joins = 20
+	errs := make([]error, joins)
+	start := make(chan struct{})
+	wg := sync.WaitGroup{}
+	for i := 0; i < joins; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			errs[i] = dep.QuizManager.JoinQuiz(ctx, quiz.ID, 1)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	joined := 0
+	for _, err := range errs {
+		if err == nil {
+			joined++
+			continue
+		}
+		assert.True(t, errors.Is(err, quiz_error.ErrUserJoined), "err: %v", err)
+	}
+	assert.Equal(t, 1, joined)
+	assert.Len(t, dao.Participants(quiz.ID), 1)
+	assert.Len(t, dao.OutboxMessages(), 1)
+}
+
+func TestPauseResumeQuiz(t *testing.T) {
+	ctx := context.Background()
+	dao := managertest.NewQuizDAO()
+	dep := newTestDependency(t, dao)
+	quiz, question, correct := createQuiz(t, dao)
+	require.NoError(t, dep.QuizManager.JoinQuiz(ctx, quiz.ID, 1))
+
+	require.NoError(t, dep.QuizManager.PauseQuiz(ctx, quiz.ID))
+	_, paused := dao.FindQuizByID(ctx, quiz.ID)
+	assert.Equal(t, int32(model.QuizStatusPaused), paused.Status)
+	assert.NotZero(t, paused.PausedTime)
+
+	_, err := dep.QuizManager.SubmitAnswer(ctx, quiz.ID, 1, question.ID, correct.ID)
+	assert.True(t, errors.Is(err, quiz_error.ErrQuizPaused))
+	assert.True(t, errors.Is(dep.QuizManager.PauseQuiz(ctx, quiz.ID), quiz_error.ErrInvalidQuizStatus))
+
+	time.Sleep(5 * time.Millisecond)
+	require.NoError(t, dep.QuizManager.ResumeQuiz(ctx, quiz.ID))
+	_, resumed := dao.FindQuizByID(ctx, quiz.ID)
+	assert.Equal(t, int32(model.QuizStatusInProgress), resumed.Status)
+	assert.Zero(t, resumed.PausedTime)
+	assert.GreaterOrEqual(t, resumed.PausedDuration, int64(5))
+	assert.Equal(t, quiz.EndTime+resumed.PausedDuration, resumed.EndTime)
+
+	result, err := dep.QuizManager.SubmitAnswer(ctx, quiz.ID, 1, question.ID, correct.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int32(10), result.TotalScore)
+	assert.True(t, errors.Is(dep.QuizManager.ResumeQuiz(ctx, quiz.ID), quiz_error.ErrInvalidQuizStatus))
+}
+
+func TestResumeQuiz_NotPaused(t *testing.T) {
+	ctx := context.Background()
+	dao := managertest.NewQuizDAO()
+	dep := newTestDependency(t, dao)
+	err, quiz := dao.CreateQuiz(ctx, &model.QuizTab{Status: model.QuizStatusLobby, Name: "quiz"})
+	require.NoError(t, err)
+
+	// a quiz in the lobby could move in progress, but it's not resumed
+	assert.True(t, errors.Is(dep.QuizManager.ResumeQuiz(ctx, quiz.ID), quiz_error.ErrInvalidQuizStatus))
+	assert.True(t, errors.Is(dep.QuizManager.ResumeQuiz(ctx, quiz.ID+100), quiz_error.ErrQuizNotExisted))
+}
+
+func TestSubmitAnswer_CachesQuestions(t *testing.T) {
+	ctx := context.Background()
+	dao := &countingDAO{QuizDAO: managertest.NewQuizDAO()}
+	dep := newTestDependency(t, dao)
+	quiz, question, correct := createQuiz(t, dao.QuizDAO)
+
+	for userID := int64(1); userID <= 3; userID++ {
+		require.NoError(t, dep.QuizManager.JoinQuiz(ctx, quiz.ID, userID))
+		result, err := dep.QuizManager.SubmitAnswer(ctx, quiz.ID, userID, question.ID, correct.ID)
+		require.NoError(t, err)
+		assert.True(t, result.Correct)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&dao.listQuestions))
+
+	_, err := dep.QuizManager.SubmitAnswer(ctx, quiz.ID, 1, question.ID+100, correct.ID)
+	assert.True(t, errors.Is(err, quiz_error.ErrQuestionNotExisted))
+}
@@ -0,0 +1,130 @@
+package manager
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/luulethe/quiz/go_common/log"
+	"github.com/luulethe/quiz/go_common/metrics"
+	"github.com/luulethe/quiz/quiz_lib/db/model"
+)
+
+const (
+	quizSchedulerAction       = "QuizScheduler"
+	quizSchedulerLockKey      = "quiz_scheduler_lock"
+	quizSchedulerLockExpire   = 30 * time.Second
+	defaultSchedulerInterval  = time.Second
+	defaultSchedulerBatchSize = 100
+	defaultQuizLobbyDuration  = 5 * time.Minute
+	defaultQuizArchiveAfter   = 7 * 24 * time.Hour
+)
+
+// QuizScheduler moves the quizzes along their lifecycle when their time comes:
+// scheduled -> lobby -> in progress -> finished -> archived.
+// Only the instance holding the redis lock schedules, every transition emits a QUIZ_EVENT_STATUS_CHANGED.
+type QuizScheduler struct {
+	dep           *Dependency
+	lock          *redisLock
+	pollInterval  time.Duration
+	batchSize     int
+	lobbyDuration time.Duration
+	archiveAfter  time.Duration
+}
+
+func NewQuizScheduler(dep *Dependency) *QuizScheduler {
+	scheduler := &QuizScheduler{
+		dep:           dep,
+		lock:          newRedisLock(dep.Cache, quizSchedulerLockKey, quizSchedulerLockExpire),
+		pollInterval:  defaultSchedulerInterval,
+		batchSize:     defaultSchedulerBatchSize,
+		lobbyDuration: defaultQuizLobbyDuration,
+		archiveAfter:  defaultQuizArchiveAfter,
+	}
+	if dep.Conf != nil {
+		conf := dep.Conf.Scheduler
+		if conf.PollInterval > 0 {
+			scheduler.pollInterval = conf.PollInterval
+		}
+		if conf.BatchSize > 0 {
+			scheduler.batchSize = conf.BatchSize
+		}
+		if conf.LobbyDuration > 0 {
+			scheduler.lobbyDuration = conf.LobbyDuration
+		}
+		if conf.ArchiveAfter > 0 {
+			scheduler.archiveAfter = conf.ArchiveAfter
+		}
+	}
+	return scheduler
+}
+
+// Run starts the scheduler until ctx is cancelled
+func (s *QuizScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(s.pollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if s.lock.hold() {
+					s.schedule(ctx)
+				}
+			}
+		}
+	}()
+}
+
+// schedule applies the transitions in lifecycle order, so a quiz late by several steps catches up in one run
+func (s *QuizScheduler) schedule(ctx context.Context) {
+	now := time.Now()
+	s.transitDue(ctx, model.QuizStatusScheduled, model.QuizStatusLobby, func() (error, []*model.QuizTab) {
+		return s.dep.QuizDAO.ListQuizzesToStart(ctx, model.QuizStatusScheduled, now.Add(s.lobbyDuration).UnixMilli(), s.batchSize)
+	})
+	s.transitDue(ctx, model.QuizStatusLobby, model.QuizStatusInProgress, func() (error, []*model.QuizTab) {
+		return s.dep.QuizDAO.ListQuizzesToStart(ctx, model.QuizStatusLobby, now.UnixMilli(), s.batchSize)
+	})
+	s.transitDue(ctx, model.QuizStatusInProgress, model.QuizStatusFinished, func() (error, []*model.QuizTab) {
+		return s.dep.QuizDAO.ListQuizzesToEnd(ctx, model.QuizStatusInProgress, now.UnixMilli(), s.batchSize)
+	})
+	s.transitDue(ctx, model.QuizStatusFinished, model.QuizStatusArchived, func() (error, []*model.QuizTab) {
+		return s.dep.QuizDAO.ListQuizzesToEnd(ctx, model.QuizStatusFinished, now.Add(-s.archiveAfter).UnixMilli(), s.batchSize)
+	})
+}
+
+func (s *QuizScheduler) transitDue(
+	ctx context.Context, fromStatus int32, toStatus int32, listDue func() (error, []*model.QuizTab),
+) {
+	for ctx.Err() == nil {
+		err, quizzes := listDue()
+		if err != nil {
+			log.Errorff(ctx, "QuizScheduler.transitDue|from:%v|to:%v|err:%v", fromStatus, toStatus, err)
+			return
+		}
+
+		for _, quiz := range quizzes {
+			err, _ := transitQuiz(ctx, s.dep, quiz, toStatus)
+			if err != nil {
+				// the quiz stays due, stop here instead of listing it again
+				log.Errorff(ctx, "QuizScheduler.transitDue|quiz_id:%v|to:%v|err:%v", quiz.ID, toStatus, err)
+				s.reportCount(metrics.ResultError)
+				return
+			}
+			s.reportCount(metrics.ResultSuccess)
+		}
+		if len(quizzes) < s.batchSize {
+			return
+		}
+	}
+}
+
+func (s *QuizScheduler) reportCount(result metrics.ResultType) {
+	if s.dep.Stats != nil {
+		s.dep.Stats.ReportCount(1, quizSchedulerAction, string(result))
+	}
+}
@@ -0,0 +1,153 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/luulethe/quiz/config"
+	"github.com/luulethe/quiz/go_common/cache"
+	"github.com/luulethe/quiz/go_common/log"
+)
+
+const (
+	RateLimitBackendMemory = "memory"
+	RateLimitBackendRedis  = "redis"
+
+	rateLimitKeyFormat     = "quiz_rate_limit_%s"
+	memoryBucketsSweepTime = time.Minute
+
+	// takeTokenScript refills the bucket KEYS[1] by ARGV[1] tokens per second up to ARGV[2] since its last
+	// call at ARGV[3] milliseconds, then takes a token if any. The bucket expires once it would be full again.
+	takeTokenScript = `
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(bucket[1])
+local ts = tonumber(bucket[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate / 1000)
+local allowed = '0'
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = '1'
+end
+redis.call('HMSET', KEYS[1], 'tokens', tokens, 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst * 1000 / rate) + 1000)
+return allowed`
+)
+
+// RateLimiter takes a token from the bucket of the key, a limit with a zero rate is unlimited
+type RateLimiter interface {
+	Allow(ctx context.Context, key string, limit config.RateLimit) (error, bool)
+}
+
+// NewRateLimiter returns the limiter of the backend of the config, nil when rate limiting is disabled
+func NewRateLimiter(conf config.RateLimitConfig, cache cache.EnhancedCache) (RateLimiter, error) {
+	switch conf.Backend {
+	case "":
+		return nil, nil
+	case RateLimitBackendMemory:
+		return NewMemoryRateLimiter(), nil
+	case RateLimitBackendRedis:
+		return NewRedisRateLimiter(cache), nil
+	}
+	return nil, fmt.Errorf("unknown rate limit backend %q", conf.Backend)
+}
+
+// burst is the bucket size, at least one token
+func burst(limit config.RateLimit) float64 {
+	return math.Max(1, float64(limit.Burst))
+}
+
+type memoryBucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Time // when the bucket is full again, it can be dropped from then
+}
+
+// MemoryRateLimiter keeps the buckets in the process, so the limits apply per server
+type MemoryRateLimiter struct {
+	mutex     sync.Mutex
+	buckets   map[string]*memoryBucket
+	lastSweep time.Time
+}
+
+func NewMemoryRateLimiter() *MemoryRateLimiter {
+	return &MemoryRateLimiter{buckets: map[string]*memoryBucket{}, lastSweep: time.Now()}
+}
+
+func (l *MemoryRateLimiter) Allow(ctx context.Context, key string, limit config.RateLimit) (error, bool) {
+	if limit.Rate <= 0 {
+		return nil, true
+	}
+	size := burst(limit)
+	now := time.Now()
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.sweep(now)
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &memoryBucket{tokens: size, last: now}
+		l.buckets[key] = bucket
+	}
+	bucket.tokens = math.Min(size, bucket.tokens+now.Sub(bucket.last).Seconds()*limit.Rate)
+	bucket.last = now
+	allowed := bucket.tokens >= 1
+	if allowed {
+		bucket.tokens--
+	}
+	bucket.full = now.Add(time.Duration((size - bucket.tokens) / limit.Rate * float64(time.Second)))
+	return nil, allowed
+}
+
+// sweep drops the full buckets, which behave like missing ones
+func (l *MemoryRateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < memoryBucketsSweepTime {
+		return
+	}
+	l.lastSweep = now
+	for key, bucket := range l.buckets {
+		if !now.Before(bucket.full) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// RedisRateLimiter keeps the buckets in redis, so the limits are shared by all servers
+type RedisRateLimiter struct {
+	cache cache.EnhancedCache
+}
+
+func NewRedisRateLimiter(cache cache.EnhancedCache) *RedisRateLimiter {
+	return &RedisRateLimiter{cache: cache}
+}
+
+func (l *RedisRateLimiter) Allow(ctx context.Context, key string, limit config.RateLimit) (error, bool) {
+	if limit.Rate <= 0 {
+		return nil, true
+	}
+	allowed, err := l.cache.Eval(
+		takeTokenScript,
+		[]string{fmt.Sprintf(rateLimitKeyFormat, key)},
+		[]string{
+			strconv.FormatFloat(limit.Rate, 'f', -1, 64),
+			strconv.FormatFloat(burst(limit), 'f', -1, 64),
+			strconv.FormatInt(time.Now().UnixMilli(), 10),
+		},
+	)
+	if err != nil {
+		log.Errorff(ctx, "RedisRateLimiter.Allow|key:%v|err:%v", key, err)
+		return err, false
+	}
+	return nil, allowed == "1"
+}
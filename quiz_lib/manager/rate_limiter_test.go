@@ -0,0 +1,62 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/luulethe/quiz/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func allowed(t *testing.T, limiter RateLimiter, key string, limit config.RateLimit, count int) int {
+	result := 0
+	for i := 0; i < count; i++ {
+		err, ok := limiter.Allow(context.Background(), key, limit)
+		require.NoError(t, err)
+		if ok {
+			result++
+		}
+	}
+	return result
+}
+
+func TestMemoryRateLimiter_Burst(t *testing.T) {
+	limiter := NewMemoryRateLimiter()
+
+	// a full bucket lets burst requests through at once, per key
+	assert.Equal(t, 5, allowed(t, limiter, "user_1", config.RateLimit{Rate: 1, Burst: 5}, 10))
+	assert.Equal(t, 5, allowed(t, limiter, "user_2", config.RateLimit{Rate: 1, Burst: 5}, 10))
+	// the bucket holds at least one token, a zero rate is unlimited
+	assert.Equal(t, 1, allowed(t, limiter, "user_3", config.RateLimit{Rate: 1}, 10))
+	assert.Equal(t, 10, allowed(t, limiter, "user_4", config.RateLimit{Burst: 1}, 10))
+}
+
+func TestMemoryRateLimiter_Refill(t *testing.T) {
+	limiter := NewMemoryRateLimiter()
+	limit := config.RateLimit{Rate: 100, Burst: 2}
+	assert.Equal(t, 2, allowed(t, limiter, "user_1", limit, 3))
+
+	// 100 tokens per second refill the bucket in 20ms, but never above the burst
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 2, allowed(t, limiter, "user_1", limit, 3))
+}
+
+func TestMemoryRateLimiter_Sweep(t *testing.T) {
+	limiter := NewMemoryRateLimiter()
+	assert.Equal(t, 1, allowed(t, limiter, "fast", config.RateLimit{Rate: 1000, Burst: 1}, 1))
+	assert.Equal(t, 1, allowed(t, limiter, "slow", config.RateLimit{Rate: 0.001, Burst: 1}, 1))
+	time.Sleep(5 * time.Millisecond)
+
+	// the sweep runs once a minute, it drops the buckets which are full again
+	assert.Equal(t, 1, allowed(t, limiter, "other", config.RateLimit{Rate: 1, Burst: 1}, 1))
+	assert.Len(t, limiter.buckets, 3)
+	limiter.lastSweep = time.Now().Add(-memoryBucketsSweepTime)
+	assert.Equal(t, 1, allowed(t, limiter, "another", config.RateLimit{Rate: 1, Burst: 1}, 1))
+	assert.Len(t, limiter.buckets, 3)
+	assert.NotContains(t, limiter.buckets, "fast")
+
+	// the slow bucket is still empty after the sweep
+	assert.Equal(t, 0, allowed(t, limiter, "slow", config.RateLimit{Rate: 0.001, Burst: 1}, 1))
+}
@@ -0,0 +1,61 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/luulethe/quiz/go_common/cache"
+	"github.com/luulethe/quiz/go_common/log"
+)
+
+const renewRedisLockScript = `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	return 'OK'
+end
+return ''`
+
+// redisLock elects a single instance for a background job, the holder renews the lock
+// on every hold call and loses it when it stops calling hold for the expire duration
+type redisLock struct {
+	cache  cache.EnhancedCache
+	key    string
+	owner  string
+	expire time.Duration
+}
+
+func newRedisLock(cache cache.EnhancedCache, key string, expire time.Duration) *redisLock {
+	hostname, _ := os.Hostname()
+	return &redisLock{
+		cache:  cache,
+		key:    key,
+		owner:  fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+		expire: expire,
+	}
+}
+
+// hold acquires or renews the lock, it returns true while this instance holds it
+func (l *redisLock) hold() bool {
+	acquired, err := l.cache.SetNX(l.key, l.owner, l.expire)
+	if err != nil {
+		log.Errorff(context.Background(), "redisLock.hold|key:%v|err:%v", l.key, err)
+		return false
+	}
+	if acquired {
+		return true
+	}
+
+	result, err := l.cache.Eval(
+		renewRedisLockScript,
+		[]string{l.key},
+		[]string{l.owner, strconv.FormatInt(l.expire.Milliseconds(), 10)},
+	)
+	if err != nil {
+		log.Errorff(context.Background(), "redisLock.hold|renew|key:%v|err:%v", l.key, err)
+		return false
+	}
+	return result == "OK"
+}
@@ -0,0 +1,51 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/luulethe/quiz/go_common/cache"
+	"github.com/luulethe/quiz/go_common/log"
+	"github.com/luulethe/quiz/quiz_lib/db"
+)
+
+const stickyMasterKeyFormat = "quiz_sticky_master_%s_%d" // database, user_id
+
+// RedisStickyStore keeps the sticky master windows of a database in redis, so that a user reads its writes
+// whichever server it reads through. A user is sticky while redis fails, its reads go to master.
+type RedisStickyStore struct {
+	cache    cache.EnhancedCache
+	database string
+	window   time.Duration
+}
+
+var _ db.StickyStore = (*RedisStickyStore)(nil)
+
+func NewRedisStickyStore(cache cache.EnhancedCache, database string, window time.Duration) *RedisStickyStore {
+	if window <= 0 {
+		window = db.DefaultStickyMasterWindow
+	}
+	return &RedisStickyStore{cache: cache, database: database, window: window}
+}
+
+func (s *RedisStickyStore) key(userID int64) string {
+	return fmt.Sprintf(stickyMasterKeyFormat, s.database, userID)
+}
+
+func (s *RedisStickyStore) MarkWrite(ctx context.Context, userID int64) {
+	key := s.key(userID)
+	if err := s.cache.Set(key, "1", s.window); err != nil {
+		log.Errorff(ctx, "RedisStickyStore.MarkWrite|key:%v|err:%v", key, err)
+	}
+}
+
+func (s *RedisStickyStore) IsSticky(ctx context.Context, userID int64) bool {
+	key := s.key(userID)
+	existed, err := s.cache.Exists(key)
+	if err != nil {
+		log.Errorff(ctx, "RedisStickyStore.IsSticky|key:%v|err:%v", key, err)
+		return true
+	}
+	return existed > 0
+}
@@ -0,0 +1,39 @@
+package manager_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/luulethe/quiz/go_common/cache"
+	"github.com/luulethe/quiz/quiz_lib/manager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisStickyStore(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	redisCache, err := cache.NewRedisClient(server.Addr(), &cache.RedisOption{})
+	require.NoError(t, err)
+	t.Cleanup(func() { redisCache.Close() })
+
+	// the stores of two servers on the same database, and of another database
+	writer := manager.NewRedisStickyStore(redisCache, "quiz", time.Second)
+	reader := manager.NewRedisStickyStore(redisCache, "quiz", time.Second)
+	other := manager.NewRedisStickyStore(redisCache, "quiz_1", time.Second)
+
+	assert.False(t, reader.IsSticky(ctx, 1))
+	writer.MarkWrite(ctx, 1)
+	assert.True(t, reader.IsSticky(ctx, 1))
+	assert.False(t, reader.IsSticky(ctx, 2))
+	assert.False(t, other.IsSticky(ctx, 1))
+
+	server.FastForward(time.Second)
+	assert.False(t, reader.IsSticky(ctx, 1))
+
+	// the reads go to master while redis fails
+	server.Close()
+	assert.True(t, reader.IsSticky(ctx, 2))
+}
@@ -23,19 +23,55 @@ const (
 type Command int32
 
 const (
-	Command_CMD_PING      Command = 0
-	Command_CMD_JOIN_QUIZ Command = 1
+	Command_CMD_PING                    Command = 0
+	Command_CMD_JOIN_QUIZ               Command = 1
+	Command_CMD_SUBMIT_ANSWER           Command = 2
+	Command_CMD_GET_LEADERBOARD         Command = 3
+	Command_CMD_GET_LEADERBOARD_RANKS   Command = 4
+	Command_CMD_ADMIN_CREATE_QUIZ       Command = 5
+	Command_CMD_ADMIN_UPDATE_QUIZ       Command = 6
+	Command_CMD_ADMIN_ADD_QUESTION      Command = 7
+	Command_CMD_ADMIN_REORDER_QUESTIONS Command = 8
+	Command_CMD_ADMIN_PUBLISH_QUIZ      Command = 9
+	Command_CMD_ADMIN_CLOSE_QUIZ        Command = 10
+	Command_CMD_ADMIN_DELETE_QUIZ       Command = 11
+	Command_CMD_ADMIN_PAUSE_QUIZ        Command = 12
+	Command_CMD_ADMIN_RESUME_QUIZ       Command = 13
 )
 
 // Enum value maps for Command.
 var (
 	Command_name = map[int32]string{
-		0: "CMD_PING",
-		1: "CMD_JOIN_QUIZ",
+		0:  "CMD_PING",
+		1:  "CMD_JOIN_QUIZ",
+		2:  "CMD_SUBMIT_ANSWER",
+		3:  "CMD_GET_LEADERBOARD",
+		4:  "CMD_GET_LEADERBOARD_RANKS",
+		5:  "CMD_ADMIN_CREATE_QUIZ",
+		6:  "CMD_ADMIN_UPDATE_QUIZ",
+		7:  "CMD_ADMIN_ADD_QUESTION",
+		8:  "CMD_ADMIN_REORDER_QUESTIONS",
+		9:  "CMD_ADMIN_PUBLISH_QUIZ",
+		10: "CMD_ADMIN_CLOSE_QUIZ",
+		11: "CMD_ADMIN_DELETE_QUIZ",
+		12: "CMD_ADMIN_PAUSE_QUIZ",
+		13: "CMD_ADMIN_RESUME_QUIZ",
 	}
 	Command_value = map[string]int32{
-		"CMD_PING":      0,
-		"CMD_JOIN_QUIZ": 1,
+		"CMD_PING":                    0,
+		"CMD_JOIN_QUIZ":               1,
+		"CMD_SUBMIT_ANSWER":           2,
+		"CMD_GET_LEADERBOARD":         3,
+		"CMD_GET_LEADERBOARD_RANKS":   4,
+		"CMD_ADMIN_CREATE_QUIZ":       5,
+		"CMD_ADMIN_UPDATE_QUIZ":       6,
+		"CMD_ADMIN_ADD_QUESTION":      7,
+		"CMD_ADMIN_REORDER_QUESTIONS": 8,
+		"CMD_ADMIN_PUBLISH_QUIZ":      9,
+		"CMD_ADMIN_CLOSE_QUIZ":        10,
+		"CMD_ADMIN_DELETE_QUIZ":       11,
+		"CMD_ADMIN_PAUSE_QUIZ":        12,
+		"CMD_ADMIN_RESUME_QUIZ":       13,
 	}
 )
 
@@ -69,28 +105,73 @@ func (Command) EnumDescriptor() ([]byte, []int) {
 type Error int32
 
 const (
-	Error_ERROR_OK               Error = 0
-	Error_ERROR_QUIZ_NOT_EXITED  Error = 1
-	Error_ERROR_QUIZ_FINISHED    Error = 2
-	Error_ERROR_USER_JOINED      Error = 3
-	Error_ERROR_USER_NOT_EXISTED Error = 4
+	Error_ERROR_OK                   Error = 0
+	Error_ERROR_QUIZ_NOT_EXITED      Error = 1
+	Error_ERROR_QUIZ_FINISHED        Error = 2
+	Error_ERROR_USER_JOINED          Error = 3
+	Error_ERROR_USER_NOT_EXISTED     Error = 4
+	Error_ERROR_USER_NOT_JOINED      Error = 5
+	Error_ERROR_QUESTION_NOT_EXISTED Error = 6
+	Error_ERROR_ANSWER_NOT_EXISTED   Error = 7
+	Error_ERROR_ANSWER_SUBMITTED     Error = 8
+	Error_ERROR_INVALID_PARAMETER    Error = 9
+	Error_ERROR_INTERNAL             Error = 10
+	Error_ERROR_QUIZ_NOT_STARTED     Error = 11
+	Error_ERROR_QUIZ_PAUSED          Error = 12
+	Error_ERROR_QUESTION_CLOSED      Error = 13
+	Error_ERROR_INVALID_QUIZ_STATUS  Error = 14
+	Error_ERROR_PERMISSION_DENIED    Error = 15
+	Error_ERROR_UNAUTHENTICATED      Error = 16
+	Error_ERROR_RATE_LIMITED         Error = 17
+	Error_ERROR_REQUEST_IN_PROGRESS  Error = 18
+	Error_ERROR_SERVICE_UNAVAILABLE  Error = 19
 )
 
 // Enum value maps for Error.
 var (
 	Error_name = map[int32]string{
-		0: "ERROR_OK",
-		1: "ERROR_QUIZ_NOT_EXITED",
-		2: "ERROR_QUIZ_FINISHED",
-		3: "ERROR_USER_JOINED",
-		4: "ERROR_USER_NOT_EXISTED",
+		0:  "ERROR_OK",
+		1:  "ERROR_QUIZ_NOT_EXITED",
+		2:  "ERROR_QUIZ_FINISHED",
+		3:  "ERROR_USER_JOINED",
+		4:  "ERROR_USER_NOT_EXISTED",
+		5:  "ERROR_USER_NOT_JOINED",
+		6:  "ERROR_QUESTION_NOT_EXISTED",
+		7:  "ERROR_ANSWER_NOT_EXISTED",
+		8:  "ERROR_ANSWER_SUBMITTED",
+		9:  "ERROR_INVALID_PARAMETER",
+		10: "ERROR_INTERNAL",
+		11: "ERROR_QUIZ_NOT_STARTED",
+		12: "ERROR_QUIZ_PAUSED",
+		13: "ERROR_QUESTION_CLOSED",
+		14: "ERROR_INVALID_QUIZ_STATUS",
+		15: "ERROR_PERMISSION_DENIED",
+		16: "ERROR_UNAUTHENTICATED",
+		17: "ERROR_RATE_LIMITED",
+		18: "ERROR_REQUEST_IN_PROGRESS",
+		19: "ERROR_SERVICE_UNAVAILABLE",
 	}
 	Error_value = map[string]int32{
-		"ERROR_OK":               0,
-		"ERROR_QUIZ_NOT_EXITED":  1,
-		"ERROR_QUIZ_FINISHED":    2,
-		"ERROR_USER_JOINED":      3,
-		"ERROR_USER_NOT_EXISTED": 4,
+		"ERROR_OK":                   0,
+		"ERROR_QUIZ_NOT_EXITED":      1,
+		"ERROR_QUIZ_FINISHED":        2,
+		"ERROR_USER_JOINED":          3,
+		"ERROR_USER_NOT_EXISTED":     4,
+		"ERROR_USER_NOT_JOINED":      5,
+		"ERROR_QUESTION_NOT_EXISTED": 6,
+		"ERROR_ANSWER_NOT_EXISTED":   7,
+		"ERROR_ANSWER_SUBMITTED":     8,
+		"ERROR_INVALID_PARAMETER":    9,
+		"ERROR_INTERNAL":             10,
+		"ERROR_QUIZ_NOT_STARTED":     11,
+		"ERROR_QUIZ_PAUSED":          12,
+		"ERROR_QUESTION_CLOSED":      13,
+		"ERROR_INVALID_QUIZ_STATUS":  14,
+		"ERROR_PERMISSION_DENIED":    15,
+		"ERROR_UNAUTHENTICATED":      16,
+		"ERROR_RATE_LIMITED":         17,
+		"ERROR_REQUEST_IN_PROGRESS":  18,
+		"ERROR_SERVICE_UNAVAILABLE":  19,
 	}
 )
 
@@ -125,19 +206,65 @@ var File_const_proto protoreflect.FileDescriptor
 
 var file_const_proto_rawDesc = []byte{
 	0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x63,
-	0x6f, 0x6e, 0x73, 0x74, 0x2a, 0x2a, 0x0a, 0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12,
-	0x0c, 0x0a, 0x08, 0x43, 0x4d, 0x44, 0x5f, 0x50, 0x49, 0x4e, 0x47, 0x10, 0x00, 0x12, 0x11, 0x0a,
-	0x0d, 0x43, 0x4d, 0x44, 0x5f, 0x4a, 0x4f, 0x49, 0x4e, 0x5f, 0x51, 0x55, 0x49, 0x5a, 0x10, 0x01,
-	0x2a, 0x7c, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x0c, 0x0a, 0x08, 0x45, 0x52, 0x52,
-	0x4f, 0x52, 0x5f, 0x4f, 0x4b, 0x10, 0x00, 0x12, 0x19, 0x0a, 0x15, 0x45, 0x52, 0x52, 0x4f, 0x52,
-	0x5f, 0x51, 0x55, 0x49, 0x5a, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x45, 0x58, 0x49, 0x54, 0x45, 0x44,
-	0x10, 0x01, 0x12, 0x17, 0x0a, 0x13, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x51, 0x55, 0x49, 0x5a,
-	0x5f, 0x46, 0x49, 0x4e, 0x49, 0x53, 0x48, 0x45, 0x44, 0x10, 0x02, 0x12, 0x15, 0x0a, 0x11, 0x45,
-	0x52, 0x52, 0x4f, 0x52, 0x5f, 0x55, 0x53, 0x45, 0x52, 0x5f, 0x4a, 0x4f, 0x49, 0x4e, 0x45, 0x44,
-	0x10, 0x03, 0x12, 0x1a, 0x0a, 0x16, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x55, 0x53, 0x45, 0x52,
-	0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x45, 0x58, 0x49, 0x53, 0x54, 0x45, 0x44, 0x10, 0x04, 0x42, 0x0d,
-	0x5a, 0x0b, 0x70, 0x62, 0x2f, 0x71, 0x75, 0x69, 0x7a, 0x5f, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6f, 0x6e, 0x73, 0x74, 0x2a, 0xf2, 0x02, 0x0a, 0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
+	0x12, 0x0c, 0x0a, 0x08, 0x43, 0x4d, 0x44, 0x5f, 0x50, 0x49, 0x4e, 0x47, 0x10, 0x00, 0x12, 0x11,
+	0x0a, 0x0d, 0x43, 0x4d, 0x44, 0x5f, 0x4a, 0x4f, 0x49, 0x4e, 0x5f, 0x51, 0x55, 0x49, 0x5a, 0x10,
+	0x01, 0x12, 0x15, 0x0a, 0x11, 0x43, 0x4d, 0x44, 0x5f, 0x53, 0x55, 0x42, 0x4d, 0x49, 0x54, 0x5f,
+	0x41, 0x4e, 0x53, 0x57, 0x45, 0x52, 0x10, 0x02, 0x12, 0x17, 0x0a, 0x13, 0x43, 0x4d, 0x44, 0x5f,
+	0x47, 0x45, 0x54, 0x5f, 0x4c, 0x45, 0x41, 0x44, 0x45, 0x52, 0x42, 0x4f, 0x41, 0x52, 0x44, 0x10,
+	0x03, 0x12, 0x1d, 0x0a, 0x19, 0x43, 0x4d, 0x44, 0x5f, 0x47, 0x45, 0x54, 0x5f, 0x4c, 0x45, 0x41,
+	0x44, 0x45, 0x52, 0x42, 0x4f, 0x41, 0x52, 0x44, 0x5f, 0x52, 0x41, 0x4e, 0x4b, 0x53, 0x10, 0x04,
+	0x12, 0x19, 0x0a, 0x15, 0x43, 0x4d, 0x44, 0x5f, 0x41, 0x44, 0x4d, 0x49, 0x4e, 0x5f, 0x43, 0x52,
+	0x45, 0x41, 0x54, 0x45, 0x5f, 0x51, 0x55, 0x49, 0x5a, 0x10, 0x05, 0x12, 0x19, 0x0a, 0x15, 0x43,
+	0x4d, 0x44, 0x5f, 0x41, 0x44, 0x4d, 0x49, 0x4e, 0x5f, 0x55, 0x50, 0x44, 0x41, 0x54, 0x45, 0x5f,
+	0x51, 0x55, 0x49, 0x5a, 0x10, 0x06, 0x12, 0x1a, 0x0a, 0x16, 0x43, 0x4d, 0x44, 0x5f, 0x41, 0x44,
+	0x4d, 0x49, 0x4e, 0x5f, 0x41, 0x44, 0x44, 0x5f, 0x51, 0x55, 0x45, 0x53, 0x54, 0x49, 0x4f, 0x4e,
+	0x10, 0x07, 0x12, 0x1f, 0x0a, 0x1b, 0x43, 0x4d, 0x44, 0x5f, 0x41, 0x44, 0x4d, 0x49, 0x4e, 0x5f,
+	0x52, 0x45, 0x4f, 0x52, 0x44, 0x45, 0x52, 0x5f, 0x51, 0x55, 0x45, 0x53, 0x54, 0x49, 0x4f, 0x4e,
+	0x53, 0x10, 0x08, 0x12, 0x1a, 0x0a, 0x16, 0x43, 0x4d, 0x44, 0x5f, 0x41, 0x44, 0x4d, 0x49, 0x4e,
+	0x5f, 0x50, 0x55, 0x42, 0x4c, 0x49, 0x53, 0x48, 0x5f, 0x51, 0x55, 0x49, 0x5a, 0x10, 0x09, 0x12,
+	0x18, 0x0a, 0x14, 0x43, 0x4d, 0x44, 0x5f, 0x41, 0x44, 0x4d, 0x49, 0x4e, 0x5f, 0x43, 0x4c, 0x4f,
+	0x53, 0x45, 0x5f, 0x51, 0x55, 0x49, 0x5a, 0x10, 0x0a, 0x12, 0x19, 0x0a, 0x15, 0x43, 0x4d, 0x44,
+	0x5f, 0x41, 0x44, 0x4d, 0x49, 0x4e, 0x5f, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x45, 0x5f, 0x51, 0x55,
+	0x49, 0x5a, 0x10, 0x0b, 0x12, 0x18, 0x0a, 0x14, 0x43, 0x4d, 0x44, 0x5f, 0x41, 0x44, 0x4d, 0x49,
+	0x4e, 0x5f, 0x50, 0x41, 0x55, 0x53, 0x45, 0x5f, 0x51, 0x55, 0x49, 0x5a, 0x10, 0x0c, 0x12, 0x19,
+	0x0a, 0x15, 0x43, 0x4d, 0x44, 0x5f, 0x41, 0x44, 0x4d, 0x49, 0x4e, 0x5f, 0x52, 0x45, 0x53, 0x55,
+	0x4d, 0x45, 0x5f, 0x51, 0x55, 0x49, 0x5a, 0x10, 0x0d, 0x2a, 0x9d, 0x04, 0x0a, 0x05, 0x45, 0x72,
+	0x72, 0x6f, 0x72, 0x12, 0x0c, 0x0a, 0x08, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x4f, 0x4b, 0x10,
+	0x00, 0x12, 0x19, 0x0a, 0x15, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x51, 0x55, 0x49, 0x5a, 0x5f,
+	0x4e, 0x4f, 0x54, 0x5f, 0x45, 0x58, 0x49, 0x54, 0x45, 0x44, 0x10, 0x01, 0x12, 0x17, 0x0a, 0x13,
+	0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x51, 0x55, 0x49, 0x5a, 0x5f, 0x46, 0x49, 0x4e, 0x49, 0x53,
+	0x48, 0x45, 0x44, 0x10, 0x02, 0x12, 0x15, 0x0a, 0x11, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x55,
+	0x53, 0x45, 0x52, 0x5f, 0x4a, 0x4f, 0x49, 0x4e, 0x45, 0x44, 0x10, 0x03, 0x12, 0x1a, 0x0a, 0x16,
+	0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x55, 0x53, 0x45, 0x52, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x45,
+	0x58, 0x49, 0x53, 0x54, 0x45, 0x44, 0x10, 0x04, 0x12, 0x19, 0x0a, 0x15, 0x45, 0x52, 0x52, 0x4f,
+	0x52, 0x5f, 0x55, 0x53, 0x45, 0x52, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x4a, 0x4f, 0x49, 0x4e, 0x45,
+	0x44, 0x10, 0x05, 0x12, 0x1e, 0x0a, 0x1a, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x51, 0x55, 0x45,
+	0x53, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x45, 0x58, 0x49, 0x53, 0x54, 0x45,
+	0x44, 0x10, 0x06, 0x12, 0x1c, 0x0a, 0x18, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x41, 0x4e, 0x53,
+	0x57, 0x45, 0x52, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x45, 0x58, 0x49, 0x53, 0x54, 0x45, 0x44, 0x10,
+	0x07, 0x12, 0x1a, 0x0a, 0x16, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x41, 0x4e, 0x53, 0x57, 0x45,
+	0x52, 0x5f, 0x53, 0x55, 0x42, 0x4d, 0x49, 0x54, 0x54, 0x45, 0x44, 0x10, 0x08, 0x12, 0x1b, 0x0a,
+	0x17, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x50,
+	0x41, 0x52, 0x41, 0x4d, 0x45, 0x54, 0x45, 0x52, 0x10, 0x09, 0x12, 0x12, 0x0a, 0x0e, 0x45, 0x52,
+	0x52, 0x4f, 0x52, 0x5f, 0x49, 0x4e, 0x54, 0x45, 0x52, 0x4e, 0x41, 0x4c, 0x10, 0x0a, 0x12, 0x1a,
+	0x0a, 0x16, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x51, 0x55, 0x49, 0x5a, 0x5f, 0x4e, 0x4f, 0x54,
+	0x5f, 0x53, 0x54, 0x41, 0x52, 0x54, 0x45, 0x44, 0x10, 0x0b, 0x12, 0x15, 0x0a, 0x11, 0x45, 0x52,
+	0x52, 0x4f, 0x52, 0x5f, 0x51, 0x55, 0x49, 0x5a, 0x5f, 0x50, 0x41, 0x55, 0x53, 0x45, 0x44, 0x10,
+	0x0c, 0x12, 0x19, 0x0a, 0x15, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x51, 0x55, 0x45, 0x53, 0x54,
+	0x49, 0x4f, 0x4e, 0x5f, 0x43, 0x4c, 0x4f, 0x53, 0x45, 0x44, 0x10, 0x0d, 0x12, 0x1d, 0x0a, 0x19,
+	0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x51, 0x55,
+	0x49, 0x5a, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x10, 0x0e, 0x12, 0x1b, 0x0a, 0x17, 0x45,
+	0x52, 0x52, 0x4f, 0x52, 0x5f, 0x50, 0x45, 0x52, 0x4d, 0x49, 0x53, 0x53, 0x49, 0x4f, 0x4e, 0x5f,
+	0x44, 0x45, 0x4e, 0x49, 0x45, 0x44, 0x10, 0x0f, 0x12, 0x19, 0x0a, 0x15, 0x45, 0x52, 0x52, 0x4f,
+	0x52, 0x5f, 0x55, 0x4e, 0x41, 0x55, 0x54, 0x48, 0x45, 0x4e, 0x54, 0x49, 0x43, 0x41, 0x54, 0x45,
+	0x44, 0x10, 0x10, 0x12, 0x16, 0x0a, 0x12, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x52, 0x41, 0x54,
+	0x45, 0x5f, 0x4c, 0x49, 0x4d, 0x49, 0x54, 0x45, 0x44, 0x10, 0x11, 0x12, 0x1d, 0x0a, 0x19, 0x45,
+	0x52, 0x52, 0x4f, 0x52, 0x5f, 0x52, 0x45, 0x51, 0x55, 0x45, 0x53, 0x54, 0x5f, 0x49, 0x4e, 0x5f,
+	0x50, 0x52, 0x4f, 0x47, 0x52, 0x45, 0x53, 0x53, 0x10, 0x12, 0x12, 0x1d, 0x0a, 0x19, 0x45, 0x52,
+	0x52, 0x4f, 0x52, 0x5f, 0x53, 0x45, 0x52, 0x56, 0x49, 0x43, 0x45, 0x5f, 0x55, 0x4e, 0x41, 0x56,
+	0x41, 0x49, 0x4c, 0x41, 0x42, 0x4c, 0x45, 0x10, 0x13, 0x42, 0x0d, 0x5a, 0x0b, 0x70, 0x62, 0x2f,
+	0x71, 0x75, 0x69, 0x7a, 0x5f, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -20,19 +20,68 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// / ResultDetail is the detail of a QuizServiceV2 status error
+type ResultDetail struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result Error `protobuf:"varint,1,opt,name=result,proto3,enum=const.Error" json:"result,omitempty"`
+}
+
+func (x *ResultDetail) Reset() {
+	*x = ResultDetail{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_quiz_api_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResultDetail) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResultDetail) ProtoMessage() {}
+
+func (x *ResultDetail) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_api_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResultDetail.ProtoReflect.Descriptor instead.
+func (*ResultDetail) Descriptor() ([]byte, []int) {
+	return file_quiz_api_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ResultDetail) GetResult() Error {
+	if x != nil {
+		return x.Result
+	}
+	return Error_ERROR_OK
+}
+
 type RequestData struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Command Command `protobuf:"varint,1,opt,name=command,proto3,enum=const.Command" json:"command,omitempty"`
-	Request []byte  `protobuf:"bytes,2,opt,name=request,proto3" json:"request,omitempty"`
+	Command   Command `protobuf:"varint,1,opt,name=command,proto3,enum=const.Command" json:"command,omitempty"`
+	Request   []byte  `protobuf:"bytes,2,opt,name=request,proto3" json:"request,omitempty"`
+	RequestId string  `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"` // optional idempotency key, a retry with the same id gets the reply of the first request
 }
 
 func (x *RequestData) Reset() {
 	*x = RequestData{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_quiz_api_proto_msgTypes[0]
+		mi := &file_quiz_api_proto_msgTypes[1]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -45,7 +94,7 @@ func (x *RequestData) String() string {
 func (*RequestData) ProtoMessage() {}
 
 func (x *RequestData) ProtoReflect() protoreflect.Message {
-	mi := &file_quiz_api_proto_msgTypes[0]
+	mi := &file_quiz_api_proto_msgTypes[1]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -58,7 +107,7 @@ func (x *RequestData) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RequestData.ProtoReflect.Descriptor instead.
 func (*RequestData) Descriptor() ([]byte, []int) {
-	return file_quiz_api_proto_rawDescGZIP(), []int{0}
+	return file_quiz_api_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *RequestData) GetCommand() Command {
@@ -75,6 +124,13 @@ func (x *RequestData) GetRequest() []byte {
 	return nil
 }
 
+func (x *RequestData) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
 type ResponseData struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -87,7 +143,7 @@ type ResponseData struct {
 func (x *ResponseData) Reset() {
 	*x = ResponseData{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_quiz_api_proto_msgTypes[1]
+		mi := &file_quiz_api_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -100,7 +156,7 @@ func (x *ResponseData) String() string {
 func (*ResponseData) ProtoMessage() {}
 
 func (x *ResponseData) ProtoReflect() protoreflect.Message {
-	mi := &file_quiz_api_proto_msgTypes[1]
+	mi := &file_quiz_api_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -113,7 +169,7 @@ func (x *ResponseData) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ResponseData.ProtoReflect.Descriptor instead.
 func (*ResponseData) Descriptor() ([]byte, []int) {
-	return file_quiz_api_proto_rawDescGZIP(), []int{1}
+	return file_quiz_api_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *ResponseData) GetResult() Error {
@@ -136,14 +192,15 @@ type JoinQuizRequest struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	UserId int64 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Deprecated: Do not use.
+	UserId int64 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // ignored, the user is the authenticated caller
 	QuizId int64 `protobuf:"varint,2,opt,name=quiz_id,json=quizId,proto3" json:"quiz_id,omitempty"`
 }
 
 func (x *JoinQuizRequest) Reset() {
 	*x = JoinQuizRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_quiz_api_proto_msgTypes[2]
+		mi := &file_quiz_api_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -156,7 +213,7 @@ func (x *JoinQuizRequest) String() string {
 func (*JoinQuizRequest) ProtoMessage() {}
 
 func (x *JoinQuizRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_quiz_api_proto_msgTypes[2]
+	mi := &file_quiz_api_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -169,9 +226,10 @@ func (x *JoinQuizRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use JoinQuizRequest.ProtoReflect.Descriptor instead.
 func (*JoinQuizRequest) Descriptor() ([]byte, []int) {
-	return file_quiz_api_proto_rawDescGZIP(), []int{2}
+	return file_quiz_api_proto_rawDescGZIP(), []int{3}
 }
 
+// Deprecated: Do not use.
 func (x *JoinQuizRequest) GetUserId() int64 {
 	if x != nil {
 		return x.UserId
@@ -196,7 +254,7 @@ type JoinQuizRequestReply struct {
 func (x *JoinQuizRequestReply) Reset() {
 	*x = JoinQuizRequestReply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_quiz_api_proto_msgTypes[3]
+		mi := &file_quiz_api_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -209,7 +267,7 @@ func (x *JoinQuizRequestReply) String() string {
 func (*JoinQuizRequestReply) ProtoMessage() {}
 
 func (x *JoinQuizRequestReply) ProtoReflect() protoreflect.Message {
-	mi := &file_quiz_api_proto_msgTypes[3]
+	mi := &file_quiz_api_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -222,116 +280,2086 @@ func (x *JoinQuizRequestReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use JoinQuizRequestReply.ProtoReflect.Descriptor instead.
 func (*JoinQuizRequestReply) Descriptor() ([]byte, []int) {
-	return file_quiz_api_proto_rawDescGZIP(), []int{3}
+	return file_quiz_api_proto_rawDescGZIP(), []int{4}
 }
 
-var File_quiz_api_proto protoreflect.FileDescriptor
+// / CMD_SUBMIT_ANSWER request
+type SubmitAnswerRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-var file_quiz_api_proto_rawDesc = []byte{
-	0x0a, 0x0e, 0x71, 0x75, 0x69, 0x7a, 0x5f, 0x61, 0x70, 0x69, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x12, 0x04, 0x71, 0x75, 0x69, 0x7a, 0x1a, 0x0b, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x22, 0x51, 0x0a, 0x0b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x44, 0x61,
-	0x74, 0x61, 0x12, 0x28, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x2e, 0x43, 0x6f, 0x6d, 0x6d,
-	0x61, 0x6e, 0x64, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x18, 0x0a, 0x07,
-	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x72,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x50, 0x0a, 0x0c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x12, 0x24, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0c, 0x2e, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x2e, 0x45,
-	0x72, 0x72, 0x6f, 0x72, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x1a, 0x0a, 0x08,
-	0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08,
-	0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x43, 0x0a, 0x0f, 0x4a, 0x6f, 0x69, 0x6e,
-	0x51, 0x75, 0x69, 0x7a, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x75,
-	0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x75, 0x73,
-	0x65, 0x72, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x71, 0x75, 0x69, 0x7a, 0x5f, 0x69, 0x64, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x71, 0x75, 0x69, 0x7a, 0x49, 0x64, 0x22, 0x16, 0x0a,
-	0x14, 0x4a, 0x6f, 0x69, 0x6e, 0x51, 0x75, 0x69, 0x7a, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x52, 0x65, 0x70, 0x6c, 0x79, 0x32, 0x40, 0x0a, 0x0b, 0x51, 0x75, 0x69, 0x7a, 0x53, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x12, 0x31, 0x0a, 0x06, 0x48, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x12, 0x11,
-	0x2e, 0x71, 0x75, 0x69, 0x7a, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x44, 0x61, 0x74,
-	0x61, 0x1a, 0x12, 0x2e, 0x71, 0x75, 0x69, 0x7a, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x44, 0x61, 0x74, 0x61, 0x22, 0x00, 0x42, 0x0d, 0x5a, 0x0b, 0x70, 0x62, 0x2f, 0x71, 0x75,
-	0x69, 0x7a, 0x5f, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	// Deprecated: Do not use.
+	UserId     int64 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // ignored, the user is the authenticated caller
+	QuizId     int64 `protobuf:"varint,2,opt,name=quiz_id,json=quizId,proto3" json:"quiz_id,omitempty"`
+	QuestionId int64 `protobuf:"varint,3,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
+	AnswerId   int64 `protobuf:"varint,4,opt,name=answer_id,json=answerId,proto3" json:"answer_id,omitempty"`
 }
 
-var (
-	file_quiz_api_proto_rawDescOnce sync.Once
-	file_quiz_api_proto_rawDescData = file_quiz_api_proto_rawDesc
-)
+func (x *SubmitAnswerRequest) Reset() {
+	*x = SubmitAnswerRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_quiz_api_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
 
-func file_quiz_api_proto_rawDescGZIP() []byte {
-	file_quiz_api_proto_rawDescOnce.Do(func() {
-		file_quiz_api_proto_rawDescData = protoimpl.X.CompressGZIP(file_quiz_api_proto_rawDescData)
-	})
-	return file_quiz_api_proto_rawDescData
+func (x *SubmitAnswerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var file_quiz_api_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
-var file_quiz_api_proto_goTypes = []interface{}{
-	(*RequestData)(nil),          // 0: quiz.RequestData
-	(*ResponseData)(nil),         // 1: quiz.ResponseData
-	(*JoinQuizRequest)(nil),      // 2: quiz.JoinQuizRequest
-	(*JoinQuizRequestReply)(nil), // 3: quiz.JoinQuizRequestReply
-	(Command)(0),                 // 4: const.Command
-	(Error)(0),                   // 5: const.Error
+func (*SubmitAnswerRequest) ProtoMessage() {}
+
+func (x *SubmitAnswerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_api_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-var file_quiz_api_proto_depIdxs = []int32{
-	4, // 0: quiz.RequestData.command:type_name -> const.Command
-	5, // 1: quiz.ResponseData.result:type_name -> const.Error
-	0, // 2: quiz.QuizService.Handle:input_type -> quiz.RequestData
-	1, // 3: quiz.QuizService.Handle:output_type -> quiz.ResponseData
-	3, // [3:4] is the sub-list for method output_type
-	2, // [2:3] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+
+// Deprecated: Use SubmitAnswerRequest.ProtoReflect.Descriptor instead.
+func (*SubmitAnswerRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_api_proto_rawDescGZIP(), []int{5}
 }
 
-func init() { file_quiz_api_proto_init() }
-func file_quiz_api_proto_init() {
-	if File_quiz_api_proto != nil {
-		return
+// Deprecated: Do not use.
+func (x *SubmitAnswerRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
 	}
-	file_const_proto_init()
-	if !protoimpl.UnsafeEnabled {
-		file_quiz_api_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RequestData); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_quiz_api_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ResponseData); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+	return 0
+}
+
+func (x *SubmitAnswerRequest) GetQuizId() int64 {
+	if x != nil {
+		return x.QuizId
+	}
+	return 0
+}
+
+func (x *SubmitAnswerRequest) GetQuestionId() int64 {
+	if x != nil {
+		return x.QuestionId
+	}
+	return 0
+}
+
+func (x *SubmitAnswerRequest) GetAnswerId() int64 {
+	if x != nil {
+		return x.AnswerId
+	}
+	return 0
+}
+
+// / CMD_SUBMIT_ANSWER reply
+type SubmitAnswerReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Correct    bool  `protobuf:"varint,1,opt,name=correct,proto3" json:"correct,omitempty"`
+	Score      int32 `protobuf:"varint,2,opt,name=score,proto3" json:"score,omitempty"`
+	TotalScore int32 `protobuf:"varint,3,opt,name=total_score,json=totalScore,proto3" json:"total_score,omitempty"`
+}
+
+func (x *SubmitAnswerReply) Reset() {
+	*x = SubmitAnswerReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_quiz_api_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubmitAnswerReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitAnswerReply) ProtoMessage() {}
+
+func (x *SubmitAnswerReply) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_api_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_quiz_api_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*JoinQuizRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitAnswerReply.ProtoReflect.Descriptor instead.
+func (*SubmitAnswerReply) Descriptor() ([]byte, []int) {
+	return file_quiz_api_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SubmitAnswerReply) GetCorrect() bool {
+	if x != nil {
+		return x.Correct
+	}
+	return false
+}
+
+func (x *SubmitAnswerReply) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *SubmitAnswerReply) GetTotalScore() int32 {
+	if x != nil {
+		return x.TotalScore
+	}
+	return 0
+}
+
+// / CMD_GET_LEADERBOARD request
+type GetLeaderBoardRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	QuizId    int64 `protobuf:"varint,1,opt,name=quiz_id,json=quizId,proto3" json:"quiz_id,omitempty"`
+	PageIndex int32 `protobuf:"varint,2,opt,name=page_index,json=pageIndex,proto3" json:"page_index,omitempty"`
+	PageSize  int32 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+}
+
+func (x *GetLeaderBoardRequest) Reset() {
+	*x = GetLeaderBoardRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_quiz_api_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetLeaderBoardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLeaderBoardRequest) ProtoMessage() {}
+
+func (x *GetLeaderBoardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_api_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_quiz_api_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*JoinQuizRequestReply); i {
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLeaderBoardRequest.ProtoReflect.Descriptor instead.
+func (*GetLeaderBoardRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_api_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetLeaderBoardRequest) GetQuizId() int64 {
+	if x != nil {
+		return x.QuizId
+	}
+	return 0
+}
+
+func (x *GetLeaderBoardRequest) GetPageIndex() int32 {
+	if x != nil {
+		return x.PageIndex
+	}
+	return 0
+}
+
+func (x *GetLeaderBoardRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type LeaderBoardEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserId int64 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Score  int32 `protobuf:"varint,2,opt,name=score,proto3" json:"score,omitempty"`
+	Rank   int64 `protobuf:"varint,3,opt,name=rank,proto3" json:"rank,omitempty"`
+}
+
+func (x *LeaderBoardEntry) Reset() {
+	*x = LeaderBoardEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_quiz_api_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LeaderBoardEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaderBoardEntry) ProtoMessage() {}
+
+func (x *LeaderBoardEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_api_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaderBoardEntry.ProtoReflect.Descriptor instead.
+func (*LeaderBoardEntry) Descriptor() ([]byte, []int) {
+	return file_quiz_api_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *LeaderBoardEntry) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *LeaderBoardEntry) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *LeaderBoardEntry) GetRank() int64 {
+	if x != nil {
+		return x.Rank
+	}
+	return 0
+}
+
+// / CMD_GET_LEADERBOARD reply
+type GetLeaderBoardReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Entries []*LeaderBoardEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	Total   int64               `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+}
+
+func (x *GetLeaderBoardReply) Reset() {
+	*x = GetLeaderBoardReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_quiz_api_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetLeaderBoardReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLeaderBoardReply) ProtoMessage() {}
+
+func (x *GetLeaderBoardReply) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_api_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLeaderBoardReply.ProtoReflect.Descriptor instead.
+func (*GetLeaderBoardReply) Descriptor() ([]byte, []int) {
+	return file_quiz_api_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetLeaderBoardReply) GetEntries() []*LeaderBoardEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *GetLeaderBoardReply) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+// / CMD_GET_LEADERBOARD_RANKS request
+type GetLeaderBoardRanksRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	QuizId  int64   `protobuf:"varint,1,opt,name=quiz_id,json=quizId,proto3" json:"quiz_id,omitempty"`
+	TopN    int32   `protobuf:"varint,2,opt,name=top_n,json=topN,proto3" json:"top_n,omitempty"`
+	UserIds []int64 `protobuf:"varint,3,rep,packed,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`
+	/// without_top only reads the ranks of user_ids, e.g. for the next batches of users of a push
+	WithoutTop bool `protobuf:"varint,4,opt,name=without_top,json=withoutTop,proto3" json:"without_top,omitempty"`
+}
+
+func (x *GetLeaderBoardRanksRequest) Reset() {
+	*x = GetLeaderBoardRanksRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_quiz_api_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetLeaderBoardRanksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLeaderBoardRanksRequest) ProtoMessage() {}
+
+func (x *GetLeaderBoardRanksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_api_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLeaderBoardRanksRequest.ProtoReflect.Descriptor instead.
+func (*GetLeaderBoardRanksRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_api_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetLeaderBoardRanksRequest) GetQuizId() int64 {
+	if x != nil {
+		return x.QuizId
+	}
+	return 0
+}
+
+func (x *GetLeaderBoardRanksRequest) GetTopN() int32 {
+	if x != nil {
+		return x.TopN
+	}
+	return 0
+}
+
+func (x *GetLeaderBoardRanksRequest) GetUserIds() []int64 {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+func (x *GetLeaderBoardRanksRequest) GetWithoutTop() bool {
+	if x != nil {
+		return x.WithoutTop
+	}
+	return false
+}
+
+// / CMD_GET_LEADERBOARD_RANKS reply
+// / ranks only contains the users on the leader board
+type GetLeaderBoardRanksReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Top   []*LeaderBoardEntry `protobuf:"bytes,1,rep,name=top,proto3" json:"top,omitempty"`
+	Ranks []*LeaderBoardEntry `protobuf:"bytes,2,rep,name=ranks,proto3" json:"ranks,omitempty"`
+	Total int64               `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+}
+
+func (x *GetLeaderBoardRanksReply) Reset() {
+	*x = GetLeaderBoardRanksReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_quiz_api_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetLeaderBoardRanksReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLeaderBoardRanksReply) ProtoMessage() {}
+
+func (x *GetLeaderBoardRanksReply) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_api_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLeaderBoardRanksReply.ProtoReflect.Descriptor instead.
+func (*GetLeaderBoardRanksReply) Descriptor() ([]byte, []int) {
+	return file_quiz_api_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetLeaderBoardRanksReply) GetTop() []*LeaderBoardEntry {
+	if x != nil {
+		return x.Top
+	}
+	return nil
+}
+
+func (x *GetLeaderBoardRanksReply) GetRanks() []*LeaderBoardEntry {
+	if x != nil {
+		return x.Ranks
+	}
+	return nil
+}
+
+func (x *GetLeaderBoardRanksReply) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+// / CMD_ADMIN_CREATE_QUIZ request, the quiz is created as a draft
+type AdminCreateQuizRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name      string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	StartTime int64  `protobuf:"varint,2,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime   int64  `protobuf:"varint,3,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+}
+
+func (x *AdminCreateQuizRequest) Reset() {
+	*x = AdminCreateQuizRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_quiz_api_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminCreateQuizRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminCreateQuizRequest) ProtoMessage() {}
+
+func (x *AdminCreateQuizRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_api_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminCreateQuizRequest.ProtoReflect.Descriptor instead.
+func (*AdminCreateQuizRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_api_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *AdminCreateQuizRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AdminCreateQuizRequest) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *AdminCreateQuizRequest) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+// / CMD_ADMIN_CREATE_QUIZ reply
+type AdminCreateQuizReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	QuizId int64 `protobuf:"varint,1,opt,name=quiz_id,json=quizId,proto3" json:"quiz_id,omitempty"`
+}
+
+func (x *AdminCreateQuizReply) Reset() {
+	*x = AdminCreateQuizReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_quiz_api_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminCreateQuizReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminCreateQuizReply) ProtoMessage() {}
+
+func (x *AdminCreateQuizReply) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_api_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminCreateQuizReply.ProtoReflect.Descriptor instead.
+func (*AdminCreateQuizReply) Descriptor() ([]byte, []int) {
+	return file_quiz_api_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *AdminCreateQuizReply) GetQuizId() int64 {
+	if x != nil {
+		return x.QuizId
+	}
+	return 0
+}
+
+// / CMD_ADMIN_UPDATE_QUIZ request, only a draft or scheduled quiz can be updated
+type AdminUpdateQuizRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	QuizId    int64  `protobuf:"varint,1,opt,name=quiz_id,json=quizId,proto3" json:"quiz_id,omitempty"`
+	Name      string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	StartTime int64  `protobuf:"varint,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime   int64  `protobuf:"varint,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+}
+
+func (x *AdminUpdateQuizRequest) Reset() {
+	*x = AdminUpdateQuizRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_quiz_api_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminUpdateQuizRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminUpdateQuizRequest) ProtoMessage() {}
+
+func (x *AdminUpdateQuizRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_api_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminUpdateQuizRequest.ProtoReflect.Descriptor instead.
+func (*AdminUpdateQuizRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_api_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *AdminUpdateQuizRequest) GetQuizId() int64 {
+	if x != nil {
+		return x.QuizId
+	}
+	return 0
+}
+
+func (x *AdminUpdateQuizRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AdminUpdateQuizRequest) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *AdminUpdateQuizRequest) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+// / CMD_ADMIN_UPDATE_QUIZ reply
+type AdminUpdateQuizReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *AdminUpdateQuizReply) Reset() {
+	*x = AdminUpdateQuizReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_quiz_api_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminUpdateQuizReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminUpdateQuizReply) ProtoMessage() {}
+
+func (x *AdminUpdateQuizReply) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_api_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminUpdateQuizReply.ProtoReflect.Descriptor instead.
+func (*AdminUpdateQuizReply) Descriptor() ([]byte, []int) {
+	return file_quiz_api_proto_rawDescGZIP(), []int{15}
+}
+
+type AdminAnswerOption struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Content   string `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+	IsCorrect bool   `protobuf:"varint,2,opt,name=is_correct,json=isCorrect,proto3" json:"is_correct,omitempty"`
+}
+
+func (x *AdminAnswerOption) Reset() {
+	*x = AdminAnswerOption{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_quiz_api_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminAnswerOption) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminAnswerOption) ProtoMessage() {}
+
+func (x *AdminAnswerOption) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_api_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminAnswerOption.ProtoReflect.Descriptor instead.
+func (*AdminAnswerOption) Descriptor() ([]byte, []int) {
+	return file_quiz_api_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *AdminAnswerOption) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *AdminAnswerOption) GetIsCorrect() bool {
+	if x != nil {
+		return x.IsCorrect
+	}
+	return false
+}
+
+// / CMD_ADMIN_ADD_QUESTION request, the question is appended to the questions of a draft quiz.
+// / duration is in seconds, 0 keeps the question open until the end of the quiz.
+type AdminAddQuestionRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	QuizId   int64                `protobuf:"varint,1,opt,name=quiz_id,json=quizId,proto3" json:"quiz_id,omitempty"`
+	Content  string               `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	Score    int32                `protobuf:"varint,3,opt,name=score,proto3" json:"score,omitempty"`
+	Duration int32                `protobuf:"varint,4,opt,name=duration,proto3" json:"duration,omitempty"`
+	Options  []*AdminAnswerOption `protobuf:"bytes,5,rep,name=options,proto3" json:"options,omitempty"`
+}
+
+func (x *AdminAddQuestionRequest) Reset() {
+	*x = AdminAddQuestionRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_quiz_api_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminAddQuestionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminAddQuestionRequest) ProtoMessage() {}
+
+func (x *AdminAddQuestionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_api_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminAddQuestionRequest.ProtoReflect.Descriptor instead.
+func (*AdminAddQuestionRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_api_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *AdminAddQuestionRequest) GetQuizId() int64 {
+	if x != nil {
+		return x.QuizId
+	}
+	return 0
+}
+
+func (x *AdminAddQuestionRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *AdminAddQuestionRequest) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *AdminAddQuestionRequest) GetDuration() int32 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *AdminAddQuestionRequest) GetOptions() []*AdminAnswerOption {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+// / CMD_ADMIN_ADD_QUESTION reply
+type AdminAddQuestionReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	QuestionId int64 `protobuf:"varint,1,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
+}
+
+func (x *AdminAddQuestionReply) Reset() {
+	*x = AdminAddQuestionReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_quiz_api_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminAddQuestionReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminAddQuestionReply) ProtoMessage() {}
+
+func (x *AdminAddQuestionReply) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_api_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminAddQuestionReply.ProtoReflect.Descriptor instead.
+func (*AdminAddQuestionReply) Descriptor() ([]byte, []int) {
+	return file_quiz_api_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *AdminAddQuestionReply) GetQuestionId() int64 {
+	if x != nil {
+		return x.QuestionId
+	}
+	return 0
+}
+
+// / CMD_ADMIN_REORDER_QUESTIONS request, question_ids lists all questions of a draft quiz in their new order
+type AdminReorderQuestionsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	QuizId      int64   `protobuf:"varint,1,opt,name=quiz_id,json=quizId,proto3" json:"quiz_id,omitempty"`
+	QuestionIds []int64 `protobuf:"varint,2,rep,packed,name=question_ids,json=questionIds,proto3" json:"question_ids,omitempty"`
+}
+
+func (x *AdminReorderQuestionsRequest) Reset() {
+	*x = AdminReorderQuestionsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_quiz_api_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminReorderQuestionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminReorderQuestionsRequest) ProtoMessage() {}
+
+func (x *AdminReorderQuestionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_api_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminReorderQuestionsRequest.ProtoReflect.Descriptor instead.
+func (*AdminReorderQuestionsRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_api_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *AdminReorderQuestionsRequest) GetQuizId() int64 {
+	if x != nil {
+		return x.QuizId
+	}
+	return 0
+}
+
+func (x *AdminReorderQuestionsRequest) GetQuestionIds() []int64 {
+	if x != nil {
+		return x.QuestionIds
+	}
+	return nil
+}
+
+// / CMD_ADMIN_REORDER_QUESTIONS reply
+type AdminReorderQuestionsReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *AdminReorderQuestionsReply) Reset() {
+	*x = AdminReorderQuestionsReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_quiz_api_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminReorderQuestionsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminReorderQuestionsReply) ProtoMessage() {}
+
+func (x *AdminReorderQuestionsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_api_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminReorderQuestionsReply.ProtoReflect.Descriptor instead.
+func (*AdminReorderQuestionsReply) Descriptor() ([]byte, []int) {
+	return file_quiz_api_proto_rawDescGZIP(), []int{20}
+}
+
+// / CMD_ADMIN_PUBLISH_QUIZ request, the draft quiz is scheduled
+type AdminPublishQuizRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	QuizId int64 `protobuf:"varint,1,opt,name=quiz_id,json=quizId,proto3" json:"quiz_id,omitempty"`
+}
+
+func (x *AdminPublishQuizRequest) Reset() {
+	*x = AdminPublishQuizRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_quiz_api_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminPublishQuizRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminPublishQuizRequest) ProtoMessage() {}
+
+func (x *AdminPublishQuizRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_api_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminPublishQuizRequest.ProtoReflect.Descriptor instead.
+func (*AdminPublishQuizRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_api_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *AdminPublishQuizRequest) GetQuizId() int64 {
+	if x != nil {
+		return x.QuizId
+	}
+	return 0
+}
+
+// / CMD_ADMIN_PUBLISH_QUIZ reply
+type AdminPublishQuizReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *AdminPublishQuizReply) Reset() {
+	*x = AdminPublishQuizReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_quiz_api_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminPublishQuizReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminPublishQuizReply) ProtoMessage() {}
+
+func (x *AdminPublishQuizReply) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_api_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminPublishQuizReply.ProtoReflect.Descriptor instead.
+func (*AdminPublishQuizReply) Descriptor() ([]byte, []int) {
+	return file_quiz_api_proto_rawDescGZIP(), []int{22}
+}
+
+// / CMD_ADMIN_CLOSE_QUIZ request, the quiz is finished now
+type AdminCloseQuizRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	QuizId int64 `protobuf:"varint,1,opt,name=quiz_id,json=quizId,proto3" json:"quiz_id,omitempty"`
+}
+
+func (x *AdminCloseQuizRequest) Reset() {
+	*x = AdminCloseQuizRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_quiz_api_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminCloseQuizRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminCloseQuizRequest) ProtoMessage() {}
+
+func (x *AdminCloseQuizRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_api_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminCloseQuizRequest.ProtoReflect.Descriptor instead.
+func (*AdminCloseQuizRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_api_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *AdminCloseQuizRequest) GetQuizId() int64 {
+	if x != nil {
+		return x.QuizId
+	}
+	return 0
+}
+
+// / CMD_ADMIN_CLOSE_QUIZ reply
+type AdminCloseQuizReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *AdminCloseQuizReply) Reset() {
+	*x = AdminCloseQuizReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_quiz_api_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminCloseQuizReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminCloseQuizReply) ProtoMessage() {}
+
+func (x *AdminCloseQuizReply) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_api_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminCloseQuizReply.ProtoReflect.Descriptor instead.
+func (*AdminCloseQuizReply) Descriptor() ([]byte, []int) {
+	return file_quiz_api_proto_rawDescGZIP(), []int{24}
+}
+
+// / CMD_ADMIN_DELETE_QUIZ request, only a draft quiz can be deleted
+type AdminDeleteQuizRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	QuizId int64 `protobuf:"varint,1,opt,name=quiz_id,json=quizId,proto3" json:"quiz_id,omitempty"`
+}
+
+func (x *AdminDeleteQuizRequest) Reset() {
+	*x = AdminDeleteQuizRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_quiz_api_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminDeleteQuizRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminDeleteQuizRequest) ProtoMessage() {}
+
+func (x *AdminDeleteQuizRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_api_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminDeleteQuizRequest.ProtoReflect.Descriptor instead.
+func (*AdminDeleteQuizRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_api_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *AdminDeleteQuizRequest) GetQuizId() int64 {
+	if x != nil {
+		return x.QuizId
+	}
+	return 0
+}
+
+// / CMD_ADMIN_DELETE_QUIZ reply
+type AdminDeleteQuizReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *AdminDeleteQuizReply) Reset() {
+	*x = AdminDeleteQuizReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_quiz_api_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminDeleteQuizReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminDeleteQuizReply) ProtoMessage() {}
+
+func (x *AdminDeleteQuizReply) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_api_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminDeleteQuizReply.ProtoReflect.Descriptor instead.
+func (*AdminDeleteQuizReply) Descriptor() ([]byte, []int) {
+	return file_quiz_api_proto_rawDescGZIP(), []int{26}
+}
+
+// / CMD_ADMIN_PAUSE_QUIZ request, the quiz in progress is paused, its answers are rejected until it resumes
+type AdminPauseQuizRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	QuizId int64 `protobuf:"varint,1,opt,name=quiz_id,json=quizId,proto3" json:"quiz_id,omitempty"`
+}
+
+func (x *AdminPauseQuizRequest) Reset() {
+	*x = AdminPauseQuizRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_quiz_api_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminPauseQuizRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminPauseQuizRequest) ProtoMessage() {}
+
+func (x *AdminPauseQuizRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_api_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminPauseQuizRequest.ProtoReflect.Descriptor instead.
+func (*AdminPauseQuizRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_api_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *AdminPauseQuizRequest) GetQuizId() int64 {
+	if x != nil {
+		return x.QuizId
+	}
+	return 0
+}
+
+// / CMD_ADMIN_PAUSE_QUIZ reply
+type AdminPauseQuizReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *AdminPauseQuizReply) Reset() {
+	*x = AdminPauseQuizReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_quiz_api_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminPauseQuizReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminPauseQuizReply) ProtoMessage() {}
+
+func (x *AdminPauseQuizReply) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_api_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminPauseQuizReply.ProtoReflect.Descriptor instead.
+func (*AdminPauseQuizReply) Descriptor() ([]byte, []int) {
+	return file_quiz_api_proto_rawDescGZIP(), []int{28}
+}
+
+// / CMD_ADMIN_RESUME_QUIZ request, the paused quiz is in progress again, its end_time is pushed back by the pause
+type AdminResumeQuizRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	QuizId int64 `protobuf:"varint,1,opt,name=quiz_id,json=quizId,proto3" json:"quiz_id,omitempty"`
+}
+
+func (x *AdminResumeQuizRequest) Reset() {
+	*x = AdminResumeQuizRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_quiz_api_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminResumeQuizRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminResumeQuizRequest) ProtoMessage() {}
+
+func (x *AdminResumeQuizRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_api_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminResumeQuizRequest.ProtoReflect.Descriptor instead.
+func (*AdminResumeQuizRequest) Descriptor() ([]byte, []int) {
+	return file_quiz_api_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *AdminResumeQuizRequest) GetQuizId() int64 {
+	if x != nil {
+		return x.QuizId
+	}
+	return 0
+}
+
+// / CMD_ADMIN_RESUME_QUIZ reply
+type AdminResumeQuizReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *AdminResumeQuizReply) Reset() {
+	*x = AdminResumeQuizReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_quiz_api_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminResumeQuizReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminResumeQuizReply) ProtoMessage() {}
+
+func (x *AdminResumeQuizReply) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_api_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminResumeQuizReply.ProtoReflect.Descriptor instead.
+func (*AdminResumeQuizReply) Descriptor() ([]byte, []int) {
+	return file_quiz_api_proto_rawDescGZIP(), []int{30}
+}
+
+var File_quiz_api_proto protoreflect.FileDescriptor
+
+var file_quiz_api_proto_rawDesc = []byte{
+	0x0a, 0x0e, 0x71, 0x75, 0x69, 0x7a, 0x5f, 0x61, 0x70, 0x69, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x04, 0x71, 0x75, 0x69, 0x7a, 0x1a, 0x0b, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x22, 0x34, 0x0a, 0x0c, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x44, 0x65, 0x74,
+	0x61, 0x69, 0x6c, 0x12, 0x24, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x0c, 0x2e, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x2e, 0x45, 0x72, 0x72, 0x6f,
+	0x72, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x70, 0x0a, 0x0b, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x44, 0x61, 0x74, 0x61, 0x12, 0x28, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d,
+	0x61, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x63, 0x6f, 0x6e, 0x73,
+	0x74, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61,
+	0x6e, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x22, 0x50, 0x0a, 0x0c, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x12, 0x24, 0x0a, 0x06, 0x72,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0c, 0x2e, 0x63, 0x6f,
+	0x6e, 0x73, 0x74, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x47, 0x0a,
+	0x0f, 0x4a, 0x6f, 0x69, 0x6e, 0x51, 0x75, 0x69, 0x7a, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x1b, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x03, 0x42, 0x02, 0x18, 0x01, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x17, 0x0a,
+	0x07, 0x71, 0x75, 0x69, 0x7a, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06,
+	0x71, 0x75, 0x69, 0x7a, 0x49, 0x64, 0x22, 0x16, 0x0a, 0x14, 0x4a, 0x6f, 0x69, 0x6e, 0x51, 0x75,
+	0x69, 0x7a, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x89,
+	0x01, 0x0a, 0x13, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x41, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x42, 0x02, 0x18, 0x01, 0x52, 0x06, 0x75, 0x73, 0x65,
+	0x72, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x71, 0x75, 0x69, 0x7a, 0x5f, 0x69, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x71, 0x75, 0x69, 0x7a, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0a, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x1b, 0x0a,
+	0x09, 0x61, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x08, 0x61, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x49, 0x64, 0x22, 0x64, 0x0a, 0x11, 0x53, 0x75,
+	0x62, 0x6d, 0x69, 0x74, 0x41, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12,
+	0x18, 0x0a, 0x07, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x07, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x63, 0x6f,
+	0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x12,
+	0x1f, 0x0a, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x53, 0x63, 0x6f, 0x72, 0x65,
+	0x22, 0x6c, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x4c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x42, 0x6f, 0x61,
+	0x72, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x71, 0x75, 0x69,
+	0x7a, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x71, 0x75, 0x69, 0x7a,
+	0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x70, 0x61, 0x67, 0x65, 0x49, 0x6e, 0x64, 0x65,
+	0x78, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x22, 0x55,
+	0x0a, 0x10, 0x4c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x42, 0x6f, 0x61, 0x72, 0x64, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x73,
+	0x63, 0x6f, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x72,
+	0x65, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x61, 0x6e, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x04, 0x72, 0x61, 0x6e, 0x6b, 0x22, 0x5d, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x4c, 0x65, 0x61, 0x64,
+	0x65, 0x72, 0x42, 0x6f, 0x61, 0x72, 0x64, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x30, 0x0a, 0x07,
+	0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e,
+	0x71, 0x75, 0x69, 0x7a, 0x2e, 0x4c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x42, 0x6f, 0x61, 0x72, 0x64,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x12, 0x14,
+	0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x74,
+	0x6f, 0x74, 0x61, 0x6c, 0x22, 0x86, 0x01, 0x0a, 0x1a, 0x47, 0x65, 0x74, 0x4c, 0x65, 0x61, 0x64,
+	0x65, 0x72, 0x42, 0x6f, 0x61, 0x72, 0x64, 0x52, 0x61, 0x6e, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x71, 0x75, 0x69, 0x7a, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x71, 0x75, 0x69, 0x7a, 0x49, 0x64, 0x12, 0x13, 0x0a, 0x05,
+	0x74, 0x6f, 0x70, 0x5f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x74, 0x6f, 0x70,
+	0x4e, 0x12, 0x19, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x03, 0x52, 0x07, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x73, 0x12, 0x1f, 0x0a, 0x0b,
+	0x77, 0x69, 0x74, 0x68, 0x6f, 0x75, 0x74, 0x5f, 0x74, 0x6f, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0a, 0x77, 0x69, 0x74, 0x68, 0x6f, 0x75, 0x74, 0x54, 0x6f, 0x70, 0x22, 0x88, 0x01,
+	0x0a, 0x18, 0x47, 0x65, 0x74, 0x4c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x42, 0x6f, 0x61, 0x72, 0x64,
+	0x52, 0x61, 0x6e, 0x6b, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x28, 0x0a, 0x03, 0x74, 0x6f,
+	0x70, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x71, 0x75, 0x69, 0x7a, 0x2e, 0x4c,
+	0x65, 0x61, 0x64, 0x65, 0x72, 0x42, 0x6f, 0x61, 0x72, 0x64, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x03, 0x74, 0x6f, 0x70, 0x12, 0x2c, 0x0a, 0x05, 0x72, 0x61, 0x6e, 0x6b, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x71, 0x75, 0x69, 0x7a, 0x2e, 0x4c, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x42, 0x6f, 0x61, 0x72, 0x64, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x72, 0x61, 0x6e,
+	0x6b, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x22, 0x66, 0x0a, 0x16, 0x41, 0x64, 0x6d, 0x69,
+	0x6e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x51, 0x75, 0x69, 0x7a, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f,
+	0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x5f, 0x74, 0x69, 0x6d,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65,
+	0x22, 0x2f, 0x0a, 0x14, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x51,
+	0x75, 0x69, 0x7a, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x17, 0x0a, 0x07, 0x71, 0x75, 0x69, 0x7a,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x71, 0x75, 0x69, 0x7a, 0x49,
+	0x64, 0x22, 0x7f, 0x0a, 0x16, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x51, 0x75, 0x69, 0x7a, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x71,
+	0x75, 0x69, 0x7a, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x71, 0x75,
+	0x69, 0x7a, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x73, 0x74,
+	0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x5f, 0x74,
+	0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x65, 0x6e, 0x64, 0x54, 0x69,
+	0x6d, 0x65, 0x22, 0x16, 0x0a, 0x14, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x51, 0x75, 0x69, 0x7a, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x4c, 0x0a, 0x11, 0x41, 0x64,
+	0x6d, 0x69, 0x6e, 0x41, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x69, 0x73, 0x5f,
+	0x63, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x69,
+	0x73, 0x43, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x22, 0xb1, 0x01, 0x0a, 0x17, 0x41, 0x64, 0x6d,
+	0x69, 0x6e, 0x41, 0x64, 0x64, 0x51, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x71, 0x75, 0x69, 0x7a, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x71, 0x75, 0x69, 0x7a, 0x49, 0x64, 0x12, 0x18, 0x0a,
+	0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x1a, 0x0a,
+	0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x31, 0x0a, 0x07, 0x6f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x71, 0x75, 0x69,
+	0x7a, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x41, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x4f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x38, 0x0a, 0x15,
+	0x41, 0x64, 0x6d, 0x69, 0x6e, 0x41, 0x64, 0x64, 0x51, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x1f, 0x0a, 0x0b, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f,
+	0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x22, 0x5a, 0x0a, 0x1c, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x52,
+	0x65, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x51, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x71, 0x75, 0x69, 0x7a, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x71, 0x75, 0x69, 0x7a, 0x49, 0x64, 0x12,
+	0x21, 0x0a, 0x0c, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x03, 0x52, 0x0b, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x49,
+	0x64, 0x73, 0x22, 0x1c, 0x0a, 0x1a, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x52, 0x65, 0x6f, 0x72, 0x64,
+	0x65, 0x72, 0x51, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x22, 0x32, 0x0a, 0x17, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68,
+	0x51, 0x75, 0x69, 0x7a, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x71,
+	0x75, 0x69, 0x7a, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x71, 0x75,
+	0x69, 0x7a, 0x49, 0x64, 0x22, 0x17, 0x0a, 0x15, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x50, 0x75, 0x62,
+	0x6c, 0x69, 0x73, 0x68, 0x51, 0x75, 0x69, 0x7a, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x30, 0x0a,
+	0x15, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x51, 0x75, 0x69, 0x7a, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x71, 0x75, 0x69, 0x7a, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x71, 0x75, 0x69, 0x7a, 0x49, 0x64, 0x22,
+	0x15, 0x0a, 0x13, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x51, 0x75, 0x69,
+	0x7a, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x31, 0x0a, 0x16, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x44,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x51, 0x75, 0x69, 0x7a, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x17, 0x0a, 0x07, 0x71, 0x75, 0x69, 0x7a, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x06, 0x71, 0x75, 0x69, 0x7a, 0x49, 0x64, 0x22, 0x16, 0x0a, 0x14, 0x41, 0x64, 0x6d,
+	0x69, 0x6e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x51, 0x75, 0x69, 0x7a, 0x52, 0x65, 0x70, 0x6c,
+	0x79, 0x22, 0x30, 0x0a, 0x15, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x50, 0x61, 0x75, 0x73, 0x65, 0x51,
+	0x75, 0x69, 0x7a, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x71, 0x75,
+	0x69, 0x7a, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x71, 0x75, 0x69,
+	0x7a, 0x49, 0x64, 0x22, 0x15, 0x0a, 0x13, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x50, 0x61, 0x75, 0x73,
+	0x65, 0x51, 0x75, 0x69, 0x7a, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x31, 0x0a, 0x16, 0x41, 0x64,
+	0x6d, 0x69, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x51, 0x75, 0x69, 0x7a, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x71, 0x75, 0x69, 0x7a, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x71, 0x75, 0x69, 0x7a, 0x49, 0x64, 0x22, 0x16, 0x0a,
+	0x14, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x51, 0x75, 0x69, 0x7a,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x32, 0x40, 0x0a, 0x0b, 0x51, 0x75, 0x69, 0x7a, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x12, 0x31, 0x0a, 0x06, 0x48, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x12, 0x11,
+	0x2e, 0x71, 0x75, 0x69, 0x7a, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x44, 0x61, 0x74,
+	0x61, 0x1a, 0x12, 0x2e, 0x71, 0x75, 0x69, 0x7a, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x44, 0x61, 0x74, 0x61, 0x22, 0x00, 0x32, 0x96, 0x08, 0x0a, 0x0d, 0x51, 0x75, 0x69, 0x7a,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x56, 0x32, 0x12, 0x3f, 0x0a, 0x08, 0x4a, 0x6f, 0x69,
+	0x6e, 0x51, 0x75, 0x69, 0x7a, 0x12, 0x15, 0x2e, 0x71, 0x75, 0x69, 0x7a, 0x2e, 0x4a, 0x6f, 0x69,
+	0x6e, 0x51, 0x75, 0x69, 0x7a, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x71,
+	0x75, 0x69, 0x7a, 0x2e, 0x4a, 0x6f, 0x69, 0x6e, 0x51, 0x75, 0x69, 0x7a, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x44, 0x0a, 0x0c, 0x53, 0x75,
+	0x62, 0x6d, 0x69, 0x74, 0x41, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x12, 0x19, 0x2e, 0x71, 0x75, 0x69,
+	0x7a, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x41, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x71, 0x75, 0x69, 0x7a, 0x2e, 0x53, 0x75, 0x62,
+	0x6d, 0x69, 0x74, 0x41, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00,
+	0x12, 0x4a, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x4c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x42, 0x6f, 0x61,
+	0x72, 0x64, 0x12, 0x1b, 0x2e, 0x71, 0x75, 0x69, 0x7a, 0x2e, 0x47, 0x65, 0x74, 0x4c, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x42, 0x6f, 0x61, 0x72, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x19, 0x2e, 0x71, 0x75, 0x69, 0x7a, 0x2e, 0x47, 0x65, 0x74, 0x4c, 0x65, 0x61, 0x64, 0x65, 0x72,
+	0x42, 0x6f, 0x61, 0x72, 0x64, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x59, 0x0a, 0x13,
+	0x47, 0x65, 0x74, 0x4c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x42, 0x6f, 0x61, 0x72, 0x64, 0x52, 0x61,
+	0x6e, 0x6b, 0x73, 0x12, 0x20, 0x2e, 0x71, 0x75, 0x69, 0x7a, 0x2e, 0x47, 0x65, 0x74, 0x4c, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x42, 0x6f, 0x61, 0x72, 0x64, 0x52, 0x61, 0x6e, 0x6b, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x71, 0x75, 0x69, 0x7a, 0x2e, 0x47, 0x65, 0x74,
+	0x4c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x42, 0x6f, 0x61, 0x72, 0x64, 0x52, 0x61, 0x6e, 0x6b, 0x73,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x4d, 0x0a, 0x0f, 0x41, 0x64, 0x6d, 0x69, 0x6e,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x51, 0x75, 0x69, 0x7a, 0x12, 0x1c, 0x2e, 0x71, 0x75, 0x69,
+	0x7a, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x51, 0x75, 0x69,
+	0x7a, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x71, 0x75, 0x69, 0x7a, 0x2e,
+	0x41, 0x64, 0x6d, 0x69, 0x6e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x51, 0x75, 0x69, 0x7a, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x4d, 0x0a, 0x0f, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x51, 0x75, 0x69, 0x7a, 0x12, 0x1c, 0x2e, 0x71, 0x75, 0x69, 0x7a,
+	0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x51, 0x75, 0x69, 0x7a,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x71, 0x75, 0x69, 0x7a, 0x2e, 0x41,
+	0x64, 0x6d, 0x69, 0x6e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x51, 0x75, 0x69, 0x7a, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x50, 0x0a, 0x10, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x41, 0x64,
+	0x64, 0x51, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x2e, 0x71, 0x75, 0x69, 0x7a,
+	0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x41, 0x64, 0x64, 0x51, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x71, 0x75, 0x69, 0x7a, 0x2e,
+	0x41, 0x64, 0x6d, 0x69, 0x6e, 0x41, 0x64, 0x64, 0x51, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x5f, 0x0a, 0x15, 0x41, 0x64, 0x6d, 0x69, 0x6e,
+	0x52, 0x65, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x51, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x12, 0x22, 0x2e, 0x71, 0x75, 0x69, 0x7a, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x52, 0x65, 0x6f,
+	0x72, 0x64, 0x65, 0x72, 0x51, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x71, 0x75, 0x69, 0x7a, 0x2e, 0x41, 0x64, 0x6d, 0x69,
+	0x6e, 0x52, 0x65, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x51, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x50, 0x0a, 0x10, 0x41, 0x64, 0x6d, 0x69,
+	0x6e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x51, 0x75, 0x69, 0x7a, 0x12, 0x1d, 0x2e, 0x71,
+	0x75, 0x69, 0x7a, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68,
+	0x51, 0x75, 0x69, 0x7a, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x71, 0x75,
+	0x69, 0x7a, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x51,
+	0x75, 0x69, 0x7a, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x4a, 0x0a, 0x0e, 0x41, 0x64,
+	0x6d, 0x69, 0x6e, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x51, 0x75, 0x69, 0x7a, 0x12, 0x1b, 0x2e, 0x71,
+	0x75, 0x69, 0x7a, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x51, 0x75,
+	0x69, 0x7a, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x71, 0x75, 0x69, 0x7a,
+	0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x51, 0x75, 0x69, 0x7a, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x4d, 0x0a, 0x0f, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x44,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x51, 0x75, 0x69, 0x7a, 0x12, 0x1c, 0x2e, 0x71, 0x75, 0x69, 0x7a,
+	0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x51, 0x75, 0x69, 0x7a,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x71, 0x75, 0x69, 0x7a, 0x2e, 0x41,
+	0x64, 0x6d, 0x69, 0x6e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x51, 0x75, 0x69, 0x7a, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x4a, 0x0a, 0x0e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x50, 0x61,
+	0x75, 0x73, 0x65, 0x51, 0x75, 0x69, 0x7a, 0x12, 0x1b, 0x2e, 0x71, 0x75, 0x69, 0x7a, 0x2e, 0x41,
+	0x64, 0x6d, 0x69, 0x6e, 0x50, 0x61, 0x75, 0x73, 0x65, 0x51, 0x75, 0x69, 0x7a, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x71, 0x75, 0x69, 0x7a, 0x2e, 0x41, 0x64, 0x6d, 0x69,
+	0x6e, 0x50, 0x61, 0x75, 0x73, 0x65, 0x51, 0x75, 0x69, 0x7a, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22,
+	0x00, 0x12, 0x4d, 0x0a, 0x0f, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65,
+	0x51, 0x75, 0x69, 0x7a, 0x12, 0x1c, 0x2e, 0x71, 0x75, 0x69, 0x7a, 0x2e, 0x41, 0x64, 0x6d, 0x69,
+	0x6e, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x51, 0x75, 0x69, 0x7a, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x71, 0x75, 0x69, 0x7a, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x52,
+	0x65, 0x73, 0x75, 0x6d, 0x65, 0x51, 0x75, 0x69, 0x7a, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00,
+	0x42, 0x0d, 0x5a, 0x0b, 0x70, 0x62, 0x2f, 0x71, 0x75, 0x69, 0x7a, 0x5f, 0x61, 0x70, 0x69, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_quiz_api_proto_rawDescOnce sync.Once
+	file_quiz_api_proto_rawDescData = file_quiz_api_proto_rawDesc
+)
+
+func file_quiz_api_proto_rawDescGZIP() []byte {
+	file_quiz_api_proto_rawDescOnce.Do(func() {
+		file_quiz_api_proto_rawDescData = protoimpl.X.CompressGZIP(file_quiz_api_proto_rawDescData)
+	})
+	return file_quiz_api_proto_rawDescData
+}
+
+var file_quiz_api_proto_msgTypes = make([]protoimpl.MessageInfo, 31)
+var file_quiz_api_proto_goTypes = []interface{}{
+	(*ResultDetail)(nil),                 // 0: quiz.ResultDetail
+	(*RequestData)(nil),                  // 1: quiz.RequestData
+	(*ResponseData)(nil),                 // 2: quiz.ResponseData
+	(*JoinQuizRequest)(nil),              // 3: quiz.JoinQuizRequest
+	(*JoinQuizRequestReply)(nil),         // 4: quiz.JoinQuizRequestReply
+	(*SubmitAnswerRequest)(nil),          // 5: quiz.SubmitAnswerRequest
+	(*SubmitAnswerReply)(nil),            // 6: quiz.SubmitAnswerReply
+	(*GetLeaderBoardRequest)(nil),        // 7: quiz.GetLeaderBoardRequest
+	(*LeaderBoardEntry)(nil),             // 8: quiz.LeaderBoardEntry
+	(*GetLeaderBoardReply)(nil),          // 9: quiz.GetLeaderBoardReply
+	(*GetLeaderBoardRanksRequest)(nil),   // 10: quiz.GetLeaderBoardRanksRequest
+	(*GetLeaderBoardRanksReply)(nil),     // 11: quiz.GetLeaderBoardRanksReply
+	(*AdminCreateQuizRequest)(nil),       // 12: quiz.AdminCreateQuizRequest
+	(*AdminCreateQuizReply)(nil),         // 13: quiz.AdminCreateQuizReply
+	(*AdminUpdateQuizRequest)(nil),       // 14: quiz.AdminUpdateQuizRequest
+	(*AdminUpdateQuizReply)(nil),         // 15: quiz.AdminUpdateQuizReply
+	(*AdminAnswerOption)(nil),            // 16: quiz.AdminAnswerOption
+	(*AdminAddQuestionRequest)(nil),      // 17: quiz.AdminAddQuestionRequest
+	(*AdminAddQuestionReply)(nil),        // 18: quiz.AdminAddQuestionReply
+	(*AdminReorderQuestionsRequest)(nil), // 19: quiz.AdminReorderQuestionsRequest
+	(*AdminReorderQuestionsReply)(nil),   // 20: quiz.AdminReorderQuestionsReply
+	(*AdminPublishQuizRequest)(nil),      // 21: quiz.AdminPublishQuizRequest
+	(*AdminPublishQuizReply)(nil),        // 22: quiz.AdminPublishQuizReply
+	(*AdminCloseQuizRequest)(nil),        // 23: quiz.AdminCloseQuizRequest
+	(*AdminCloseQuizReply)(nil),          // 24: quiz.AdminCloseQuizReply
+	(*AdminDeleteQuizRequest)(nil),       // 25: quiz.AdminDeleteQuizRequest
+	(*AdminDeleteQuizReply)(nil),         // 26: quiz.AdminDeleteQuizReply
+	(*AdminPauseQuizRequest)(nil),        // 27: quiz.AdminPauseQuizRequest
+	(*AdminPauseQuizReply)(nil),          // 28: quiz.AdminPauseQuizReply
+	(*AdminResumeQuizRequest)(nil),       // 29: quiz.AdminResumeQuizRequest
+	(*AdminResumeQuizReply)(nil),         // 30: quiz.AdminResumeQuizReply
+	(Error)(0),                           // 31: const.Error
+	(Command)(0),                         // 32: const.Command
+}
+var file_quiz_api_proto_depIdxs = []int32{
+	31, // 0: quiz.ResultDetail.result:type_name -> const.Error
+	32, // 1: quiz.RequestData.command:type_name -> const.Command
+	31, // 2: quiz.ResponseData.result:type_name -> const.Error
+	8,  // 3: quiz.GetLeaderBoardReply.entries:type_name -> quiz.LeaderBoardEntry
+	8,  // 4: quiz.GetLeaderBoardRanksReply.top:type_name -> quiz.LeaderBoardEntry
+	8,  // 5: quiz.GetLeaderBoardRanksReply.ranks:type_name -> quiz.LeaderBoardEntry
+	16, // 6: quiz.AdminAddQuestionRequest.options:type_name -> quiz.AdminAnswerOption
+	1,  // 7: quiz.QuizService.Handle:input_type -> quiz.RequestData
+	3,  // 8: quiz.QuizServiceV2.JoinQuiz:input_type -> quiz.JoinQuizRequest
+	5,  // 9: quiz.QuizServiceV2.SubmitAnswer:input_type -> quiz.SubmitAnswerRequest
+	7,  // 10: quiz.QuizServiceV2.GetLeaderBoard:input_type -> quiz.GetLeaderBoardRequest
+	10, // 11: quiz.QuizServiceV2.GetLeaderBoardRanks:input_type -> quiz.GetLeaderBoardRanksRequest
+	12, // 12: quiz.QuizServiceV2.AdminCreateQuiz:input_type -> quiz.AdminCreateQuizRequest
+	14, // 13: quiz.QuizServiceV2.AdminUpdateQuiz:input_type -> quiz.AdminUpdateQuizRequest
+	17, // 14: quiz.QuizServiceV2.AdminAddQuestion:input_type -> quiz.AdminAddQuestionRequest
+	19, // 15: quiz.QuizServiceV2.AdminReorderQuestions:input_type -> quiz.AdminReorderQuestionsRequest
+	21, // 16: quiz.QuizServiceV2.AdminPublishQuiz:input_type -> quiz.AdminPublishQuizRequest
+	23, // 17: quiz.QuizServiceV2.AdminCloseQuiz:input_type -> quiz.AdminCloseQuizRequest
+	25, // 18: quiz.QuizServiceV2.AdminDeleteQuiz:input_type -> quiz.AdminDeleteQuizRequest
+	27, // 19: quiz.QuizServiceV2.AdminPauseQuiz:input_type -> quiz.AdminPauseQuizRequest
+	29, // 20: quiz.QuizServiceV2.AdminResumeQuiz:input_type -> quiz.AdminResumeQuizRequest
+	2,  // 21: quiz.QuizService.Handle:output_type -> quiz.ResponseData
+	4,  // 22: quiz.QuizServiceV2.JoinQuiz:output_type -> quiz.JoinQuizRequestReply
+	6,  // 23: quiz.QuizServiceV2.SubmitAnswer:output_type -> quiz.SubmitAnswerReply
+	9,  // 24: quiz.QuizServiceV2.GetLeaderBoard:output_type -> quiz.GetLeaderBoardReply
+	11, // 25: quiz.QuizServiceV2.GetLeaderBoardRanks:output_type -> quiz.GetLeaderBoardRanksReply
+	13, // 26: quiz.QuizServiceV2.AdminCreateQuiz:output_type -> quiz.AdminCreateQuizReply
+	15, // 27: quiz.QuizServiceV2.AdminUpdateQuiz:output_type -> quiz.AdminUpdateQuizReply
+	18, // 28: quiz.QuizServiceV2.AdminAddQuestion:output_type -> quiz.AdminAddQuestionReply
+	20, // 29: quiz.QuizServiceV2.AdminReorderQuestions:output_type -> quiz.AdminReorderQuestionsReply
+	22, // 30: quiz.QuizServiceV2.AdminPublishQuiz:output_type -> quiz.AdminPublishQuizReply
+	24, // 31: quiz.QuizServiceV2.AdminCloseQuiz:output_type -> quiz.AdminCloseQuizReply
+	26, // 32: quiz.QuizServiceV2.AdminDeleteQuiz:output_type -> quiz.AdminDeleteQuizReply
+	28, // 33: quiz.QuizServiceV2.AdminPauseQuiz:output_type -> quiz.AdminPauseQuizReply
+	30, // 34: quiz.QuizServiceV2.AdminResumeQuiz:output_type -> quiz.AdminResumeQuizReply
+	21, // [21:35] is the sub-list for method output_type
+	7,  // [7:21] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_quiz_api_proto_init() }
+func file_quiz_api_proto_init() {
+	if File_quiz_api_proto != nil {
+		return
+	}
+	file_const_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_quiz_api_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResultDetail); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_quiz_api_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RequestData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_quiz_api_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResponseData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_quiz_api_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JoinQuizRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_quiz_api_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JoinQuizRequestReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_quiz_api_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubmitAnswerRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_quiz_api_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubmitAnswerReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_quiz_api_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetLeaderBoardRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_quiz_api_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LeaderBoardEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_quiz_api_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetLeaderBoardReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_quiz_api_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetLeaderBoardRanksRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_quiz_api_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetLeaderBoardRanksReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_quiz_api_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminCreateQuizRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_quiz_api_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminCreateQuizReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_quiz_api_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminUpdateQuizRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_quiz_api_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminUpdateQuizReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_quiz_api_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminAnswerOption); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_quiz_api_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminAddQuestionRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_quiz_api_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminAddQuestionReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_quiz_api_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminReorderQuestionsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_quiz_api_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminReorderQuestionsReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_quiz_api_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminPublishQuizRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_quiz_api_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminPublishQuizReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_quiz_api_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminCloseQuizRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_quiz_api_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminCloseQuizReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_quiz_api_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminDeleteQuizRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_quiz_api_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminDeleteQuizReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_quiz_api_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminPauseQuizRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_quiz_api_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminPauseQuizReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_quiz_api_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminResumeQuizRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_quiz_api_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminResumeQuizReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -349,9 +2377,9 @@ func file_quiz_api_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_quiz_api_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   31,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_quiz_api_proto_goTypes,
 		DependencyIndexes: file_quiz_api_proto_depIdxs,
@@ -103,3 +103,521 @@ var QuizService_ServiceDesc = grpc.ServiceDesc{
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "quiz_api.proto",
 }
+
+// QuizServiceV2Client is the client API for QuizServiceV2 service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type QuizServiceV2Client interface {
+	JoinQuiz(ctx context.Context, in *JoinQuizRequest, opts ...grpc.CallOption) (*JoinQuizRequestReply, error)
+	SubmitAnswer(ctx context.Context, in *SubmitAnswerRequest, opts ...grpc.CallOption) (*SubmitAnswerReply, error)
+	GetLeaderBoard(ctx context.Context, in *GetLeaderBoardRequest, opts ...grpc.CallOption) (*GetLeaderBoardReply, error)
+	GetLeaderBoardRanks(ctx context.Context, in *GetLeaderBoardRanksRequest, opts ...grpc.CallOption) (*GetLeaderBoardRanksReply, error)
+	AdminCreateQuiz(ctx context.Context, in *AdminCreateQuizRequest, opts ...grpc.CallOption) (*AdminCreateQuizReply, error)
+	AdminUpdateQuiz(ctx context.Context, in *AdminUpdateQuizRequest, opts ...grpc.CallOption) (*AdminUpdateQuizReply, error)
+	AdminAddQuestion(ctx context.Context, in *AdminAddQuestionRequest, opts ...grpc.CallOption) (*AdminAddQuestionReply, error)
+	AdminReorderQuestions(ctx context.Context, in *AdminReorderQuestionsRequest, opts ...grpc.CallOption) (*AdminReorderQuestionsReply, error)
+	AdminPublishQuiz(ctx context.Context, in *AdminPublishQuizRequest, opts ...grpc.CallOption) (*AdminPublishQuizReply, error)
+	AdminCloseQuiz(ctx context.Context, in *AdminCloseQuizRequest, opts ...grpc.CallOption) (*AdminCloseQuizReply, error)
+	AdminDeleteQuiz(ctx context.Context, in *AdminDeleteQuizRequest, opts ...grpc.CallOption) (*AdminDeleteQuizReply, error)
+	AdminPauseQuiz(ctx context.Context, in *AdminPauseQuizRequest, opts ...grpc.CallOption) (*AdminPauseQuizReply, error)
+	AdminResumeQuiz(ctx context.Context, in *AdminResumeQuizRequest, opts ...grpc.CallOption) (*AdminResumeQuizReply, error)
+}
+
+type quizServiceV2Client struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewQuizServiceV2Client(cc grpc.ClientConnInterface) QuizServiceV2Client {
+	return &quizServiceV2Client{cc}
+}
+
+func (c *quizServiceV2Client) JoinQuiz(ctx context.Context, in *JoinQuizRequest, opts ...grpc.CallOption) (*JoinQuizRequestReply, error) {
+	out := new(JoinQuizRequestReply)
+	err := c.cc.Invoke(ctx, "/quiz.QuizServiceV2/JoinQuiz", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quizServiceV2Client) SubmitAnswer(ctx context.Context, in *SubmitAnswerRequest, opts ...grpc.CallOption) (*SubmitAnswerReply, error) {
+	out := new(SubmitAnswerReply)
+	err := c.cc.Invoke(ctx, "/quiz.QuizServiceV2/SubmitAnswer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quizServiceV2Client) GetLeaderBoard(ctx context.Context, in *GetLeaderBoardRequest, opts ...grpc.CallOption) (*GetLeaderBoardReply, error) {
+	out := new(GetLeaderBoardReply)
+	err := c.cc.Invoke(ctx, "/quiz.QuizServiceV2/GetLeaderBoard", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quizServiceV2Client) GetLeaderBoardRanks(ctx context.Context, in *GetLeaderBoardRanksRequest, opts ...grpc.CallOption) (*GetLeaderBoardRanksReply, error) {
+	out := new(GetLeaderBoardRanksReply)
+	err := c.cc.Invoke(ctx, "/quiz.QuizServiceV2/GetLeaderBoardRanks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quizServiceV2Client) AdminCreateQuiz(ctx context.Context, in *AdminCreateQuizRequest, opts ...grpc.CallOption) (*AdminCreateQuizReply, error) {
+	out := new(AdminCreateQuizReply)
+	err := c.cc.Invoke(ctx, "/quiz.QuizServiceV2/AdminCreateQuiz", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quizServiceV2Client) AdminUpdateQuiz(ctx context.Context, in *AdminUpdateQuizRequest, opts ...grpc.CallOption) (*AdminUpdateQuizReply, error) {
+	out := new(AdminUpdateQuizReply)
+	err := c.cc.Invoke(ctx, "/quiz.QuizServiceV2/AdminUpdateQuiz", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quizServiceV2Client) AdminAddQuestion(ctx context.Context, in *AdminAddQuestionRequest, opts ...grpc.CallOption) (*AdminAddQuestionReply, error) {
+	out := new(AdminAddQuestionReply)
+	err := c.cc.Invoke(ctx, "/quiz.QuizServiceV2/AdminAddQuestion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quizServiceV2Client) AdminReorderQuestions(ctx context.Context, in *AdminReorderQuestionsRequest, opts ...grpc.CallOption) (*AdminReorderQuestionsReply, error) {
+	out := new(AdminReorderQuestionsReply)
+	err := c.cc.Invoke(ctx, "/quiz.QuizServiceV2/AdminReorderQuestions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quizServiceV2Client) AdminPublishQuiz(ctx context.Context, in *AdminPublishQuizRequest, opts ...grpc.CallOption) (*AdminPublishQuizReply, error) {
+	out := new(AdminPublishQuizReply)
+	err := c.cc.Invoke(ctx, "/quiz.QuizServiceV2/AdminPublishQuiz", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quizServiceV2Client) AdminCloseQuiz(ctx context.Context, in *AdminCloseQuizRequest, opts ...grpc.CallOption) (*AdminCloseQuizReply, error) {
+	out := new(AdminCloseQuizReply)
+	err := c.cc.Invoke(ctx, "/quiz.QuizServiceV2/AdminCloseQuiz", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quizServiceV2Client) AdminDeleteQuiz(ctx context.Context, in *AdminDeleteQuizRequest, opts ...grpc.CallOption) (*AdminDeleteQuizReply, error) {
+	out := new(AdminDeleteQuizReply)
+	err := c.cc.Invoke(ctx, "/quiz.QuizServiceV2/AdminDeleteQuiz", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quizServiceV2Client) AdminPauseQuiz(ctx context.Context, in *AdminPauseQuizRequest, opts ...grpc.CallOption) (*AdminPauseQuizReply, error) {
+	out := new(AdminPauseQuizReply)
+	err := c.cc.Invoke(ctx, "/quiz.QuizServiceV2/AdminPauseQuiz", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quizServiceV2Client) AdminResumeQuiz(ctx context.Context, in *AdminResumeQuizRequest, opts ...grpc.CallOption) (*AdminResumeQuizReply, error) {
+	out := new(AdminResumeQuizReply)
+	err := c.cc.Invoke(ctx, "/quiz.QuizServiceV2/AdminResumeQuiz", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// QuizServiceV2Server is the server API for QuizServiceV2 service.
+// All implementations must embed UnimplementedQuizServiceV2Server
+// for forward compatibility
+type QuizServiceV2Server interface {
+	JoinQuiz(context.Context, *JoinQuizRequest) (*JoinQuizRequestReply, error)
+	SubmitAnswer(context.Context, *SubmitAnswerRequest) (*SubmitAnswerReply, error)
+	GetLeaderBoard(context.Context, *GetLeaderBoardRequest) (*GetLeaderBoardReply, error)
+	GetLeaderBoardRanks(context.Context, *GetLeaderBoardRanksRequest) (*GetLeaderBoardRanksReply, error)
+	AdminCreateQuiz(context.Context, *AdminCreateQuizRequest) (*AdminCreateQuizReply, error)
+	AdminUpdateQuiz(context.Context, *AdminUpdateQuizRequest) (*AdminUpdateQuizReply, error)
+	AdminAddQuestion(context.Context, *AdminAddQuestionRequest) (*AdminAddQuestionReply, error)
+	AdminReorderQuestions(context.Context, *AdminReorderQuestionsRequest) (*AdminReorderQuestionsReply, error)
+	AdminPublishQuiz(context.Context, *AdminPublishQuizRequest) (*AdminPublishQuizReply, error)
+	AdminCloseQuiz(context.Context, *AdminCloseQuizRequest) (*AdminCloseQuizReply, error)
+	AdminDeleteQuiz(context.Context, *AdminDeleteQuizRequest) (*AdminDeleteQuizReply, error)
+	AdminPauseQuiz(context.Context, *AdminPauseQuizRequest) (*AdminPauseQuizReply, error)
+	AdminResumeQuiz(context.Context, *AdminResumeQuizRequest) (*AdminResumeQuizReply, error)
+	mustEmbedUnimplementedQuizServiceV2Server()
+}
+
+// UnimplementedQuizServiceV2Server must be embedded to have forward compatible implementations.
+type UnimplementedQuizServiceV2Server struct {
+}
+
+func (UnimplementedQuizServiceV2Server) JoinQuiz(context.Context, *JoinQuizRequest) (*JoinQuizRequestReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method JoinQuiz not implemented")
+}
+func (UnimplementedQuizServiceV2Server) SubmitAnswer(context.Context, *SubmitAnswerRequest) (*SubmitAnswerReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitAnswer not implemented")
+}
+func (UnimplementedQuizServiceV2Server) GetLeaderBoard(context.Context, *GetLeaderBoardRequest) (*GetLeaderBoardReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLeaderBoard not implemented")
+}
+func (UnimplementedQuizServiceV2Server) GetLeaderBoardRanks(context.Context, *GetLeaderBoardRanksRequest) (*GetLeaderBoardRanksReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLeaderBoardRanks not implemented")
+}
+func (UnimplementedQuizServiceV2Server) AdminCreateQuiz(context.Context, *AdminCreateQuizRequest) (*AdminCreateQuizReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdminCreateQuiz not implemented")
+}
+func (UnimplementedQuizServiceV2Server) AdminUpdateQuiz(context.Context, *AdminUpdateQuizRequest) (*AdminUpdateQuizReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdminUpdateQuiz not implemented")
+}
+func (UnimplementedQuizServiceV2Server) AdminAddQuestion(context.Context, *AdminAddQuestionRequest) (*AdminAddQuestionReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdminAddQuestion not implemented")
+}
+func (UnimplementedQuizServiceV2Server) AdminReorderQuestions(context.Context, *AdminReorderQuestionsRequest) (*AdminReorderQuestionsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdminReorderQuestions not implemented")
+}
+func (UnimplementedQuizServiceV2Server) AdminPublishQuiz(context.Context, *AdminPublishQuizRequest) (*AdminPublishQuizReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdminPublishQuiz not implemented")
+}
+func (UnimplementedQuizServiceV2Server) AdminCloseQuiz(context.Context, *AdminCloseQuizRequest) (*AdminCloseQuizReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdminCloseQuiz not implemented")
+}
+func (UnimplementedQuizServiceV2Server) AdminDeleteQuiz(context.Context, *AdminDeleteQuizRequest) (*AdminDeleteQuizReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdminDeleteQuiz not implemented")
+}
+func (UnimplementedQuizServiceV2Server) AdminPauseQuiz(context.Context, *AdminPauseQuizRequest) (*AdminPauseQuizReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdminPauseQuiz not implemented")
+}
+func (UnimplementedQuizServiceV2Server) AdminResumeQuiz(context.Context, *AdminResumeQuizRequest) (*AdminResumeQuizReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdminResumeQuiz not implemented")
+}
+func (UnimplementedQuizServiceV2Server) mustEmbedUnimplementedQuizServiceV2Server() {}
+
+// UnsafeQuizServiceV2Server may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to QuizServiceV2Server will
+// result in compilation errors.
+type UnsafeQuizServiceV2Server interface {
+	mustEmbedUnimplementedQuizServiceV2Server()
+}
+
+func RegisterQuizServiceV2Server(s grpc.ServiceRegistrar, srv QuizServiceV2Server) {
+	s.RegisterService(&QuizServiceV2_ServiceDesc, srv)
+}
+
+func _QuizServiceV2_JoinQuiz_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JoinQuizRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceV2Server).JoinQuiz(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/quiz.QuizServiceV2/JoinQuiz",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceV2Server).JoinQuiz(ctx, req.(*JoinQuizRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuizServiceV2_SubmitAnswer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitAnswerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceV2Server).SubmitAnswer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/quiz.QuizServiceV2/SubmitAnswer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceV2Server).SubmitAnswer(ctx, req.(*SubmitAnswerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuizServiceV2_GetLeaderBoard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLeaderBoardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceV2Server).GetLeaderBoard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/quiz.QuizServiceV2/GetLeaderBoard",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceV2Server).GetLeaderBoard(ctx, req.(*GetLeaderBoardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuizServiceV2_GetLeaderBoardRanks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLeaderBoardRanksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceV2Server).GetLeaderBoardRanks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/quiz.QuizServiceV2/GetLeaderBoardRanks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceV2Server).GetLeaderBoardRanks(ctx, req.(*GetLeaderBoardRanksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuizServiceV2_AdminCreateQuiz_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminCreateQuizRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceV2Server).AdminCreateQuiz(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/quiz.QuizServiceV2/AdminCreateQuiz",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceV2Server).AdminCreateQuiz(ctx, req.(*AdminCreateQuizRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuizServiceV2_AdminUpdateQuiz_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminUpdateQuizRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceV2Server).AdminUpdateQuiz(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/quiz.QuizServiceV2/AdminUpdateQuiz",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceV2Server).AdminUpdateQuiz(ctx, req.(*AdminUpdateQuizRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuizServiceV2_AdminAddQuestion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminAddQuestionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceV2Server).AdminAddQuestion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/quiz.QuizServiceV2/AdminAddQuestion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceV2Server).AdminAddQuestion(ctx, req.(*AdminAddQuestionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuizServiceV2_AdminReorderQuestions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminReorderQuestionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceV2Server).AdminReorderQuestions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/quiz.QuizServiceV2/AdminReorderQuestions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceV2Server).AdminReorderQuestions(ctx, req.(*AdminReorderQuestionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuizServiceV2_AdminPublishQuiz_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminPublishQuizRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceV2Server).AdminPublishQuiz(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/quiz.QuizServiceV2/AdminPublishQuiz",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceV2Server).AdminPublishQuiz(ctx, req.(*AdminPublishQuizRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuizServiceV2_AdminCloseQuiz_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminCloseQuizRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceV2Server).AdminCloseQuiz(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/quiz.QuizServiceV2/AdminCloseQuiz",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceV2Server).AdminCloseQuiz(ctx, req.(*AdminCloseQuizRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuizServiceV2_AdminDeleteQuiz_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminDeleteQuizRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceV2Server).AdminDeleteQuiz(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/quiz.QuizServiceV2/AdminDeleteQuiz",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceV2Server).AdminDeleteQuiz(ctx, req.(*AdminDeleteQuizRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuizServiceV2_AdminPauseQuiz_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminPauseQuizRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceV2Server).AdminPauseQuiz(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/quiz.QuizServiceV2/AdminPauseQuiz",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceV2Server).AdminPauseQuiz(ctx, req.(*AdminPauseQuizRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuizServiceV2_AdminResumeQuiz_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminResumeQuizRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuizServiceV2Server).AdminResumeQuiz(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/quiz.QuizServiceV2/AdminResumeQuiz",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuizServiceV2Server).AdminResumeQuiz(ctx, req.(*AdminResumeQuizRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// QuizServiceV2_ServiceDesc is the grpc.ServiceDesc for QuizServiceV2 service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var QuizServiceV2_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "quiz.QuizServiceV2",
+	HandlerType: (*QuizServiceV2Server)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "JoinQuiz",
+			Handler:    _QuizServiceV2_JoinQuiz_Handler,
+		},
+		{
+			MethodName: "SubmitAnswer",
+			Handler:    _QuizServiceV2_SubmitAnswer_Handler,
+		},
+		{
+			MethodName: "GetLeaderBoard",
+			Handler:    _QuizServiceV2_GetLeaderBoard_Handler,
+		},
+		{
+			MethodName: "GetLeaderBoardRanks",
+			Handler:    _QuizServiceV2_GetLeaderBoardRanks_Handler,
+		},
+		{
+			MethodName: "AdminCreateQuiz",
+			Handler:    _QuizServiceV2_AdminCreateQuiz_Handler,
+		},
+		{
+			MethodName: "AdminUpdateQuiz",
+			Handler:    _QuizServiceV2_AdminUpdateQuiz_Handler,
+		},
+		{
+			MethodName: "AdminAddQuestion",
+			Handler:    _QuizServiceV2_AdminAddQuestion_Handler,
+		},
+		{
+			MethodName: "AdminReorderQuestions",
+			Handler:    _QuizServiceV2_AdminReorderQuestions_Handler,
+		},
+		{
+			MethodName: "AdminPublishQuiz",
+			Handler:    _QuizServiceV2_AdminPublishQuiz_Handler,
+		},
+		{
+			MethodName: "AdminCloseQuiz",
+			Handler:    _QuizServiceV2_AdminCloseQuiz_Handler,
+		},
+		{
+			MethodName: "AdminDeleteQuiz",
+			Handler:    _QuizServiceV2_AdminDeleteQuiz_Handler,
+		},
+		{
+			MethodName: "AdminPauseQuiz",
+			Handler:    _QuizServiceV2_AdminPauseQuiz_Handler,
+		},
+		{
+			MethodName: "AdminResumeQuiz",
+			Handler:    _QuizServiceV2_AdminResumeQuiz_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "quiz_api.proto",
+}
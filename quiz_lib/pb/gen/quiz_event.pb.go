@@ -0,0 +1,392 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        v5.27.1
+// source: quiz_event.proto
+
+package quiz_api
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type QuizEventType int32
+
+const (
+	QuizEventType_QUIZ_EVENT_UNKNOWN            QuizEventType = 0
+	QuizEventType_QUIZ_EVENT_PARTICIPANT_JOINED QuizEventType = 1
+	QuizEventType_QUIZ_EVENT_SCORE_CHANGED      QuizEventType = 2
+	QuizEventType_QUIZ_EVENT_STATUS_CHANGED     QuizEventType = 3
+)
+
+// Enum value maps for QuizEventType.
+var (
+	QuizEventType_name = map[int32]string{
+		0: "QUIZ_EVENT_UNKNOWN",
+		1: "QUIZ_EVENT_PARTICIPANT_JOINED",
+		2: "QUIZ_EVENT_SCORE_CHANGED",
+		3: "QUIZ_EVENT_STATUS_CHANGED",
+	}
+	QuizEventType_value = map[string]int32{
+		"QUIZ_EVENT_UNKNOWN":            0,
+		"QUIZ_EVENT_PARTICIPANT_JOINED": 1,
+		"QUIZ_EVENT_SCORE_CHANGED":      2,
+		"QUIZ_EVENT_STATUS_CHANGED":     3,
+	}
+)
+
+func (x QuizEventType) Enum() *QuizEventType {
+	p := new(QuizEventType)
+	*p = x
+	return p
+}
+
+func (x QuizEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (QuizEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_quiz_event_proto_enumTypes[0].Descriptor()
+}
+
+func (QuizEventType) Type() protoreflect.EnumType {
+	return &file_quiz_event_proto_enumTypes[0]
+}
+
+func (x QuizEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use QuizEventType.Descriptor instead.
+func (QuizEventType) EnumDescriptor() ([]byte, []int) {
+	return file_quiz_event_proto_rawDescGZIP(), []int{0}
+}
+
+// / QuizEvent is published to the quiz event topic, keyed by quiz_id.
+// / Fields can be added freely, version is bumped on incompatible changes only.
+type QuizEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version     int32         `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Type        QuizEventType `protobuf:"varint,2,opt,name=type,proto3,enum=quiz.QuizEventType" json:"type,omitempty"`
+	QuizId      int64         `protobuf:"varint,3,opt,name=quiz_id,json=quizId,proto3" json:"quiz_id,omitempty"`
+	UserId      int64         `protobuf:"varint,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Delta       int32         `protobuf:"varint,5,opt,name=delta,proto3" json:"delta,omitempty"`
+	CreatedTime int64         `protobuf:"varint,6,opt,name=created_time,json=createdTime,proto3" json:"created_time,omitempty"`
+	// set by QUIZ_EVENT_STATUS_CHANGED, values of model.QuizStatus*
+	FromStatus int32 `protobuf:"varint,7,opt,name=from_status,json=fromStatus,proto3" json:"from_status,omitempty"`
+	ToStatus   int32 `protobuf:"varint,8,opt,name=to_status,json=toStatus,proto3" json:"to_status,omitempty"`
+	// the state written by the change. The consumers rebuild the leader board from database
+	// when a participant event has no state, e.g. when it was published by an older server.
+	//
+	// Types that are assignable to State:
+	//	*QuizEvent_Participant
+	State isQuizEvent_State `protobuf_oneof:"state"`
+}
+
+func (x *QuizEvent) Reset() {
+	*x = QuizEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_quiz_event_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QuizEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuizEvent) ProtoMessage() {}
+
+func (x *QuizEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_event_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuizEvent.ProtoReflect.Descriptor instead.
+func (*QuizEvent) Descriptor() ([]byte, []int) {
+	return file_quiz_event_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *QuizEvent) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *QuizEvent) GetType() QuizEventType {
+	if x != nil {
+		return x.Type
+	}
+	return QuizEventType_QUIZ_EVENT_UNKNOWN
+}
+
+func (x *QuizEvent) GetQuizId() int64 {
+	if x != nil {
+		return x.QuizId
+	}
+	return 0
+}
+
+func (x *QuizEvent) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *QuizEvent) GetDelta() int32 {
+	if x != nil {
+		return x.Delta
+	}
+	return 0
+}
+
+func (x *QuizEvent) GetCreatedTime() int64 {
+	if x != nil {
+		return x.CreatedTime
+	}
+	return 0
+}
+
+func (x *QuizEvent) GetFromStatus() int32 {
+	if x != nil {
+		return x.FromStatus
+	}
+	return 0
+}
+
+func (x *QuizEvent) GetToStatus() int32 {
+	if x != nil {
+		return x.ToStatus
+	}
+	return 0
+}
+
+func (m *QuizEvent) GetState() isQuizEvent_State {
+	if m != nil {
+		return m.State
+	}
+	return nil
+}
+
+func (x *QuizEvent) GetParticipant() *ParticipantState {
+	if x, ok := x.GetState().(*QuizEvent_Participant); ok {
+		return x.Participant
+	}
+	return nil
+}
+
+type isQuizEvent_State interface {
+	isQuizEvent_State()
+}
+
+type QuizEvent_Participant struct {
+	Participant *ParticipantState `protobuf:"bytes,9,opt,name=participant,proto3,oneof"`
+}
+
+func (*QuizEvent_Participant) isQuizEvent_State() {}
+
+// / ParticipantState is the participant right after the change of a QUIZ_EVENT_PARTICIPANT_JOINED
+// / or QUIZ_EVENT_SCORE_CHANGED event, delta is the score added by the change
+type ParticipantState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Score       int32 `protobuf:"varint,1,opt,name=score,proto3" json:"score,omitempty"`
+	UpdatedTime int64 `protobuf:"varint,2,opt,name=updated_time,json=updatedTime,proto3" json:"updated_time,omitempty"`
+}
+
+func (x *ParticipantState) Reset() {
+	*x = ParticipantState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_quiz_event_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ParticipantState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParticipantState) ProtoMessage() {}
+
+func (x *ParticipantState) ProtoReflect() protoreflect.Message {
+	mi := &file_quiz_event_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParticipantState.ProtoReflect.Descriptor instead.
+func (*ParticipantState) Descriptor() ([]byte, []int) {
+	return file_quiz_event_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ParticipantState) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *ParticipantState) GetUpdatedTime() int64 {
+	if x != nil {
+		return x.UpdatedTime
+	}
+	return 0
+}
+
+var File_quiz_event_proto protoreflect.FileDescriptor
+
+var file_quiz_event_proto_rawDesc = []byte{
+	0x0a, 0x10, 0x71, 0x75, 0x69, 0x7a, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x04, 0x71, 0x75, 0x69, 0x7a, 0x22, 0xbc, 0x02, 0x0a, 0x09, 0x51, 0x75, 0x69,
+	0x7a, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x27, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13,
+	0x2e, 0x71, 0x75, 0x69, 0x7a, 0x2e, 0x51, 0x75, 0x69, 0x7a, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54,
+	0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x71, 0x75, 0x69,
+	0x7a, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x71, 0x75, 0x69, 0x7a,
+	0x49, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x64,
+	0x65, 0x6c, 0x74, 0x61, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x64, 0x65, 0x6c, 0x74,
+	0x61, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x74, 0x69, 0x6d,
+	0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64,
+	0x54, 0x69, 0x6d, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x66, 0x72, 0x6f, 0x6d, 0x5f, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x66, 0x72, 0x6f, 0x6d, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x6f, 0x5f, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x74, 0x6f, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x3a, 0x0a, 0x0b, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e,
+	0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x71, 0x75, 0x69, 0x7a, 0x2e, 0x50,
+	0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x48,
+	0x00, 0x52, 0x0b, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x42, 0x07,
+	0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x22, 0x4b, 0x0a, 0x10, 0x50, 0x61, 0x72, 0x74, 0x69,
+	0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73,
+	0x63, 0x6f, 0x72, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x72,
+	0x65, 0x12, 0x21, 0x0a, 0x0c, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x74, 0x69, 0x6d,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64,
+	0x54, 0x69, 0x6d, 0x65, 0x2a, 0x87, 0x01, 0x0a, 0x0d, 0x51, 0x75, 0x69, 0x7a, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x16, 0x0a, 0x12, 0x51, 0x55, 0x49, 0x5a, 0x5f, 0x45,
+	0x56, 0x45, 0x4e, 0x54, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x21,
+	0x0a, 0x1d, 0x51, 0x55, 0x49, 0x5a, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x50, 0x41, 0x52,
+	0x54, 0x49, 0x43, 0x49, 0x50, 0x41, 0x4e, 0x54, 0x5f, 0x4a, 0x4f, 0x49, 0x4e, 0x45, 0x44, 0x10,
+	0x01, 0x12, 0x1c, 0x0a, 0x18, 0x51, 0x55, 0x49, 0x5a, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f,
+	0x53, 0x43, 0x4f, 0x52, 0x45, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x47, 0x45, 0x44, 0x10, 0x02, 0x12,
+	0x1d, 0x0a, 0x19, 0x51, 0x55, 0x49, 0x5a, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x53, 0x54,
+	0x41, 0x54, 0x55, 0x53, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x47, 0x45, 0x44, 0x10, 0x03, 0x42, 0x0d,
+	0x5a, 0x0b, 0x70, 0x62, 0x2f, 0x71, 0x75, 0x69, 0x7a, 0x5f, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_quiz_event_proto_rawDescOnce sync.Once
+	file_quiz_event_proto_rawDescData = file_quiz_event_proto_rawDesc
+)
+
+func file_quiz_event_proto_rawDescGZIP() []byte {
+	file_quiz_event_proto_rawDescOnce.Do(func() {
+		file_quiz_event_proto_rawDescData = protoimpl.X.CompressGZIP(file_quiz_event_proto_rawDescData)
+	})
+	return file_quiz_event_proto_rawDescData
+}
+
+var file_quiz_event_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_quiz_event_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_quiz_event_proto_goTypes = []interface{}{
+	(QuizEventType)(0),       // 0: quiz.QuizEventType
+	(*QuizEvent)(nil),        // 1: quiz.QuizEvent
+	(*ParticipantState)(nil), // 2: quiz.ParticipantState
+}
+var file_quiz_event_proto_depIdxs = []int32{
+	0, // 0: quiz.QuizEvent.type:type_name -> quiz.QuizEventType
+	2, // 1: quiz.QuizEvent.participant:type_name -> quiz.ParticipantState
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_quiz_event_proto_init() }
+func file_quiz_event_proto_init() {
+	if File_quiz_event_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_quiz_event_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QuizEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_quiz_event_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ParticipantState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_quiz_event_proto_msgTypes[0].OneofWrappers = []interface{}{
+		(*QuizEvent_Participant)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_quiz_event_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_quiz_event_proto_goTypes,
+		DependencyIndexes: file_quiz_event_proto_depIdxs,
+		EnumInfos:         file_quiz_event_proto_enumTypes,
+		MessageInfos:      file_quiz_event_proto_msgTypes,
+	}.Build()
+	File_quiz_event_proto = out.File
+	file_quiz_event_proto_rawDesc = nil
+	file_quiz_event_proto_goTypes = nil
+	file_quiz_event_proto_depIdxs = nil
+}
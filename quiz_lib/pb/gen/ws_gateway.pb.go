@@ -0,0 +1,345 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        v5.27.1
+// source: ws_gateway.proto
+
+package quiz_api
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// / ClientFrame is a binary websocket message sent by a client to the websocket gateway
+type ClientFrame struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Seq     int64        `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	Request *RequestData `protobuf:"bytes,2,opt,name=request,proto3" json:"request,omitempty"`
+}
+
+func (x *ClientFrame) Reset() {
+	*x = ClientFrame{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ws_gateway_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ClientFrame) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientFrame) ProtoMessage() {}
+
+func (x *ClientFrame) ProtoReflect() protoreflect.Message {
+	mi := &file_ws_gateway_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientFrame.ProtoReflect.Descriptor instead.
+func (*ClientFrame) Descriptor() ([]byte, []int) {
+	return file_ws_gateway_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ClientFrame) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *ClientFrame) GetRequest() *RequestData {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+// / ServerFrame is a binary websocket message sent by the websocket gateway to a client.
+// / seq is the seq of the ClientFrame it replies to, or 0 for a push.
+type ServerFrame struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Seq         int64            `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	Response    *ResponseData    `protobuf:"bytes,2,opt,name=response,proto3" json:"response,omitempty"`
+	LeaderBoard *LeaderBoardPush `protobuf:"bytes,3,opt,name=leader_board,json=leaderBoard,proto3" json:"leader_board,omitempty"`
+}
+
+func (x *ServerFrame) Reset() {
+	*x = ServerFrame{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ws_gateway_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ServerFrame) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerFrame) ProtoMessage() {}
+
+func (x *ServerFrame) ProtoReflect() protoreflect.Message {
+	mi := &file_ws_gateway_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerFrame.ProtoReflect.Descriptor instead.
+func (*ServerFrame) Descriptor() ([]byte, []int) {
+	return file_ws_gateway_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ServerFrame) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *ServerFrame) GetResponse() *ResponseData {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+func (x *ServerFrame) GetLeaderBoard() *LeaderBoardPush {
+	if x != nil {
+		return x.LeaderBoard
+	}
+	return nil
+}
+
+// / LeaderBoardPush is pushed to the clients of a quiz when its leader board changes.
+// / own is unset if the user is not on the leader board.
+type LeaderBoardPush struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	QuizId int64               `protobuf:"varint,1,opt,name=quiz_id,json=quizId,proto3" json:"quiz_id,omitempty"`
+	Top    []*LeaderBoardEntry `protobuf:"bytes,2,rep,name=top,proto3" json:"top,omitempty"`
+	Own    *LeaderBoardEntry   `protobuf:"bytes,3,opt,name=own,proto3" json:"own,omitempty"`
+	Total  int64               `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+}
+
+func (x *LeaderBoardPush) Reset() {
+	*x = LeaderBoardPush{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ws_gateway_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LeaderBoardPush) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaderBoardPush) ProtoMessage() {}
+
+func (x *LeaderBoardPush) ProtoReflect() protoreflect.Message {
+	mi := &file_ws_gateway_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaderBoardPush.ProtoReflect.Descriptor instead.
+func (*LeaderBoardPush) Descriptor() ([]byte, []int) {
+	return file_ws_gateway_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *LeaderBoardPush) GetQuizId() int64 {
+	if x != nil {
+		return x.QuizId
+	}
+	return 0
+}
+
+func (x *LeaderBoardPush) GetTop() []*LeaderBoardEntry {
+	if x != nil {
+		return x.Top
+	}
+	return nil
+}
+
+func (x *LeaderBoardPush) GetOwn() *LeaderBoardEntry {
+	if x != nil {
+		return x.Own
+	}
+	return nil
+}
+
+func (x *LeaderBoardPush) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+var File_ws_gateway_proto protoreflect.FileDescriptor
+
+var file_ws_gateway_proto_rawDesc = []byte{
+	0x0a, 0x10, 0x77, 0x73, 0x5f, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x04, 0x71, 0x75, 0x69, 0x7a, 0x1a, 0x0e, 0x71, 0x75, 0x69, 0x7a, 0x5f, 0x61,
+	0x70, 0x69, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x4c, 0x0a, 0x0b, 0x43, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x65, 0x71, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x73, 0x65, 0x71, 0x12, 0x2b, 0x0a, 0x07, 0x72, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x71, 0x75, 0x69,
+	0x7a, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52, 0x07, 0x72,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x89, 0x01, 0x0a, 0x0b, 0x53, 0x65, 0x72, 0x76, 0x65,
+	0x72, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x65, 0x71, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x03, 0x73, 0x65, 0x71, 0x12, 0x2e, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x71, 0x75, 0x69,
+	0x7a, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x08,
+	0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x38, 0x0a, 0x0c, 0x6c, 0x65, 0x61, 0x64,
+	0x65, 0x72, 0x5f, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15,
+	0x2e, 0x71, 0x75, 0x69, 0x7a, 0x2e, 0x4c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x42, 0x6f, 0x61, 0x72,
+	0x64, 0x50, 0x75, 0x73, 0x68, 0x52, 0x0b, 0x6c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x42, 0x6f, 0x61,
+	0x72, 0x64, 0x22, 0x94, 0x01, 0x0a, 0x0f, 0x4c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x42, 0x6f, 0x61,
+	0x72, 0x64, 0x50, 0x75, 0x73, 0x68, 0x12, 0x17, 0x0a, 0x07, 0x71, 0x75, 0x69, 0x7a, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x71, 0x75, 0x69, 0x7a, 0x49, 0x64, 0x12,
+	0x28, 0x0a, 0x03, 0x74, 0x6f, 0x70, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x71,
+	0x75, 0x69, 0x7a, 0x2e, 0x4c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x42, 0x6f, 0x61, 0x72, 0x64, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x03, 0x74, 0x6f, 0x70, 0x12, 0x28, 0x0a, 0x03, 0x6f, 0x77, 0x6e,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x71, 0x75, 0x69, 0x7a, 0x2e, 0x4c, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x42, 0x6f, 0x61, 0x72, 0x64, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x03,
+	0x6f, 0x77, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x0d, 0x5a, 0x0b, 0x70, 0x62, 0x2f,
+	0x71, 0x75, 0x69, 0x7a, 0x5f, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_ws_gateway_proto_rawDescOnce sync.Once
+	file_ws_gateway_proto_rawDescData = file_ws_gateway_proto_rawDesc
+)
+
+func file_ws_gateway_proto_rawDescGZIP() []byte {
+	file_ws_gateway_proto_rawDescOnce.Do(func() {
+		file_ws_gateway_proto_rawDescData = protoimpl.X.CompressGZIP(file_ws_gateway_proto_rawDescData)
+	})
+	return file_ws_gateway_proto_rawDescData
+}
+
+var file_ws_gateway_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_ws_gateway_proto_goTypes = []interface{}{
+	(*ClientFrame)(nil),      // 0: quiz.ClientFrame
+	(*ServerFrame)(nil),      // 1: quiz.ServerFrame
+	(*LeaderBoardPush)(nil),  // 2: quiz.LeaderBoardPush
+	(*RequestData)(nil),      // 3: quiz.RequestData
+	(*ResponseData)(nil),     // 4: quiz.ResponseData
+	(*LeaderBoardEntry)(nil), // 5: quiz.LeaderBoardEntry
+}
+var file_ws_gateway_proto_depIdxs = []int32{
+	3, // 0: quiz.ClientFrame.request:type_name -> quiz.RequestData
+	4, // 1: quiz.ServerFrame.response:type_name -> quiz.ResponseData
+	2, // 2: quiz.ServerFrame.leader_board:type_name -> quiz.LeaderBoardPush
+	5, // 3: quiz.LeaderBoardPush.top:type_name -> quiz.LeaderBoardEntry
+	5, // 4: quiz.LeaderBoardPush.own:type_name -> quiz.LeaderBoardEntry
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_ws_gateway_proto_init() }
+func file_ws_gateway_proto_init() {
+	if File_ws_gateway_proto != nil {
+		return
+	}
+	file_quiz_api_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_ws_gateway_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ClientFrame); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ws_gateway_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ServerFrame); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ws_gateway_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LeaderBoardPush); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_ws_gateway_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_ws_gateway_proto_goTypes,
+		DependencyIndexes: file_ws_gateway_proto_depIdxs,
+		MessageInfos:      file_ws_gateway_proto_msgTypes,
+	}.Build()
+	File_ws_gateway_proto = out.File
+	file_ws_gateway_proto_rawDesc = nil
+	file_ws_gateway_proto_goTypes = nil
+	file_ws_gateway_proto_depIdxs = nil
+}
@@ -0,0 +1,149 @@
+// Package quiz_error separates the business rejections of a request from the internal faults. A rejection
+// is an *Error, it's answered with its pb.Error and grpc code. Any other error is a fault, answered with
+// ERROR_INTERNAL and a generic message so that internal messages, e.g. SQL text, never reach the clients.
+package quiz_error
+
+import (
+	"errors"
+	"fmt"
+
+	pb "github.com/luulethe/quiz/quiz_lib/pb/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const internalMessage = "internal error"
+
+var (
+	ErrQuizNotExisted     = New(pb.Error_ERROR_QUIZ_NOT_EXITED)
+	ErrQuizFinished       = New(pb.Error_ERROR_QUIZ_FINISHED)
+	ErrQuizNotStarted     = New(pb.Error_ERROR_QUIZ_NOT_STARTED)
+	ErrQuizPaused         = New(pb.Error_ERROR_QUIZ_PAUSED)
+	ErrInvalidQuizStatus  = New(pb.Error_ERROR_INVALID_QUIZ_STATUS)
+	ErrUserNotExisted     = New(pb.Error_ERROR_USER_NOT_EXISTED)
+	ErrUserJoined         = New(pb.Error_ERROR_USER_JOINED)
+	ErrUserNotJoined      = New(pb.Error_ERROR_USER_NOT_JOINED)
+	ErrQuestionNotExisted = New(pb.Error_ERROR_QUESTION_NOT_EXISTED)
+	ErrQuestionClosed     = New(pb.Error_ERROR_QUESTION_CLOSED)
+	ErrAnswerNotExisted   = New(pb.Error_ERROR_ANSWER_NOT_EXISTED)
+	ErrAnswerSubmitted    = New(pb.Error_ERROR_ANSWER_SUBMITTED)
+	ErrInvalidParameter   = New(pb.Error_ERROR_INVALID_PARAMETER)
+)
+
+// Error is a business rejection. The message is shown to the client, the cause is only logged.
+type Error struct {
+	Result  pb.Error
+	Message string
+	cause   error
+}
+
+func New(result pb.Error) *Error {
+	return &Error{Result: result}
+}
+
+// Newf rejects with a message for the client, e.g. the invalid parameter
+func Newf(result pb.Error, format string, args ...interface{}) *Error {
+	return &Error{Result: result, Message: fmt.Sprintf(format, args...)}
+}
+
+// Wrap rejects because of the cause, e.g. a request which can't be decoded
+func Wrap(result pb.Error, cause error) *Error {
+	return &Error{Result: result, cause: cause}
+}
+
+func (e *Error) Error() string {
+	message := e.Result.String()
+	if e.Message != "" {
+		message += ": " + e.Message
+	}
+	if e.cause != nil {
+		message += ": " + e.cause.Error()
+	}
+	return message
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is matches the rejections of the same result, so that errors.Is(err, ErrUserJoined) holds for any message
+func (e *Error) Is(target error) bool {
+	rejection, ok := target.(*Error)
+	return ok && rejection.Result == e.Result
+}
+
+// FromResult returns the rejection of a result, nil for ERROR_OK
+func FromResult(result pb.Error) error {
+	if result == pb.Error_ERROR_OK {
+		return nil
+	}
+	return New(result)
+}
+
+// AsRejection returns the rejection of err, false for nil and for a fault
+func AsRejection(err error) (*Error, bool) {
+	var rejection *Error
+	if errors.As(err, &rejection) && rejection.Result != pb.Error_ERROR_INTERNAL {
+		return rejection, true
+	}
+	return nil, false
+}
+
+// Result returns ERROR_OK for nil, the result of a rejection and ERROR_INTERNAL for a fault
+func Result(err error) pb.Error {
+	if err == nil {
+		return pb.Error_ERROR_OK
+	}
+	if rejection, ok := AsRejection(err); ok {
+		return rejection.Result
+	}
+	return pb.Error_ERROR_INTERNAL
+}
+
+// Status converts err to a grpc status with its result as ResultDetail, a fault only tells it's internal
+func Status(err error) *status.Status {
+	result := Result(err)
+	message := internalMessage
+	if rejection, ok := AsRejection(err); ok {
+		message = result.String()
+		if rejection.Message != "" {
+			message += ": " + rejection.Message
+		}
+	}
+
+	st := status.New(Code(result), message)
+	detailed, detailErr := st.WithDetails(&pb.ResultDetail{Result: result})
+	if detailErr != nil {
+		return st
+	}
+	return detailed
+}
+
+// Code maps a result to its grpc code
+func Code(result pb.Error) codes.Code {
+	switch result {
+	case pb.Error_ERROR_OK:
+		return codes.OK
+	case pb.Error_ERROR_QUIZ_NOT_EXITED, pb.Error_ERROR_USER_NOT_EXISTED,
+		pb.Error_ERROR_QUESTION_NOT_EXISTED, pb.Error_ERROR_ANSWER_NOT_EXISTED:
+		return codes.NotFound
+	case pb.Error_ERROR_USER_JOINED, pb.Error_ERROR_ANSWER_SUBMITTED:
+		return codes.AlreadyExists
+	case pb.Error_ERROR_INVALID_PARAMETER:
+		return codes.InvalidArgument
+	case pb.Error_ERROR_PERMISSION_DENIED:
+		return codes.PermissionDenied
+	case pb.Error_ERROR_UNAUTHENTICATED:
+		return codes.Unauthenticated
+	case pb.Error_ERROR_RATE_LIMITED:
+		return codes.ResourceExhausted
+	case pb.Error_ERROR_REQUEST_IN_PROGRESS:
+		return codes.Aborted
+	case pb.Error_ERROR_SERVICE_UNAVAILABLE:
+		return codes.Unavailable
+	case pb.Error_ERROR_QUIZ_FINISHED, pb.Error_ERROR_USER_NOT_JOINED, pb.Error_ERROR_QUIZ_NOT_STARTED,
+		pb.Error_ERROR_QUIZ_PAUSED, pb.Error_ERROR_QUESTION_CLOSED, pb.Error_ERROR_INVALID_QUIZ_STATUS:
+		return codes.FailedPrecondition
+	}
+	return codes.Internal
+}
@@ -0,0 +1,80 @@
+package quiz_error
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	pb "github.com/luulethe/quiz/quiz_lib/pb/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestError_Is(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		target error
+		is     bool
+	}{
+		{"sentinel", ErrUserJoined, ErrUserJoined, true},
+		{"same result", New(pb.Error_ERROR_USER_JOINED), ErrUserJoined, true},
+		{"message", Newf(pb.Error_ERROR_INVALID_PARAMETER, "name is empty"), ErrInvalidParameter, true},
+		{"cause", Wrap(pb.Error_ERROR_INVALID_PARAMETER, errors.New("bad json")), ErrInvalidParameter, true},
+		{"wrapped", fmt.Errorf("JoinQuiz: %w", ErrQuizNotExisted), ErrQuizNotExisted, true},
+		{"other result", ErrUserJoined, ErrUserNotJoined, false},
+		{"fault", errors.New("user joined"), ErrUserJoined, false},
+		{"not a rejection", ErrUserJoined, errors.New(pb.Error_ERROR_USER_JOINED.String()), false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.is, errors.Is(test.err, test.target))
+		})
+	}
+
+	// the cause is still found behind the rejection
+	assert.True(t, errors.Is(Wrap(pb.Error_ERROR_ANSWER_NOT_EXISTED, sql.ErrNoRows), sql.ErrNoRows))
+}
+
+func TestStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		result  pb.Error
+		code    codes.Code
+		message string
+	}{
+		{"rejection", ErrQuizFinished, pb.Error_ERROR_QUIZ_FINISHED, codes.FailedPrecondition, "ERROR_QUIZ_FINISHED"},
+		{"message", Newf(pb.Error_ERROR_INVALID_PARAMETER, "name is empty"), pb.Error_ERROR_INVALID_PARAMETER,
+			codes.InvalidArgument, "ERROR_INVALID_PARAMETER: name is empty"},
+		{"cause", Wrap(pb.Error_ERROR_INVALID_PARAMETER, errors.New("bad json")), pb.Error_ERROR_INVALID_PARAMETER,
+			codes.InvalidArgument, "ERROR_INVALID_PARAMETER"},
+		{"wrapped", fmt.Errorf("JoinQuiz: %w", ErrUserNotExisted), pb.Error_ERROR_USER_NOT_EXISTED,
+			codes.NotFound, "ERROR_USER_NOT_EXISTED"},
+		{"fault", errors.New("Error 1062: Duplicate entry 'x' for key 'PRIMARY'"), pb.Error_ERROR_INTERNAL,
+			codes.Internal, internalMessage},
+		{"wrapped fault", fmt.Errorf("insert: %w", sql.ErrConnDone), pb.Error_ERROR_INTERNAL,
+			codes.Internal, internalMessage},
+		{"internal rejection", Wrap(pb.Error_ERROR_INTERNAL, errors.New("select failed")), pb.Error_ERROR_INTERNAL,
+			codes.Internal, internalMessage},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.result, Result(test.err))
+			st := Status(test.err)
+			assert.Equal(t, test.code, st.Code())
+			// neither the causes nor the faults reach the client
+			assert.Equal(t, test.message, st.Message())
+			require.Len(t, st.Details(), 1)
+			detail, ok := st.Details()[0].(*pb.ResultDetail)
+			require.True(t, ok)
+			assert.Equal(t, test.result, detail.Result)
+		})
+	}
+
+	assert.Equal(t, pb.Error_ERROR_OK, Result(nil))
+	assert.NoError(t, FromResult(pb.Error_ERROR_OK))
+	assert.True(t, errors.Is(FromResult(pb.Error_ERROR_QUIZ_PAUSED), ErrQuizPaused))
+}
@@ -0,0 +1,289 @@
+package gateway
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/luulethe/quiz/go_common/log"
+	"github.com/luulethe/quiz/go_common/metrics"
+	"github.com/luulethe/quiz/quiz_lib/manager"
+	pb "github.com/luulethe/quiz/quiz_lib/pb/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// connection serves one websocket client. Frames are forwarded to the quiz server one by one
+// so the replies keep the order of the requests.
+type connection struct {
+	gateway    *Gateway
+	conn       *websocket.Conn
+	clientID   string
+	userID     int64
+	token      string
+	writeMutex sync.Mutex
+	pushes     chan *pb.LeaderBoardPush
+
+	quizMutex sync.Mutex
+	quizID    int64 // the quiz joined through this connection, 0 if none
+	lastPush  *pb.LeaderBoardPush
+	// registered is false while the joined quiz failed to register, the next heartbeat registers it again
+	registered bool
+}
+
+func newConnection(gateway *Gateway, conn *websocket.Conn, userID int64, token string) *connection {
+	return &connection{
+		gateway:  gateway,
+		conn:     conn,
+		clientID: uuid.New().String(),
+		userID:   userID,
+		token:    token,
+		pushes:   make(chan *pb.LeaderBoardPush, pushQueueSize),
+	}
+}
+
+func (c *connection) serve() {
+	ctx, cancel := context.WithCancel(c.gateway.ctx)
+	ctx = log.WithFields(ctx, log.Fields{"cid": c.clientID, "user_id": c.userID})
+	defer func() {
+		cancel()
+		_ = c.conn.Close()
+		c.leave(ctx)
+	}()
+	go c.keepAlive(ctx)
+	go c.writePushes(ctx)
+
+	c.conn.SetReadLimit(maxFrameSize)
+	_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		messageType, data, err := c.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				log.Errorff(ctx, "connection.serve|read|err:%v", err)
+			}
+			return
+		}
+		if messageType != websocket.BinaryMessage {
+			c.close(websocket.CloseUnsupportedData, "binary frame expected")
+			return
+		}
+
+		frame := &pb.ClientFrame{}
+		if err := proto.Unmarshal(data, frame); err != nil {
+			log.Errorff(ctx, "connection.serve|unmarshal|err:%v", err)
+			c.close(websocket.CloseUnsupportedData, "invalid frame")
+			return
+		}
+		if err := c.handle(ctx, frame); err != nil {
+			log.Errorff(ctx, "connection.serve|write|seq:%v|err:%v", frame.Seq, err)
+			return
+		}
+	}
+}
+
+func (c *connection) handle(ctx context.Context, frame *pb.ClientFrame) error {
+	if frame.Request == nil {
+		return c.write(&pb.ServerFrame{Seq: frame.Seq, Response: &pb.ResponseData{Result: pb.Error_ERROR_INVALID_PARAMETER}})
+	}
+
+	start := time.Now()
+	requestCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	requestCtx = metadata.AppendToOutgoingContext(requestCtx, AuthorizationKey, "Bearer "+c.token)
+
+	result := metrics.ResultSuccess
+	response, err := c.gateway.client.Handle(requestCtx, frame.Request)
+	if err != nil {
+		log.Errorff(ctx, "connection.handle|command:%v|seq:%v|err:%v", frame.Request.Command, frame.Seq, err)
+		result = metrics.ResultError
+		response = &pb.ResponseData{Result: pb.Error_ERROR_INTERNAL}
+		if status.Code(err) == codes.Unimplemented {
+			response.Result = pb.Error_ERROR_INVALID_PARAMETER
+		}
+	}
+	if frame.Request.Command == pb.Command_CMD_JOIN_QUIZ && isJoined(response.Result) {
+		c.join(ctx, frame.Request)
+	}
+	if c.gateway.stats != nil {
+		latency := float64(time.Since(start)) / float64(time.Millisecond)
+		c.gateway.stats.ReportCount(1, frame.Request.Command.String(), string(result))
+		c.gateway.stats.ReportLatency(latency, frame.Request.Command.String(), string(result))
+	}
+	return c.write(&pb.ServerFrame{Seq: frame.Seq, Response: response})
+}
+
+func isJoined(result pb.Error) bool {
+	return result == pb.Error_ERROR_OK || result == pb.Error_ERROR_USER_JOINED
+}
+
+// join registers the connection to the joined quiz, replacing the quiz joined before
+func (c *connection) join(ctx context.Context, request *pb.RequestData) {
+	requestData := &pb.JoinQuizRequest{}
+	if err := proto.Unmarshal(request.Request, requestData); err != nil {
+		return
+	}
+
+	c.quizMutex.Lock()
+	defer c.quizMutex.Unlock()
+	if c.quizID == requestData.QuizId {
+		return
+	}
+	if c.quizID != 0 {
+		c.unregister(ctx)
+	}
+	c.quizID = requestData.QuizId
+	c.lastPush = nil
+	c.register(ctx)
+}
+
+func (c *connection) joinedQuiz() int64 {
+	c.quizMutex.Lock()
+	defer c.quizMutex.Unlock()
+	return c.quizID
+}
+
+// push queues the leader board without waiting for the client, dropping the oldest queued one when the queue is full
+func (c *connection) push(push *pb.LeaderBoardPush) {
+	for {
+		select {
+		case c.pushes <- push:
+			return
+		default:
+		}
+		select {
+		case <-c.pushes:
+			c.gateway.reportCount("PushLeaderBoardDropped", metrics.ResultWarning)
+		default:
+		}
+	}
+}
+
+// writePushes sends the queued leader boards until the connection is closed
+func (c *connection) writePushes(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case push := <-c.pushes:
+			if err := c.writePush(push); err != nil {
+				log.Errorff(ctx, "connection.writePushes|quiz_id:%v|err:%v", push.QuizId, err)
+				return
+			}
+		}
+	}
+}
+
+// writePush sends the leader board unless the client has already received the same one
+func (c *connection) writePush(push *pb.LeaderBoardPush) error {
+	c.quizMutex.Lock()
+	skip := c.quizID != push.QuizId || proto.Equal(c.lastPush, push)
+	c.quizMutex.Unlock()
+	if skip {
+		return nil
+	}
+
+	err := c.write(&pb.ServerFrame{LeaderBoard: push})
+	if err != nil {
+		return err
+	}
+	c.quizMutex.Lock()
+	if c.quizID == push.QuizId {
+		c.lastPush = push
+	}
+	c.quizMutex.Unlock()
+	return nil
+}
+
+func (c *connection) leave(ctx context.Context) {
+	c.quizMutex.Lock()
+	defer c.quizMutex.Unlock()
+	if c.quizID != 0 {
+		c.unregister(ctx)
+		c.quizID = 0
+	}
+}
+
+// reregister registers the joined quiz again: every connection after this node has been reaped from the registry,
+// otherwise only the connections which failed to register
+func (c *connection) reregister(ctx context.Context, reaped bool) {
+	c.quizMutex.Lock()
+	defer c.quizMutex.Unlock()
+	if c.quizID != 0 && (reaped || !c.registered) {
+		c.register(ctx)
+	}
+}
+
+func (c *connection) register(ctx context.Context) {
+	if c.gateway.registry == nil {
+		return
+	}
+	err := c.gateway.registry.Register(ctx, c.registryConnection())
+	c.registered = err == nil
+	if err != nil {
+		log.Errorff(ctx, "connection.register|quiz_id:%v|err:%v", c.quizID, err)
+		c.gateway.reportCount("Register", metrics.ResultError)
+	}
+}
+
+func (c *connection) unregister(ctx context.Context) {
+	if c.gateway.registry == nil {
+		return
+	}
+	err := c.gateway.registry.Unregister(ctx, c.registryConnection())
+	c.registered = false
+	if err != nil {
+		log.Errorff(ctx, "connection.unregister|quiz_id:%v|err:%v", c.quizID, err)
+		c.gateway.reportCount("Unregister", metrics.ResultError)
+	}
+}
+
+func (c *connection) registryConnection() *manager.Connection {
+	return &manager.Connection{ClientID: c.clientID, UserID: c.userID, QuizID: c.quizID, NodeID: c.gateway.nodeID}
+}
+
+func (c *connection) write(frame *pb.ServerFrame) error {
+	data, err := proto.Marshal(frame)
+	if err != nil {
+		return err
+	}
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+	_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+	return c.conn.WriteMessage(websocket.BinaryMessage, data)
+}
+
+func (c *connection) close(code int, text string) {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+	_ = c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, text), time.Now().Add(writeWait))
+}
+
+// keepAlive pings the client until the connection is closed, a client not answering
+// within pongWait makes the read loop time out. It also unblocks the read loop when
+// the gateway is shutting down.
+func (c *connection) keepAlive(ctx context.Context) {
+	ticker := time.NewTicker(pingPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			_ = c.conn.Close()
+			return
+		case <-ticker.C:
+			c.writeMutex.Lock()
+			err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait))
+			c.writeMutex.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
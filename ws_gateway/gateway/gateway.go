@@ -0,0 +1,147 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/luulethe/quiz/go_common/auth"
+	"github.com/luulethe/quiz/go_common/log"
+	"github.com/luulethe/quiz/go_common/metrics"
+	"github.com/luulethe/quiz/quiz_lib/manager"
+	pb "github.com/luulethe/quiz/quiz_lib/pb/gen"
+)
+
+const (
+	// AuthorizationKey is the gRPC metadata key carrying the client token to the quiz server
+	AuthorizationKey = "authorization"
+	// ServiceKeyMetadata is the gRPC metadata key carrying the service key of the gateway's own commands
+	ServiceKeyMetadata = "x-service-key"
+)
+
+const (
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = pongWait * 9 / 10
+	requestTimeout = 10 * time.Second
+	maxFrameSize   = 64 * 1024
+)
+
+// Authenticator authenticates the websocket handshake request and returns the user id and its token
+type Authenticator interface {
+	Authenticate(r *http.Request) (int64, string, error)
+}
+
+type tokenAuthenticator struct {
+	verifier *auth.TokenVerifier
+}
+
+// NewTokenAuthenticator reads the token from the "Authorization: Bearer" header,
+// or from the "token" query parameter for browsers which can't set websocket headers
+func NewTokenAuthenticator(verifier *auth.TokenVerifier) Authenticator {
+	return &tokenAuthenticator{verifier: verifier}
+}
+
+func (a *tokenAuthenticator) Authenticate(r *http.Request) (int64, string, error) {
+	token := r.URL.Query().Get("token")
+	if header := r.Header.Get("Authorization"); strings.HasPrefix(header, "Bearer ") {
+		token = strings.TrimPrefix(header, "Bearer ")
+	}
+	if token == "" {
+		return 0, "", auth.ErrInvalidToken
+	}
+	userID, err := a.verifier.Verify(token)
+	if err != nil {
+		return 0, "", err
+	}
+	return userID, token, nil
+}
+
+// Gateway upgrades http requests to websocket connections and forwards their frames to the quiz server
+type Gateway struct {
+	ctx           context.Context
+	client        pb.QuizServiceClient
+	authenticator Authenticator
+	upgrader      websocket.Upgrader
+	stats         *metrics.StatsCollector
+
+	// registry is optional, without it the connections are not visible to the other services
+	registry    manager.ConnectionRegistry
+	nodeID      string
+	mutex       sync.Mutex
+	connections map[string]*connection
+	wg          sync.WaitGroup
+}
+
+func NewGateway(
+	ctx context.Context, client pb.QuizServiceClient, authenticator Authenticator,
+	registry manager.ConnectionRegistry, nodeID string, stats *metrics.StatsCollector,
+) *Gateway {
+	return &Gateway{
+		ctx:           ctx,
+		client:        client,
+		authenticator: authenticator,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  4096,
+			WriteBufferSize: 4096,
+			CheckOrigin:     func(r *http.Request) bool { return true },
+		},
+		stats:       stats,
+		registry:    registry,
+		nodeID:      nodeID,
+		connections: map[string]*connection{},
+	}
+}
+
+// Wait waits for the served connections to be closed
+func (g *Gateway) Wait() {
+	g.wg.Wait()
+}
+
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	userID, token, err := g.authenticator.Authenticate(r)
+	if err != nil {
+		g.reportCount("Authenticate", metrics.ResultError)
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	conn, err := g.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// the upgrader has replied with an http error
+		log.Errorff(g.ctx, "Gateway.ServeHTTP|upgrade|user_id:%v|err:%v", userID, err)
+		g.reportCount("Upgrade", metrics.ResultError)
+		return
+	}
+
+	g.wg.Add(1)
+	defer g.wg.Done()
+	g.reportGauge(1, "Connection")
+	defer g.reportGauge(-1, "Connection")
+
+	c := newConnection(g, conn, userID, token)
+	g.mutex.Lock()
+	g.connections[c.clientID] = c
+	g.mutex.Unlock()
+	defer func() {
+		g.mutex.Lock()
+		delete(g.connections, c.clientID)
+		g.mutex.Unlock()
+	}()
+	c.serve()
+}
+
+func (g *Gateway) reportCount(action string, result metrics.ResultType) {
+	if g.stats != nil {
+		g.stats.ReportCount(1, action, string(result))
+	}
+}
+
+func (g *Gateway) reportGauge(gauge float64, action string) {
+	if g.stats != nil {
+		g.stats.ReportGauge(gauge, action, "")
+	}
+}
@@ -0,0 +1,200 @@
+package gateway_test
+
+import (
+	"context"
+	"net"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/websocket"
+	"github.com/luulethe/quiz/config"
+	"github.com/luulethe/quiz/go_common/cache"
+	"github.com/luulethe/quiz/quiz_api"
+	"github.com/luulethe/quiz/quiz_lib/db/model"
+	"github.com/luulethe/quiz/quiz_lib/manager"
+	"github.com/luulethe/quiz/quiz_lib/manager/managertest"
+	pb "github.com/luulethe/quiz/quiz_lib/pb/gen"
+	"github.com/luulethe/quiz/ws_gateway/gateway"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	testNodeID     = "test-node"
+	testAdminKey   = "test-admin-key"
+	testServiceKey = "test-service-key"
+	testTokenKeyID = "test"
+	testTokenKey   = "test-secret"
+)
+
+type testEnv struct {
+	dep    *manager.Dependency
+	dao    *managertest.QuizDAO
+	server *httptest.Server
+}
+
+// newTestEnv runs a quiz server over bufconn with an in-memory database and a miniredis,
+// and the gateway in front of it over httptest
+func newTestEnv(t *testing.T) *testEnv {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	redisServer := miniredis.RunT(t)
+	redisCache, err := cache.NewRedisClient(redisServer.Addr(), &cache.RedisOption{})
+	require.NoError(t, err)
+
+	conf := &config.Configuration{
+		Auth:      config.AuthConfig{Keys: []config.AuthKey{{ID: testTokenKeyID, Secret: testTokenKey}}},
+		Admin:     config.AdminConfig{Keys: []string{testAdminKey}, ServiceKeys: []string{testServiceKey}},
+		WSGateway: &config.WSGatewayConfig{},
+	}
+	dao := managertest.NewQuizDAO()
+	dep := &manager.Dependency{Conf: conf, Cache: redisCache, QuizDAO: dao}
+	dep.Auth = manager.NewTokenVerifier(conf.Auth)
+	dep.QuizManager = manager.NewQuizManager(dep)
+	dep.LeaderBoard = manager.NewLeaderBoard(dep)
+	dep.Connections = manager.NewConnectionRegistry(redisCache, conf.NodeTTL())
+	dep.Notifier = manager.NewLeaderBoardNotifier(dep)
+
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	pb.RegisterQuizServiceServer(grpcServer, quiz_api.NewQuizServer(ctx, dep))
+	go func() { _ = grpcServer.Serve(listener) }()
+
+	clientConn, err := grpc.DialContext(ctx, "bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithInsecure(),
+	)
+	require.NoError(t, err)
+
+	wsGateway := gateway.NewGateway(ctx, pb.NewQuizServiceClient(clientConn),
+		gateway.NewTokenAuthenticator(dep.Auth), dep.Connections, testNodeID, nil)
+	wg := &sync.WaitGroup{}
+	wsGateway.RunHeartbeat(ctx, wg, conf.NodeTTL())
+	wsGateway.RunPush(ctx, wg, redisCache.Client(), 10*time.Millisecond, 10, testServiceKey)
+	server := httptest.NewServer(wsGateway)
+
+	t.Cleanup(func() {
+		cancel()
+		server.Close()
+		wsGateway.Wait()
+		wg.Wait()
+		clientConn.Close()
+		grpcServer.Stop()
+		redisCache.Close()
+	})
+	return &testEnv{dep: dep, dao: dao, server: server}
+}
+
+func testToken(t *testing.T, userID int64) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   strconv.FormatInt(userID, 10),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	})
+	token.Header["kid"] = testTokenKeyID
+	signed, err := token.SignedString([]byte(testTokenKey))
+	require.NoError(t, err)
+	return signed
+}
+
+func (e *testEnv) dial(t *testing.T, token string) (*websocket.Conn, error) {
+	url := "ws" + strings.TrimPrefix(e.server.URL, "http") + "/ws?token=" + token
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err == nil {
+		t.Cleanup(func() { conn.Close() })
+	}
+	return conn, err
+}
+
+// createQuiz creates a quiz in progress with one question, it returns the quiz, the question and its correct answer
+func (e *testEnv) createQuiz(t *testing.T) (*model.QuizTab, *model.QuizQuestionTab, *model.QuizAnswerOptionTab) {
+	now := time.Now().UnixMilli()
+	err, quiz := e.dao.CreateQuiz(context.Background(), &model.QuizTab{
+		Status:    model.QuizStatusInProgress,
+		Name:      "quiz",
+		StartTime: now - 1000,
+		EndTime:   now + int64(time.Hour/time.Millisecond),
+	})
+	require.NoError(t, err)
+	correct := &model.QuizAnswerOptionTab{Content: "right", IsCorrect: true}
+	question := e.dao.AddQuestion(
+		&model.QuizQuestionTab{QuizID: quiz.ID, Content: "question", Score: 10, Seq: 1},
+		correct, &model.QuizAnswerOptionTab{Content: "wrong"},
+	)
+	return quiz, question, correct
+}
+
+func send(t *testing.T, conn *websocket.Conn, seq int64, command pb.Command, request proto.Message) {
+	data, err := proto.Marshal(request)
+	require.NoError(t, err)
+	frame, err := proto.Marshal(&pb.ClientFrame{Seq: seq, Request: &pb.RequestData{Command: command, Request: data}})
+	require.NoError(t, err)
+	require.NoError(t, conn.WriteMessage(websocket.BinaryMessage, frame))
+}
+
+func receive(t *testing.T, conn *websocket.Conn) *pb.ServerFrame {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	messageType, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, websocket.BinaryMessage, messageType)
+	frame := &pb.ServerFrame{}
+	require.NoError(t, proto.Unmarshal(data, frame))
+	return frame
+}
+
+func TestGateway_JoinSubmitAndPush(t *testing.T) {
+	env := newTestEnv(t)
+	quiz, question, correct := env.createQuiz(t)
+	conn, err := env.dial(t, testToken(t, 42))
+	require.NoError(t, err)
+
+	send(t, conn, 1, pb.Command_CMD_JOIN_QUIZ, &pb.JoinQuizRequest{QuizId: quiz.ID})
+	frame := receive(t, conn)
+	assert.Equal(t, int64(1), frame.Seq)
+	require.NotNil(t, frame.Response)
+	assert.Equal(t, pb.Error_ERROR_OK, frame.Response.Result)
+
+	send(t, conn, 2, pb.Command_CMD_SUBMIT_ANSWER, &pb.SubmitAnswerRequest{
+		QuizId: quiz.ID, QuestionId: question.ID, AnswerId: correct.ID,
+	})
+	frame = receive(t, conn)
+	assert.Equal(t, int64(2), frame.Seq)
+	require.NotNil(t, frame.Response)
+	require.Equal(t, pb.Error_ERROR_OK, frame.Response.Result)
+	reply := &pb.SubmitAnswerReply{}
+	require.NoError(t, proto.Unmarshal(frame.Response.Response, reply))
+	assert.True(t, reply.Correct)
+	assert.Equal(t, int32(10), reply.TotalScore)
+
+	// as the event consumer does once it has applied the score change, after the first heartbeat of the node
+	require.Eventually(t, func() bool {
+		err, nodes := env.dep.Connections.GetQuizNodes(context.Background(), quiz.ID)
+		return err == nil && len(nodes) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, env.dep.Notifier.Notify(context.Background(), quiz.ID))
+	frame = receive(t, conn)
+	assert.Equal(t, int64(0), frame.Seq)
+	require.NotNil(t, frame.LeaderBoard)
+	assert.Equal(t, quiz.ID, frame.LeaderBoard.QuizId)
+	assert.Equal(t, int64(1), frame.LeaderBoard.Total)
+	require.Len(t, frame.LeaderBoard.Top, 1)
+	assert.Equal(t, int64(42), frame.LeaderBoard.Top[0].UserId)
+	assert.Equal(t, int32(10), frame.LeaderBoard.Top[0].Score)
+	require.NotNil(t, frame.LeaderBoard.Own)
+	assert.Equal(t, int64(1), frame.LeaderBoard.Own.Rank)
+}
+
+func TestGateway_RejectsInvalidToken(t *testing.T) {
+	env := newTestEnv(t)
+
+	_, err := env.dial(t, "invalid")
+	assert.Equal(t, websocket.ErrBadHandshake, err)
+}
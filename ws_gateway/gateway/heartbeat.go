@@ -0,0 +1,71 @@
+package gateway
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/luulethe/quiz/config"
+	"github.com/luulethe/quiz/go_common/log"
+	"github.com/luulethe/quiz/go_common/metrics"
+)
+
+// RunHeartbeat keeps this node alive in the connection registry and reaps the crashed nodes,
+// the registry considers a node dead when it misses its heartbeat for nodeTTL, which must be the ttl of the registry
+func (g *Gateway) RunHeartbeat(ctx context.Context, wg *sync.WaitGroup, nodeTTL time.Duration) {
+	if g.registry == nil {
+		return
+	}
+	if nodeTTL <= 0 {
+		nodeTTL = config.DefaultNodeTTL
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(nodeTTL / 3)
+		defer ticker.Stop()
+		for {
+			g.heartbeat(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (g *Gateway) heartbeat(ctx context.Context) {
+	err, unknown := g.registry.Heartbeat(ctx, g.nodeID)
+	if err != nil {
+		log.Errorff(ctx, "Gateway.heartbeat|node_id:%s|err:%v", g.nodeID, err)
+		g.reportCount("Heartbeat", metrics.ResultError)
+		return
+	}
+	if unknown {
+		log.Infof(ctx, "Gateway.heartbeat|node %s is registered, registering its connections", g.nodeID)
+	}
+	for _, c := range g.listConnections() {
+		c.reregister(ctx, unknown)
+	}
+
+	err, reaped := g.registry.ReapStaleNodes(ctx)
+	if err != nil {
+		log.Errorff(ctx, "Gateway.heartbeat|reap|err:%v", err)
+		g.reportCount("ReapStaleNodes", metrics.ResultError)
+		return
+	}
+	if reaped > 0 {
+		log.Infof(ctx, "Gateway.heartbeat|reaped %d stale nodes", reaped)
+	}
+}
+
+func (g *Gateway) listConnections() []*connection {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	connections := make([]*connection, 0, len(g.connections))
+	for _, c := range g.connections {
+		connections = append(connections, c)
+	}
+	return connections
+}
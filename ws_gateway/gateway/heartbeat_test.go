@@ -0,0 +1,86 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/luulethe/quiz/quiz_lib/manager"
+	"github.com/stretchr/testify/assert"
+)
+
+// failingRegistry fails the first registers, the other methods aren't used
+type failingRegistry struct {
+	manager.ConnectionRegistry
+	mutex      sync.Mutex
+	fails      int
+	heartbeats int
+	unknown    bool
+	registered map[string]int64
+}
+
+func (r *failingRegistry) Register(ctx context.Context, connection *manager.Connection) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.fails > 0 {
+		r.fails--
+		return errors.New("redis unavailable")
+	}
+	r.registered[connection.ClientID] = connection.QuizID
+	return nil
+}
+
+func (r *failingRegistry) Unregister(ctx context.Context, connection *manager.Connection) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.registered, connection.ClientID)
+	return nil
+}
+
+func (r *failingRegistry) Heartbeat(ctx context.Context, nodeID string) (error, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.heartbeats++
+	return nil, r.unknown
+}
+
+func (r *failingRegistry) ReapStaleNodes(ctx context.Context) (error, int) {
+	return nil, 0
+}
+
+func TestHeartbeat_RegistersFailedConnections(t *testing.T) {
+	ctx := context.Background()
+	registry := &failingRegistry{fails: 1, registered: map[string]int64{}}
+	g := NewGateway(ctx, nil, nil, registry, "node", nil)
+	failed := newConnection(g, nil, 1, "")
+	joined := newConnection(g, nil, 2, "")
+	g.connections[failed.clientID] = failed
+	g.connections[joined.clientID] = joined
+
+	failed.quizID = 7
+	failed.register(ctx)
+	joined.quizID = 7
+	joined.register(ctx)
+	assert.Equal(t, map[string]int64{joined.clientID: 7}, registry.registered)
+
+	// each heartbeat retries the failed connection only, until it is registered
+	registry.fails = 1
+	g.heartbeat(ctx)
+	assert.Equal(t, 1, registry.heartbeats)
+	assert.Equal(t, 0, registry.fails)
+	assert.Equal(t, map[string]int64{joined.clientID: 7}, registry.registered)
+	g.heartbeat(ctx)
+	assert.Equal(t, map[string]int64{failed.clientID: 7, joined.clientID: 7}, registry.registered)
+
+	// all connections are registered again once the node has been reaped
+	registry.registered = map[string]int64{}
+	registry.unknown = true
+	g.heartbeat(ctx)
+	assert.Equal(t, map[string]int64{failed.clientID: 7, joined.clientID: 7}, registry.registered)
+
+	failed.leave(ctx)
+	registry.unknown = false
+	g.heartbeat(ctx)
+	assert.Equal(t, map[string]int64{joined.clientID: 7}, registry.registered)
+}
@@ -0,0 +1,168 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/luulethe/quiz/go_common/log"
+	"github.com/luulethe/quiz/go_common/metrics"
+	"github.com/luulethe/quiz/quiz_lib/manager"
+	pb "github.com/luulethe/quiz/quiz_lib/pb/gen"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	defaultPushInterval = time.Second
+	// rankUsersBatchSize is the max number of users of a CMD_GET_LEADERBOARD_RANKS request
+	rankUsersBatchSize = 1000
+	// pushQueueSize is the number of leader boards queued per connection, a newer leader board
+	// replaces the oldest queued one so that a slow client only receives the latest ones
+	pushQueueSize = 1
+)
+
+// RunPush subscribes to the leader board notifications of this node and pushes the leader board
+// of a changed quiz to its clients, at most once per interval per quiz.
+// A client only receives the leader board when the top, its own rank or the total has changed.
+// The ranks are read with serviceKey, as CMD_GET_LEADERBOARD_RANKS is reserved to the internal services.
+func (g *Gateway) RunPush(
+	ctx context.Context, wg *sync.WaitGroup, client *redis.Client, interval time.Duration, topN int32, serviceKey string,
+) {
+	if interval <= 0 {
+		interval = defaultPushInterval
+	}
+	if serviceKey != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, ServiceKeyMetadata, serviceKey)
+	}
+	pubSub := client.Subscribe(manager.WSNodeChannel(g.nodeID))
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer pubSub.Close()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		// pushing runs apart from the receive loop, the quizzes changed meanwhile are pushed on a next tick
+		pushing := make(chan struct{}, 1)
+		changedQuizzes := map[int64]struct{}{}
+		messages := pubSub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+				quizID, err := strconv.ParseInt(message.Payload, 10, 64)
+				if err != nil {
+					log.Errorff(ctx, "Gateway.RunPush|payload:%v|err:%v", message.Payload, err)
+					continue
+				}
+				changedQuizzes[quizID] = struct{}{}
+			case <-ticker.C:
+				if len(changedQuizzes) == 0 {
+					continue
+				}
+				select {
+				case pushing <- struct{}{}:
+				default:
+					continue
+				}
+				quizIDs := changedQuizzes
+				changedQuizzes = map[int64]struct{}{}
+				wg.Add(1)
+				go func() {
+					defer func() {
+						<-pushing
+						wg.Done()
+					}()
+					for quizID := range quizIDs {
+						g.pushLeaderBoard(ctx, quizID, topN)
+					}
+				}()
+			}
+		}
+	}()
+}
+
+func (g *Gateway) pushLeaderBoard(ctx context.Context, quizID int64, topN int32) {
+	connections := g.listQuizConnections(quizID)
+	if len(connections) == 0 {
+		return
+	}
+
+	userIDs := make([]int64, 0, len(connections))
+	seen := map[int64]bool{}
+	for _, c := range connections {
+		if !seen[c.userID] {
+			seen[c.userID] = true
+			userIDs = append(userIDs, c.userID)
+		}
+	}
+
+	// the top and the total are read with the first batch of users only
+	var reply *pb.GetLeaderBoardRanksReply
+	ranks := map[int64]*pb.LeaderBoardEntry{}
+	for start := 0; start < len(userIDs); start += rankUsersBatchSize {
+		end := start + rankUsersBatchSize
+		if end > len(userIDs) {
+			end = len(userIDs)
+		}
+		batchReply, err := g.getLeaderBoardRanks(ctx, &pb.GetLeaderBoardRanksRequest{
+			QuizId: quizID, TopN: topN, UserIds: userIDs[start:end], WithoutTop: reply != nil,
+		})
+		if err != nil {
+			log.Errorff(ctx, "Gateway.pushLeaderBoard|quiz_id:%v|err:%v", quizID, err)
+			g.reportCount("PushLeaderBoard", metrics.ResultError)
+			return
+		}
+		if reply == nil {
+			reply = batchReply
+		}
+		for _, entry := range batchReply.Ranks {
+			ranks[entry.UserId] = entry
+		}
+	}
+
+	for _, c := range connections {
+		c.push(&pb.LeaderBoardPush{QuizId: quizID, Top: reply.Top, Own: ranks[c.userID], Total: reply.Total})
+	}
+	g.reportCount("PushLeaderBoard", metrics.ResultSuccess)
+}
+
+func (g *Gateway) getLeaderBoardRanks(
+	ctx context.Context, request *pb.GetLeaderBoardRanksRequest,
+) (*pb.GetLeaderBoardRanksReply, error) {
+	data, err := proto.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	requestCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	response, err := g.client.Handle(requestCtx, &pb.RequestData{Command: pb.Command_CMD_GET_LEADERBOARD_RANKS, Request: data})
+	if err != nil {
+		return nil, err
+	}
+	if response.Result != pb.Error_ERROR_OK {
+		return nil, fmt.Errorf("get leader board ranks: %v", response.Result)
+	}
+
+	reply := &pb.GetLeaderBoardRanksReply{}
+	return reply, proto.Unmarshal(response.Response, reply)
+}
+
+func (g *Gateway) listQuizConnections(quizID int64) []*connection {
+	connections := make([]*connection, 0)
+	for _, c := range g.listConnections() {
+		if c.joinedQuiz() == quizID {
+			connections = append(connections, c)
+		}
+	}
+	return connections
+}
@@ -0,0 +1,82 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	pb "github.com/luulethe/quiz/quiz_lib/pb/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// newTestConnection returns a connection to the quiz 7 over a websocket, and the client side of it
+func newTestConnection(t *testing.T) (*connection, *websocket.Conn) {
+	conns := make(chan *websocket.Conn, 1)
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		require.NoError(t, err)
+		conns <- conn
+	}))
+	t.Cleanup(server.Close)
+
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	conn := <-conns
+	t.Cleanup(func() { conn.Close() })
+
+	c := newConnection(NewGateway(context.Background(), nil, nil, nil, "node", nil), conn, 1, "")
+	c.quizID = 7
+	return c, client
+}
+
+func receivePush(t *testing.T, client *websocket.Conn) *pb.LeaderBoardPush {
+	require.NoError(t, client.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, data, err := client.ReadMessage()
+	require.NoError(t, err)
+	frame := &pb.ServerFrame{}
+	require.NoError(t, proto.Unmarshal(data, frame))
+	return frame.LeaderBoard
+}
+
+func TestConnectionPush_DropsOldest(t *testing.T) {
+	c, client := newTestConnection(t)
+
+	// nothing sends the queue yet, as a client which doesn't read: the pushes don't wait for it
+	for total := int64(1); total <= 3; total++ {
+		c.push(&pb.LeaderBoardPush{QuizId: 7, Total: total})
+	}
+	require.Len(t, c.pushes, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.writePushes(ctx)
+	assert.Equal(t, int64(3), receivePush(t, client).Total)
+}
+
+func TestConnectionWritePush(t *testing.T) {
+	c, client := newTestConnection(t)
+	push := &pb.LeaderBoardPush{QuizId: 7, Total: 1}
+
+	require.NoError(t, c.writePush(push))
+	assert.Equal(t, int64(1), receivePush(t, client).Total)
+	// the same leader board and the leader board of another quiz are not sent
+	require.NoError(t, c.writePush(&pb.LeaderBoardPush{QuizId: 7, Total: 1}))
+	require.NoError(t, c.writePush(&pb.LeaderBoardPush{QuizId: 8, Total: 2}))
+	require.NoError(t, client.SetReadDeadline(time.Now().Add(20*time.Millisecond)))
+	_, _, err := client.ReadMessage()
+	assert.Error(t, err)
+
+	// a leader board failed to write is sent again by the next push
+	require.NoError(t, c.conn.Close())
+	next := &pb.LeaderBoardPush{QuizId: 7, Total: 2}
+	assert.Error(t, c.writePush(next))
+	assert.True(t, proto.Equal(push, c.lastPush))
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/luulethe/quiz/config"
+	"github.com/luulethe/quiz/go_common/log"
+	"github.com/luulethe/quiz/go_common/metrics"
+	"github.com/luulethe/quiz/go_common/sentry"
+	"github.com/luulethe/quiz/go_common/util"
+	"github.com/luulethe/quiz/quiz_lib/manager"
+	pb "github.com/luulethe/quiz/quiz_lib/pb/gen"
+	"github.com/luulethe/quiz/ws_gateway/gateway"
+	"google.golang.org/grpc"
+)
+
+var (
+	confFlags  = config.RegisterFlags(flag.CommandLine)
+	consoleLog = flag.Bool("console", true, "enable console log")
+	logPath    = os.Getenv("APP_LOG_PATH")
+)
+
+const shutdownTimeout = 10 * time.Second
+
+func main() {
+	flag.Parse()
+	config, err := confFlags.Load()
+	util.ExitOnErr(context.Background(), err)
+	if confFlags.PrintConfig {
+		util.ExitOnErr(context.Background(), config.WriteMasked(os.Stdout))
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	if config.WSGateway == nil {
+		util.ExitOnErr(ctx, fmt.Errorf("missing ws_gateway config"))
+	}
+
+	if config.SentryDNS != "" {
+		err := sentry.Init(config.SentryDNS)
+		util.ExitOnErr(ctx, err)
+		defer sentry.Recover()
+	}
+
+	ctx = util.InitLog(ctx, logPath, config.Debug, *consoleLog, log.FileConfig{
+		log.ErrorLevel: {"error.log", "info.log"},
+		log.InfoLevel:  {"info.log"},
+	})
+	defer log.Flush(ctx)
+	log.Debugf(ctx, "config: %v\nStarting websocket gateway\n", config)
+
+	stats := metrics.StartMonitor(ctx, "ws_gateway", config.ProfileAddr)
+
+	clientConn, err := grpc.Dial(config.WSGateway.QuizServerAddr, grpc.WithInsecure())
+	util.ExitOnErr(ctx, err)
+	defer clientConn.Close()
+
+	redisCache, err := manager.NewRedisCache(config.Redis)
+	util.ExitOnErr(ctx, err)
+	defer redisCache.Close()
+	nodeTTL := config.NodeTTL()
+	registry := manager.NewConnectionRegistry(redisCache, nodeTTL)
+
+	authenticator := gateway.NewTokenAuthenticator(manager.NewTokenVerifier(config.Auth))
+	wsGateway := gateway.NewGateway(ctx, pb.NewQuizServiceClient(clientConn), authenticator, registry, nodeID(), stats)
+	wg := &sync.WaitGroup{}
+	wsGateway.RunHeartbeat(ctx, wg, nodeTTL)
+	wsGateway.RunPush(
+		ctx, wg, redisCache.Client(), config.WSGateway.PushInterval, config.WSGateway.PushTopN, config.WSGateway.ServiceKey,
+	)
+
+	mux := http.NewServeMux()
+	mux.Handle("/ws", wsGateway)
+	srv := &http.Server{Addr: config.WSGateway.Listen, Handler: mux}
+	go func() {
+		log.Infof(ctx, "websocket gateway listener: %s", config.WSGateway.Listen)
+		if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+			log.Errorff(ctx, "ws_gateway|ListenAndServe|err:%v", err)
+			cancel()
+		}
+	}()
+
+	sigterm := make(chan os.Signal, 1)
+	signal.Notify(sigterm, syscall.SIGINT, syscall.SIGTERM)
+	select {
+	case <-ctx.Done():
+		log.Info(ctx, "terminating: context cancelled")
+	case <-sigterm:
+		log.Info(ctx, "terminating: via signal")
+	}
+	// hijacked websocket connections are not tracked by Shutdown, cancel closes them
+	cancel()
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer shutdownCancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Errorf(ctx, "Error shutting down server: %v", err)
+	}
+	wsGateway.Wait()
+	wg.Wait()
+}
+
+// nodeID is unique per process, so a restarted node doesn't inherit the connections of the crashed one
+func nodeID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%s", hostname, uuid.New().String()[:8])
+}